@@ -0,0 +1,91 @@
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/jobs"
+	"mygram-final-project/models"
+)
+
+// JobPurge adalah jenis job yang menghapus permanen seluruh data pengguna setelah masa tenggang penghapusan akun berakhir.
+const JobPurge = "account.purge"
+
+// PurgePayload adalah payload job JobPurge.
+type PurgePayload struct {
+	DeletionID int64 `json:"deletion_id"` // ID catatan AccountDeletion yang diproses
+}
+
+// ErrModerationHistory dikembalikan saat pengguna yang pernah mengambil tindakan moderasi meminta penghapusan akun.
+// Catatan tindakan moderasi tidak dapat dihapus, sehingga akun moderator tidak dapat dihapus permanen.
+var ErrModerationHistory = errors.New("akun yang pernah mengambil tindakan moderasi tidak dapat dihapus")
+
+// Schedule menjadwalkan penghapusan akun user setelah masa tenggang grace dan memasukkan job penghapusan ke antrean.
+// Jika akun sudah dijadwalkan untuk dihapus, catatan yang ada dikembalikan tanpa perubahan.
+func Schedule(tx *gorm.DB, user *models.User, grace time.Duration) (models.AccountDeletion, error) {
+	if grace <= 0 {
+		grace = models.DefaultAccountDeletionGrace
+	}
+
+	var deletion models.AccountDeletion
+	err := tx.Where("user_id = ? AND status = ?", user.ID, models.AccountDeletionScheduled).First(&deletion).Error
+	if err == nil {
+		return deletion, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return deletion, err
+	}
+
+	var actions int64
+	if err := tx.Model(&models.ModerationAction{}).Where("moderator_id = ?", user.ID).Count(&actions).Error; err != nil {
+		return deletion, err
+	}
+	if actions > 0 {
+		return deletion, ErrModerationHistory
+	}
+
+	// Waktu dibulatkan ke mikrodetik sesuai presisi PostgreSQL agar checksum catatan tetap sama setelah dibaca ulang
+	now := time.Now().Truncate(time.Microsecond)
+	deletion = models.AccountDeletion{
+		UserID:       user.ID,
+		EmailHash:    models.HashEmail(user.Email),
+		Status:       models.AccountDeletionScheduled,
+		RequestedAt:  now,
+		ScheduledFor: now.Add(grace),
+	}
+	if err := tx.Create(&deletion).Error; err != nil {
+		return deletion, err
+	}
+	if err := tx.Model(user).UpdateColumn("deletion_scheduled_for", deletion.ScheduledFor).Error; err != nil {
+		return deletion, err
+	}
+	user.DeletionScheduledFor = &deletion.ScheduledFor
+
+	return deletion, jobs.Enqueue(tx, JobPurge, PurgePayload{DeletionID: deletion.ID}, jobs.EnqueueOptions{
+		RunAt:     deletion.ScheduledFor,
+		UniqueKey: fmt.Sprintf("account-purge:%d", deletion.ID),
+	})
+}
+
+// Cancel membatalkan penghapusan akun user yang sedang dijadwalkan. Job penghapusan yang sudah ada di antrean
+// akan selesai tanpa menghapus data karena status catatannya bukan lagi scheduled.
+func Cancel(tx *gorm.DB, user *models.User) (bool, error) {
+	if !user.IsDeletionScheduled() {
+		return false, nil
+	}
+
+	now := time.Now()
+	if err := tx.Model(&models.AccountDeletion{}).
+		Where("user_id = ? AND status = ?", user.ID, models.AccountDeletionScheduled).
+		Updates(map[string]interface{}{"status": models.AccountDeletionCancelled, "cancelled_at": now}).Error; err != nil {
+		return false, err
+	}
+	if err := tx.Model(user).UpdateColumn("deletion_scheduled_for", nil).Error; err != nil {
+		return false, err
+	}
+	user.DeletionScheduledFor = nil
+	return true, nil
+}
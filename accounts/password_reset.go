@@ -0,0 +1,67 @@
+package accounts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/mail"
+	"mygram-final-project/models"
+)
+
+// JobSendPasswordReset adalah jenis job yang mengirim token reset password ke email pemilik akun.
+const JobSendPasswordReset = "account.password_reset_email"
+
+// PasswordResetPayload adalah payload job JobSendPasswordReset.
+type PasswordResetPayload struct {
+	PasswordResetID int64 `json:"password_reset_id"` // ID token reset yang dikirim
+}
+
+// NewResetToken membuat token reset password acak
+func NewResetToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SendPasswordReset membuat token baru untuk reset resetID lalu mengirimkannya ke email pemilik akun. Token mentah hanya
+// ada di email, sedangkan basis data menyimpan hash-nya. Reset yang sudah dipakai, kedaluwarsa, atau dihapus diabaikan.
+func SendPasswordReset(ctx context.Context, db *gorm.DB, sender mail.Sender, resetID int64) error {
+	var reset models.PasswordReset
+	err := db.Preload("User").First(&reset, resetID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if reset.UsedAt != nil || !reset.ExpiresAt.After(time.Now()) {
+		return nil
+	}
+
+	// Token diganti pada setiap percobaan sehingga hanya token dari email terakhir yang berlaku
+	token, err := NewResetToken()
+	if err != nil {
+		return err
+	}
+	result := db.Model(&models.PasswordReset{}).Where("id = ? AND used_at IS NULL", reset.ID).
+		UpdateColumn("token_hash", models.HashResetToken(token))
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	return sender.Send(ctx, mail.Message{
+		To:      reset.User.Email,
+		Subject: "Reset password akun MyGram",
+		Body: fmt.Sprintf("Halo %s,\n\nAdmin mewajibkan kamu mengganti password. Kirim token berikut bersama password baru ke "+
+			"POST /users/password/reset sebelum %s:\n\n%s\n\nSemua sesi kamu sudah diakhiri.",
+			reset.User.Username, reset.ExpiresAt.Format(time.RFC1123), token),
+	})
+}
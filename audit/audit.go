@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// Fields adalah nilai field objek yang dicatat sebelum atau sesudah perubahan.
+type Fields map[string]interface{}
+
+// Entry adalah tindakan yang akan dicatat di audit log.
+type Entry struct {
+	ActorID        *int64                 // ID pengguna yang melakukan tindakan, nil untuk proses sistem
+	ImpersonatorID *int64                 // ID admin yang bertindak atas nama ActorID melalui sesi impersonasi
+	Action         string                 // Jenis tindakan, lihat konstanta Audit* di package models
+	TargetType     string                 // Jenis objek yang dikenai tindakan
+	TargetID       *int64                 // ID objek yang dikenai tindakan
+	Before         Fields                 // Nilai field sebelum tindakan, nil untuk objek baru
+	After          Fields                 // Nilai field sesudah tindakan, nil untuk objek yang dihapus
+	Metadata       map[string]interface{} // Keterangan tambahan
+	IP             string                 // Alamat IP asal permintaan
+	RequestID      string                 // ID permintaan HTTP
+}
+
+// Change adalah perubahan satu field.
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Record menulis entry ke audit log. Hanya field yang nilainya berubah antara Before dan After yang disimpan,
+// dan nilai field data pribadi disamarkan sebelum disimpan.
+// Fungsi ini sebaiknya dipanggil dengan transaksi yang sama dengan perubahan data sehingga catatan hanya tersimpan jika perubahan berhasil.
+func Record(tx *gorm.DB, entry Entry) error {
+	log := models.AuditLog{
+		ActorID:        entry.ActorID,
+		ImpersonatorID: entry.ImpersonatorID,
+		Action:         entry.Action,
+		TargetType:     entry.TargetType,
+		TargetID:       entry.TargetID,
+		IP:             entry.IP,
+		RequestID:      entry.RequestID,
+		CreatedAt:      time.Now(),
+	}
+
+	if changes := Diff(entry.Before, entry.After); len(changes) > 0 {
+		redactChanges(changes)
+		data, err := json.Marshal(changes)
+		if err != nil {
+			return err
+		}
+		log.Changes = string(data)
+	}
+	if len(entry.Metadata) > 0 {
+		data, err := json.Marshal(entry.Metadata)
+		if err != nil {
+			return err
+		}
+		log.Metadata = string(data)
+	}
+
+	return tx.Create(&log).Error
+}
+
+// Diff membandingkan before dan after lalu mengembalikan field yang nilainya berbeda.
+// Field yang hanya ada di salah satu sisi dianggap berubah dari atau menjadi nil.
+func Diff(before Fields, after Fields) map[string]Change {
+	changes := map[string]Change{}
+	for field, value := range before {
+		if next, ok := after[field]; !ok || !reflect.DeepEqual(value, next) {
+			changes[field] = Change{Before: value, After: after[field]}
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = Change{Before: nil, After: value}
+		}
+	}
+	return changes
+}
+
+// ID mengembalikan pointer ke id untuk field ActorID dan TargetID.
+func ID(id int64) *int64 {
+	return &id
+}
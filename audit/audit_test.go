@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+
+	"mygram-final-project/models"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before Fields
+		after  Fields
+		want   map[string]Change
+	}{
+		{"tidak berubah", Fields{"role": "user", "age": 20}, Fields{"role": "user", "age": 20}, map[string]Change{}},
+		{"nilai berubah", Fields{"role": "user"}, Fields{"role": "admin"}, map[string]Change{"role": {"user", "admin"}}},
+		{"field dihapus", Fields{"role": "user", "banned": true}, Fields{"role": "user"}, map[string]Change{"banned": {true, nil}}},
+		{"field ditambahkan", Fields{}, Fields{"banned": true}, map[string]Change{"banned": {nil, true}}},
+		{"sebelumnya kosong", nil, Fields{"role": "user"}, map[string]Change{"role": {nil, "user"}}},
+		{"slice dibandingkan isinya", Fields{"events": []string{"a"}}, Fields{"events": []string{"a"}}, map[string]Change{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff = %v, ingin %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactChanges(t *testing.T) {
+	emailHash := models.HashEmail("budi@example.com")
+
+	tests := []struct {
+		name         string
+		changes      map[string]Change
+		want         map[string]Change
+		wantRedacted bool
+	}{
+		{
+			"field biasa tidak diubah",
+			map[string]Change{"role": {"user", "admin"}},
+			map[string]Change{"role": {"user", "admin"}},
+			false,
+		},
+		{
+			"email disimpan sebagai hash",
+			map[string]Change{"email": {"budi@example.com", nil}},
+			map[string]Change{"email": {emailHash, nil}},
+			true,
+		},
+		{
+			"konten disamarkan",
+			map[string]Change{"caption": {"lama", "baru"}, "bio": {"", "halo"}},
+			map[string]Change{"caption": {Redacted, Redacted}, "bio": {"", Redacted}},
+			true,
+		},
+		{
+			"sudah disamarkan",
+			map[string]Change{"caption": {Redacted, Redacted}, "email": {emailHash, ""}},
+			map[string]Change{"caption": {Redacted, Redacted}, "email": {emailHash, ""}},
+			false,
+		},
+		{
+			"email bukan string",
+			map[string]Change{"email": {nil, 12}},
+			map[string]Change{"email": {nil, Redacted}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redacted := redactChanges(tt.changes)
+			if redacted != tt.wantRedacted {
+				t.Errorf("redactChanges = %v, ingin %v", redacted, tt.wantRedacted)
+			}
+			if !reflect.DeepEqual(tt.changes, tt.want) {
+				t.Errorf("changes = %v, ingin %v", tt.changes, tt.want)
+			}
+		})
+	}
+}
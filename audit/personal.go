@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// Redacted adalah nilai pengganti data pribadi yang disimpan di audit log.
+const Redacted = "[redacted]"
+
+// personalFields adalah field yang berisi data pribadi atau konten buatan pengguna. Audit log tidak dapat diubah dan tetap
+// disimpan setelah akun dihapus permanen, sehingga nilainya tidak pernah disimpan apa adanya: email disimpan sebagai hash
+// seperti pada login gagal, sedangkan field lain hanya dicatat bahwa nilainya berubah.
+var personalFields = map[string]func(value interface{}) interface{}{
+	"email":             hashEmail,
+	"birthdate":         redact,
+	"profile_image_url": redact,
+	"display_name":      redact,
+	"bio":               redact,
+	"website":           redact,
+	"pronouns":          redact,
+	"location":          redact,
+	"title":             redact,
+	"caption":           redact,
+	"photo_url":         redact,
+	"message":           redact,
+	"social_media_url":  redact,
+}
+
+// redact mengganti nilai yang tidak kosong dengan Redacted
+func redact(value interface{}) interface{} {
+	if value == nil || value == "" || value == Redacted {
+		return value
+	}
+	return Redacted
+}
+
+// hashEmail mengganti email dengan hash-nya. Nilai yang sudah berupa hash dibiarkan.
+func hashEmail(value interface{}) interface{} {
+	email, ok := value.(string)
+	if !ok {
+		return redact(value)
+	}
+	if email == "" || !strings.Contains(email, "@") {
+		return email
+	}
+	return models.HashEmail(email)
+}
+
+// redactChanges mengganti nilai field data pribadi pada changes dan melaporkan apakah ada nilai yang diganti
+func redactChanges(changes map[string]Change) bool {
+	redacted := false
+	for field, change := range changes {
+		protect, ok := personalFields[field]
+		if !ok {
+			continue
+		}
+		next := Change{Before: protect(change.Before), After: protect(change.After)}
+		if !reflect.DeepEqual(next, change) {
+			changes[field] = next
+			redacted = true
+		}
+	}
+	return redacted
+}
+
+// RedactExisting mengganti data pribadi pada catatan audit log yang ditulis sebelum nilainya disamarkan, lalu
+// mengembalikan jumlah catatan yang diubah. Catatan diperbarui dengan SQL langsung karena model AuditLog menolak perubahan.
+func RedactExisting(db *gorm.DB) (int, error) {
+	fields := make([]string, 0, len(personalFields))
+	for field := range personalFields {
+		fields = append(fields, field)
+	}
+
+	var logs []models.AuditLog
+	updated := 0
+	err := db.Select("id", "changes").
+		Where("changes <> '' AND EXISTS (SELECT 1 FROM jsonb_object_keys(changes::jsonb) AS field WHERE field IN ?)", fields).
+		FindInBatches(&logs, 500, func(tx *gorm.DB, batch int) error {
+			for _, log := range logs {
+				var changes map[string]Change
+				if err := json.Unmarshal([]byte(log.Changes), &changes); err != nil {
+					return err
+				}
+				if !redactChanges(changes) {
+					continue
+				}
+				data, err := json.Marshal(changes)
+				if err != nil {
+					return err
+				}
+				if err := db.Exec("UPDATE audit_logs SET changes = ? WHERE id = ?", string(data), log.ID).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		}).Error
+	return updated, err
+}
@@ -0,0 +1,300 @@
+package background
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/accounts"
+	"mygram-final-project/audit"
+	"mygram-final-project/events"
+	"mygram-final-project/jobs"
+	"mygram-final-project/mail"
+	"mygram-final-project/media"
+	"mygram-final-project/models"
+	"mygram-final-project/storage"
+)
+
+// registerAccountPurge mendaftarkan job penghapusan permanen akun yang masa tenggangnya sudah berakhir.
+// Berkas milik pengguna di store dihapus setelah transaksi penghapusan data di-commit.
+func registerAccountPurge(worker *jobs.Worker, DB *gorm.DB, store storage.Store) {
+	jobs.Register(worker, accounts.JobPurge, func(ctx context.Context, payload accounts.PurgePayload) error {
+		var fileKeys []string
+		err := DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var err error
+			fileKeys, err = purgeAccount(tx, payload.DeletionID, time.Now())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		// Data di database sudah terhapus, sehingga kegagalan menghapus berkas hanya dicatat agar dapat dibersihkan manual
+		for _, key := range fileKeys {
+			if err := store.Delete(ctx, key); err != nil {
+				log.Printf("background: gagal menghapus berkas %s milik akun yang dihapus: %v", key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// registerPasswordResetEmail mendaftarkan job pengiriman token reset password ke email pemilik akun melalui sender
+func registerPasswordResetEmail(worker *jobs.Worker, DB *gorm.DB, sender mail.Sender) {
+	jobs.Register(worker, accounts.JobSendPasswordReset, func(ctx context.Context, payload accounts.PasswordResetPayload) error {
+		return accounts.SendPasswordReset(ctx, DB.WithContext(ctx), sender, payload.PasswordResetID)
+	})
+}
+
+// purgeAccount menghapus permanen seluruh data pengguna pada catatan deletionID, memastikan tidak ada data yang tersisa,
+// lalu menandai catatan sebagai selesai beserta ringkasan dan checksum-nya. Key berkas milik pengguna (arsip ekspor data
+// dan avatar) dikembalikan untuk dihapus dari penyimpanan. Penghapusan yang sudah dibatalkan, sudah selesai, atau belum jatuh tempo diabaikan.
+func purgeAccount(tx *gorm.DB, deletionID int64, now time.Time) ([]string, error) {
+	var deletion models.AccountDeletion
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&deletion, deletionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deletion.Status != models.AccountDeletionScheduled || deletion.ScheduledFor.After(now) {
+		return nil, nil
+	}
+
+	// Login yang membatalkan penghapusan mengosongkan jadwal pada pengguna dalam transaksi yang sama dengan catatannya
+	var user models.User
+	err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, deletion.UserID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil && !user.IsDeletionScheduled() {
+		return nil, nil
+	}
+
+	var fileKeys []string
+	if err := tx.Model(&models.DataExport{}).Where("user_id = ? AND file_key <> ?", deletion.UserID, "").
+		Pluck("file_key", &fileKeys).Error; err != nil {
+		return nil, err
+	}
+	fileKeys = append(fileKeys, media.AvatarKeys(user.AvatarKey)...)
+
+	summary, err := purgeUserData(tx, deletion.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyUserPurged(tx, deletion.UserID); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return nil, err
+	}
+	completedAt := now.Truncate(time.Microsecond)
+	deletion.Status = models.AccountDeletionCompleted
+	deletion.CompletedAt = &completedAt
+	deletion.Summary = string(data)
+	deletion.Checksum = deletion.ComputeChecksum()
+	if err := tx.Save(&deletion).Error; err != nil {
+		return nil, err
+	}
+	log.Printf("background: akun pengguna %d dihapus permanen", deletion.UserID)
+
+	// Penghapusan dilakukan oleh sistem sehingga catatan audit tidak memiliki aktor
+	if err := audit.Record(tx, audit.Entry{
+		Action:     models.AuditUserPurged,
+		TargetType: models.TargetUser,
+		TargetID:   audit.ID(deletion.UserID),
+		Metadata:   map[string]interface{}{"account_deletion_id": deletion.ID, "checksum": deletion.Checksum},
+	}); err != nil {
+		return nil, err
+	}
+
+	return fileKeys, events.Record(tx, events.Event{
+		Type:          events.UserDeleted,
+		AggregateType: models.TargetUser,
+		AggregateID:   deletion.UserID,
+		ActorID:       deletion.UserID,
+		Data:          map[string]interface{}{"id": deletion.UserID, "username": user.Username},
+	})
+}
+
+// userOwnedData adalah data milik pengguna yang dihapus langsung berdasarkan kondisi userID, setelah data dengan turunan
+// atau penghitung dihapus oleh purgeUserData. Daftar yang sama dipakai untuk memastikan tidak ada data yang tersisa.
+var userOwnedData = []struct {
+	name      string
+	model     interface{}
+	condition string
+}{
+	{"social_medias", &models.SocialMedia{}, "user_id = @user"},
+	{"mentions", &models.Mention{}, "mentioned_user_id = @user"},
+	{"follows", &models.Follow{}, "follower_id = @user OR following_id = @user"},
+	{"follow_requests", &models.FollowRequest{}, "requester_id = @user OR target_id = @user"},
+	{"blocks", &models.Block{}, "blocker_id = @user OR blocked_id = @user"},
+	{"mutes", &models.Mute{}, "muter_id = @user OR muted_id = @user"},
+	{"notification_actors", &models.NotificationActor{}, "actor_id = @user"},
+	{"notifications", &models.Notification{}, "user_id = @user OR actor_id = @user"},
+	{"notification_preferences", &models.NotificationPreference{}, "user_id = @user"},
+	{"story_views", &models.StoryView{}, "viewer_id = @user"},
+	{"stories", &models.Story{}, "user_id = @user"},
+	{"messages", &models.Message{}, "sender_id = @user"},
+	{"conversation_members", &models.ConversationMember{}, "user_id = @user"},
+	{"reports", &models.Report{}, "reporter_id = @user"},
+	{"webhook_subscriptions", &models.WebhookSubscription{}, "user_id = @user"},
+	{"data_exports", &models.DataExport{}, "user_id = @user"},
+	{"password_resets", &models.PasswordReset{}, "user_id = @user"},
+}
+
+// purgeUserData menghapus permanen seluruh data milik userID secara eksplisit tanpa bergantung pada constraint OnDelete:CASCADE,
+// lalu mengembalikan jumlah baris yang dihapus per jenis data
+func purgeUserData(tx *gorm.DB, userID int64) (map[string]int64, error) {
+	summary := map[string]int64{}
+	user := map[string]interface{}{"user": userID}
+
+	var photoIDs []int64
+	if err := tx.Unscoped().Model(&models.Photo{}).Where("user_id = ?", userID).Pluck("id", &photoIDs).Error; err != nil {
+		return nil, err
+	}
+	photos, err := purgePhotos(tx, photoIDs)
+	if err != nil {
+		return nil, err
+	}
+	summary["photos"] = photos
+
+	comments, err := purgeUserComments(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	summary["comments"] = comments
+
+	// Like pengguna dihapus bersama penyesuaian jumlah like pada foto dan komentar yang disukainya
+	if err := tx.Unscoped().Model(&models.Photo{}).
+		Where("id IN (?)", tx.Model(&models.PhotoLike{}).Select("photo_id").Where("user_id = ?", userID)).
+		UpdateColumn("like_count", gorm.Expr("GREATEST(like_count - 1, 0)")).Error; err != nil {
+		return nil, err
+	}
+	result := tx.Where("user_id = ?", userID).Delete(&models.PhotoLike{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	summary["photo_likes"] = result.RowsAffected
+
+	if err := tx.Unscoped().Model(&models.Comment{}).
+		Where("id IN (?)", tx.Model(&models.CommentLike{}).Select("comment_id").Where("user_id = ?", userID)).
+		UpdateColumn("like_count", gorm.Expr("GREATEST(like_count - 1, 0)")).Error; err != nil {
+		return nil, err
+	}
+	result = tx.Where("user_id = ?", userID).Delete(&models.CommentLike{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	summary["comment_likes"] = result.RowsAffected
+
+	// Turunan notifikasi, story, dan webhook milik pengguna dihapus sebelum induknya
+	if err := tx.Where("notification_id IN (?)", tx.Model(&models.Notification{}).Select("id").Where("user_id = ? OR actor_id = ?", userID, userID)).
+		Delete(&models.NotificationActor{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("story_id IN (?)", tx.Model(&models.Story{}).Select("id").Where("user_id = ?", userID)).
+		Delete(&models.StoryView{}).Error; err != nil {
+		return nil, err
+	}
+	subscriptions := tx.Model(&models.WebhookSubscription{}).Select("id").Where("user_id = ?", userID)
+	if err := tx.Where("delivery_id IN (?)", tx.Model(&models.WebhookDelivery{}).Select("id").Where("subscription_id IN (?)", subscriptions)).
+		Delete(&models.WebhookDeliveryAttempt{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("subscription_id IN (?)", subscriptions).Delete(&models.WebhookDelivery{}).Error; err != nil {
+		return nil, err
+	}
+
+	for _, data := range userOwnedData {
+		result := tx.Unscoped().Where(data.condition, user).Delete(data.model)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		summary[data.name] += result.RowsAffected
+	}
+
+	result = tx.Unscoped().Where("id = ?", userID).Delete(&models.User{})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	summary["users"] = result.RowsAffected
+	return summary, nil
+}
+
+// purgeUserComments menghapus permanen komentar userID, dimulai dari balasan terdalam. Balasan pengguna lain pada komentar
+// yang dihapus dinaikkan satu tingkat ke induk komentar tersebut sehingga tetap tampil di thread.
+func purgeUserComments(tx *gorm.DB, userID int64) (int64, error) {
+	var comments []models.Comment
+	if err := tx.Unscoped().Select("id", "parent_id").Where("user_id = ?", userID).Order("depth DESC").Find(&comments).Error; err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, comment := range comments {
+		var replyIDs []int64
+		if err := tx.Unscoped().Model(&models.Comment{}).Where("parent_id = ?", comment.ID).Pluck("id", &replyIDs).Error; err != nil {
+			return 0, err
+		}
+		if len(replyIDs) > 0 {
+			if err := tx.Unscoped().Model(&models.Comment{}).Where("parent_id IN ?", replyIDs).
+				UpdateColumn("depth", gorm.Expr("depth - 1")).Error; err != nil {
+				return 0, err
+			}
+			if err := tx.Unscoped().Model(&models.Comment{}).Where("id IN ?", replyIDs).
+				UpdateColumns(map[string]interface{}{"parent_id": comment.ParentID, "depth": gorm.Expr("depth - 1")}).Error; err != nil {
+				return 0, err
+			}
+		}
+		if comment.ParentID != nil {
+			if err := tx.Unscoped().Model(&models.Comment{}).Where("id = ?", *comment.ParentID).
+				UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count + ? - 1, 0)", len(replyIDs))).Error; err != nil {
+				return 0, err
+			}
+		}
+
+		deleted, err := purgeComments(tx, []int64{comment.ID})
+		if err != nil {
+			return 0, err
+		}
+		total += deleted
+	}
+	return total, nil
+}
+
+// verifyUserPurged memastikan tidak ada lagi data yang merujuk ke userID. Error membatalkan transaksi penghapusan
+// sehingga catatan tidak pernah ditandai selesai selama masih ada data yang tersisa.
+func verifyUserPurged(tx *gorm.DB, userID int64) error {
+	user := map[string]interface{}{"user": userID}
+	checks := append([]struct {
+		name      string
+		model     interface{}
+		condition string
+	}{
+		{"users", &models.User{}, "id = @user"},
+		{"photos", &models.Photo{}, "user_id = @user"},
+		{"comments", &models.Comment{}, "user_id = @user"},
+		{"photo_likes", &models.PhotoLike{}, "user_id = @user"},
+		{"comment_likes", &models.CommentLike{}, "user_id = @user"},
+	}, userOwnedData...)
+
+	for _, check := range checks {
+		var remaining int64
+		if err := tx.Unscoped().Model(check.model).Where(check.condition, user).Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return fmt.Errorf("penghapusan akun %d belum lengkap: %d baris %s tersisa", userID, remaining, check.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package background
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/events"
+	"mygram-final-project/exports"
+	"mygram-final-project/jobs"
+	"mygram-final-project/mail"
+	"mygram-final-project/notifications"
+	"mygram-final-project/storage"
+	"mygram-final-project/webhooks"
+)
+
+// Processes berisi semua proses latar belakang aplikasi: dispatcher domain event, pengiriman webhook, dan antrean job.
+// Proses ini dapat dijalankan di dalam server atau sebagai perintah worker terpisah.
+type Processes struct {
+	Events   *events.Dispatcher
+	Webhooks *webhooks.Dispatcher
+	Jobs     *jobs.Worker
+}
+
+// Options adalah pengaturan proses latar belakang.
+type Options struct {
+	Concurrency    int           // Jumlah job yang dijalankan bersamaan, 0 berarti bawaan worker
+	TrashRetention time.Duration // Lama konten yang dihapus disimpan sebelum dihapus permanen
+	Storage        storage.Store // Penyimpanan berkas untuk arsip ekspor data
+	ExportTTL      time.Duration // Masa berlaku arsip ekspor data
+	Mailer         mail.Sender   // Pengirim email ke pengguna, kosong berarti email hanya ditulis ke log
+}
+
+// New membuat semua proses latar belakang dan mendaftarkan handler serta jadwalnya.
+func New(DB *gorm.DB, options Options) *Processes {
+	p := &Processes{
+		Events:   events.NewDispatcher(DB),
+		Webhooks: webhooks.NewDispatcher(DB),
+		Jobs:     jobs.NewWorker(DB),
+	}
+	if options.Concurrency > 0 {
+		p.Jobs.Concurrency = options.Concurrency
+	}
+
+	// Daftarkan subsistem yang bereaksi terhadap domain event dari outbox
+	notifications.Register(p.Events, DB)
+	webhooks.Register(p.Events, DB)
+
+	// Daftarkan job dan jadwal berulang
+	registerCleanup(p.Jobs, DB)
+	registerTrashPurge(p.Jobs, DB, options.TrashRetention)
+	registerAccountPurge(p.Jobs, DB, options.Storage)
+	if options.Mailer == nil {
+		options.Mailer = mail.Log{}
+	}
+	registerPasswordResetEmail(p.Jobs, DB, options.Mailer)
+	exports.Register(p.Jobs, DB, options.Storage, options.ExportTTL)
+
+	return p
+}
+
+// Run menjalankan semua proses sampai ctx dibatalkan.
+func (p *Processes) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, run := range []func(context.Context){p.Events.Run, p.Webhooks.Run, p.Jobs.Run} {
+		wg.Add(1)
+		go func(run func(context.Context)) {
+			defer wg.Done()
+			run(ctx)
+		}(run)
+	}
+
+	log.Printf("background: worker %s berjalan dengan %d job bersamaan", p.Jobs.ID, p.Jobs.Concurrency)
+	wg.Wait()
+}
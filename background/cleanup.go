@@ -0,0 +1,73 @@
+package background
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/jobs"
+	"mygram-final-project/models"
+)
+
+// Jenis job pembersihan data lama.
+const (
+	JobCleanupOutbox   = "cleanup.outbox"
+	JobCleanupWebhooks = "cleanup.webhook_deliveries"
+	JobCleanupJobs     = "cleanup.jobs"
+	JobCleanupStories  = "cleanup.stories"
+)
+
+// Lama penyimpanan data yang sudah selesai diproses sebelum dihapus.
+const (
+	outboxRetention  = 7 * 24 * time.Hour
+	webhookRetention = 30 * 24 * time.Hour
+	jobRetention     = 7 * 24 * time.Hour
+)
+
+// cleanupPayload adalah payload job pembersihan
+type cleanupPayload struct{}
+
+// registerCleanup mendaftarkan job pembersihan berkala. Job dead tidak dihapus agar dapat diperiksa dan dijalankan ulang.
+func registerCleanup(worker *jobs.Worker, DB *gorm.DB) {
+	jobs.Register(worker, JobCleanupOutbox, func(ctx context.Context, _ cleanupPayload) error {
+		return deleteOlderThan(DB.WithContext(ctx), "outbox events", &models.OutboxEvent{},
+			"processed_at IS NOT NULL AND processed_at < ?", time.Now().Add(-outboxRetention))
+	})
+	jobs.Register(worker, JobCleanupWebhooks, func(ctx context.Context, _ cleanupPayload) error {
+		return deleteOlderThan(DB.WithContext(ctx), "webhook deliveries", &models.WebhookDelivery{},
+			"status <> ? AND updated_at < ?", models.WebhookDeliveryPending, time.Now().Add(-webhookRetention))
+	})
+	jobs.Register(worker, JobCleanupJobs, func(ctx context.Context, _ cleanupPayload) error {
+		return deleteOlderThan(DB.WithContext(ctx), "jobs", &models.Job{},
+			"status = ? AND completed_at < ?", models.JobSucceeded, time.Now().Add(-jobRetention))
+	})
+	jobs.Register(worker, JobCleanupStories, func(ctx context.Context, _ cleanupPayload) error {
+		// Story kedaluwarsa sudah tidak ditampilkan, sehingga dapat langsung dihapus beserta data penontonnya
+		return deleteOlderThan(DB.WithContext(ctx), "expired stories", &models.Story{},
+			"expires_at < ?", time.Now())
+	})
+
+	mustSchedule(worker, "cleanup-outbox", "15 3 * * *", JobCleanupOutbox)
+	mustSchedule(worker, "cleanup-webhooks", "30 3 * * *", JobCleanupWebhooks)
+	mustSchedule(worker, "cleanup-jobs", "45 3 * * *", JobCleanupJobs)
+	mustSchedule(worker, "cleanup-stories", "*/10 * * * *", JobCleanupStories)
+}
+
+// deleteOlderThan menghapus baris model yang memenuhi condition lalu mencatat jumlahnya
+func deleteOlderThan(db *gorm.DB, name string, model interface{}, condition string, args ...interface{}) error {
+	result := db.Where(condition, args...).Delete(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	log.Printf("background: %d %s lama dihapus", result.RowsAffected, name)
+	return nil
+}
+
+// mustSchedule mendaftarkan jadwal cron dan panic jika jadwal tidak valid
+func mustSchedule(worker *jobs.Worker, name string, spec string, jobType string) {
+	if err := worker.Schedule(name, spec, jobType, cleanupPayload{}); err != nil {
+		panic(err)
+	}
+}
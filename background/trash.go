@@ -0,0 +1,108 @@
+package background
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/jobs"
+	"mygram-final-project/models"
+)
+
+// JobPurgeTrash adalah jenis job yang menghapus permanen konten di tempat sampah yang sudah melewati masa retensi.
+const JobPurgeTrash = "cleanup.trash"
+
+// registerTrashPurge mendaftarkan job penghapusan permanen isi tempat sampah dengan masa retensi retention
+func registerTrashPurge(worker *jobs.Worker, DB *gorm.DB, retention time.Duration) {
+	if retention <= 0 {
+		retention = models.DefaultTrashRetention
+	}
+
+	jobs.Register(worker, JobPurgeTrash, func(ctx context.Context, _ cleanupPayload) error {
+		return DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return purgeTrash(tx, time.Now().Add(-retention))
+		})
+	})
+
+	mustSchedule(worker, "cleanup-trash", "0 4 * * *", JobPurgeTrash)
+}
+
+// purgeTrash menghapus permanen foto, komentar, dan media sosial yang dihapus sebelum cutoff beserta data turunannya.
+// Tombstone komentar tetap dipertahankan sebagai penanda thread, tetapi pesannya dikosongkan.
+func purgeTrash(tx *gorm.DB, cutoff time.Time) error {
+	var photoIDs []int64
+	if err := tx.Unscoped().Model(&models.Photo{}).Where("deleted_at < ?", cutoff).Pluck("id", &photoIDs).Error; err != nil {
+		return err
+	}
+	photos, err := purgePhotos(tx, photoIDs)
+	if err != nil {
+		return err
+	}
+	log.Printf("background: %d foto di tempat sampah dihapus", photos)
+
+	var commentIDs []int64
+	if err := tx.Unscoped().Model(&models.Comment{}).Where("deleted_at < ?", cutoff).Pluck("id", &commentIDs).Error; err != nil {
+		return err
+	}
+	comments, err := purgeComments(tx, commentIDs)
+	if err != nil {
+		return err
+	}
+	log.Printf("background: %d komentar di tempat sampah dihapus", comments)
+
+	result := tx.Model(&models.Comment{}).
+		Where("tombstoned = ? AND tombstoned_at < ? AND message <> ?", true, cutoff, "").
+		UpdateColumn("message", "")
+	if result.Error != nil {
+		return result.Error
+	}
+	log.Printf("background: %d pesan tombstone komentar dihapus", result.RowsAffected)
+
+	return deleteOlderThan(tx.Unscoped(), "media sosial di tempat sampah", &models.SocialMedia{}, "deleted_at < ?", cutoff)
+}
+
+// purgePhotos menghapus permanen foto photoIDs beserta komentar, like, dan mention-nya, lalu mengembalikan jumlah foto yang dihapus.
+// Pesan yang membagikan foto tersebut tetap disimpan tanpa lampiran.
+func purgePhotos(tx *gorm.DB, photoIDs []int64) (int64, error) {
+	if len(photoIDs) == 0 {
+		return 0, nil
+	}
+
+	var commentIDs []int64
+	if err := tx.Unscoped().Model(&models.Comment{}).Where("photo_id IN ?", photoIDs).Pluck("id", &commentIDs).Error; err != nil {
+		return 0, err
+	}
+	if _, err := purgeComments(tx, commentIDs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Where("photo_id IN ?", photoIDs).Delete(&models.PhotoLike{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Where("source_type = ? AND source_id IN ?", models.MentionSourcePhoto, photoIDs).Delete(&models.Mention{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&models.Message{}).Where("photo_id IN ?", photoIDs).UpdateColumn("photo_id", nil).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Unscoped().Where("id IN ?", photoIDs).Delete(&models.Photo{})
+	return result.RowsAffected, result.Error
+}
+
+// purgeComments menghapus permanen komentar commentIDs beserta like dan mention-nya, lalu mengembalikan jumlah komentar yang dihapus
+func purgeComments(tx *gorm.DB, commentIDs []int64) (int64, error) {
+	if len(commentIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := tx.Where("comment_id IN ?", commentIDs).Delete(&models.CommentLike{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Where("source_type = ? AND source_id IN ?", models.MentionSourceComment, commentIDs).Delete(&models.Mention{}).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Unscoped().Where("id IN ?", commentIDs).Delete(&models.Comment{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,92 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+)
+
+// Decision adalah keputusan filter atas sebuah konten.
+type Decision int
+
+// Keputusan filter, diurutkan dari yang paling ringan. Pipeline mengambil keputusan paling berat dari seluruh filter.
+const (
+	Allow  Decision = iota // Konten langsung dipublikasikan
+	Hold                   // Konten ditahan sampai ditinjau moderator
+	Reject                 // Konten ditolak
+)
+
+// String mengembalikan nama keputusan.
+func (d Decision) String() string {
+	switch d {
+	case Allow:
+		return "allow"
+	case Hold:
+		return "hold"
+	case Reject:
+		return "reject"
+	default:
+		return fmt.Sprintf("decision(%d)", int(d))
+	}
+}
+
+// Input adalah konten yang diperiksa beserta konteksnya.
+type Input struct {
+	UserID    int64  // ID penulis konten
+	PhotoID   int64  // ID foto tempat komentar ditulis
+	CommentID int64  // ID komentar yang sedang diedit, 0 untuk komentar baru
+	Text      string // Isi konten
+}
+
+// Result adalah hasil pemeriksaan sebuah filter atau pipeline.
+type Result struct {
+	Decision Decision
+	Reasons  []string // Alasan keputusan selain Allow
+}
+
+// Filter memeriksa konten dan mengembalikan keputusan. Error diperlakukan pipeline sebagai Hold.
+type Filter interface {
+	Name() string
+	Check(ctx context.Context, input Input) (Result, error)
+}
+
+// Pipeline menjalankan beberapa filter secara berurutan dan menggabungkan hasilnya.
+type Pipeline struct {
+	filters []Filter
+}
+
+// NewPipeline membuat pipeline dari filters. Filter bernilai nil diabaikan.
+func NewPipeline(filters ...Filter) *Pipeline {
+	pipeline := &Pipeline{}
+	for _, filter := range filters {
+		if filter != nil {
+			pipeline.filters = append(pipeline.filters, filter)
+		}
+	}
+	return pipeline
+}
+
+// Check menjalankan seluruh filter dan mengembalikan keputusan paling berat beserta alasan dari filter yang menghasilkannya.
+// Pemeriksaan berhenti pada keputusan Reject pertama.
+func (p *Pipeline) Check(ctx context.Context, input Input) Result {
+	result := Result{Decision: Allow}
+	if p == nil {
+		return result
+	}
+
+	for _, filter := range p.filters {
+		current, err := filter.Check(ctx, input)
+		if err != nil {
+			current = Result{Decision: Hold, Reasons: []string{fmt.Sprintf("%s: pemeriksaan gagal", filter.Name())}}
+		}
+		if current.Decision > result.Decision {
+			result = Result{Decision: current.Decision}
+		}
+		if current.Decision == result.Decision && current.Decision != Allow {
+			result.Reasons = append(result.Reasons, current.Reasons...)
+		}
+		if result.Decision == Reject {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,164 @@
+package contentfilter
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// WordListFilter menolak konten yang mengandung kata terlarang dan menahan konten yang mengandung kata yang perlu ditinjau.
+// Pencocokan tidak membedakan huruf besar-kecil dan berlaku per kata, sehingga "kelas" tidak cocok dengan "las".
+// Entri yang terdiri dari beberapa kata dicocokkan sebagai frasa.
+type WordListFilter struct {
+	Blocked []string // Kata atau frasa yang membuat konten ditolak
+	Review  []string // Kata atau frasa yang membuat konten ditahan
+}
+
+// Name mengembalikan nama filter.
+func (f WordListFilter) Name() string { return "word_list" }
+
+// Check memeriksa konten terhadap daftar kata.
+func (f WordListFilter) Check(_ context.Context, input Input) (Result, error) {
+	text := " " + strings.Join(words(input.Text), " ") + " "
+	if word, ok := containsAny(text, f.Blocked); ok {
+		return Result{Decision: Reject, Reasons: []string{"mengandung kata terlarang: " + word}}, nil
+	}
+	if word, ok := containsAny(text, f.Review); ok {
+		return Result{Decision: Hold, Reasons: []string{"mengandung kata yang perlu ditinjau: " + word}}, nil
+	}
+	return Result{Decision: Allow}, nil
+}
+
+// linkPattern mencocokkan tautan dengan skema http(s) atau yang diawali www.
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+
+// SpamFilter menerapkan heuristik spam: jumlah tautan, domain terlarang, karakter berulang, dan huruf kapital berlebihan.
+type SpamFilter struct {
+	MaxLinks       int      // Batas jumlah tautan sebelum konten ditahan, 0 berarti tautan tidak dibatasi
+	BlockedDomains []string // Domain yang membuat konten ditolak, termasuk subdomainnya
+}
+
+// Name mengembalikan nama filter.
+func (f SpamFilter) Name() string { return "spam" }
+
+// Check memeriksa konten dengan heuristik spam.
+func (f SpamFilter) Check(_ context.Context, input Input) (Result, error) {
+	links := linkPattern.FindAllString(input.Text, -1)
+	for _, link := range links {
+		if domain, ok := f.blockedDomain(link); ok {
+			return Result{Decision: Reject, Reasons: []string{"mengandung tautan ke domain terlarang: " + domain}}, nil
+		}
+	}
+
+	var reasons []string
+	if f.MaxLinks > 0 && len(links) > f.MaxLinks {
+		reasons = append(reasons, "terlalu banyak tautan")
+	}
+	if hasRepeatedRune(input.Text, 10) {
+		reasons = append(reasons, "mengandung karakter berulang")
+	}
+	if isShouting(input.Text) {
+		reasons = append(reasons, "didominasi huruf kapital")
+	}
+	if len(reasons) > 0 {
+		return Result{Decision: Hold, Reasons: reasons}, nil
+	}
+	return Result{Decision: Allow}, nil
+}
+
+// blockedDomain mengembalikan domain terlarang yang cocok dengan host dari link
+func (f SpamFilter) blockedDomain(link string) (string, bool) {
+	if strings.HasPrefix(strings.ToLower(link), "www.") {
+		link = "http://" + link
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, domain := range f.BlockedDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return domain, true
+		}
+	}
+	return "", false
+}
+
+// Classifier adalah pengklasifikasi konten eksternal, misalnya model machine learning atau layanan moderasi pihak ketiga.
+// Classify mengembalikan keputusan dan alasan singkat.
+type Classifier interface {
+	Classify(ctx context.Context, text string) (Decision, string, error)
+}
+
+// ClassifierFilter menjadikan Classifier sebagai Filter pada pipeline.
+type ClassifierFilter struct {
+	Classifier Classifier
+}
+
+// Name mengembalikan nama filter.
+func (f ClassifierFilter) Name() string { return "classifier" }
+
+// Check meneruskan konten ke Classifier.
+func (f ClassifierFilter) Check(ctx context.Context, input Input) (Result, error) {
+	decision, reason, err := f.Classifier.Classify(ctx, input.Text)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Decision: decision}
+	if decision != Allow && reason != "" {
+		result.Reasons = []string{reason}
+	}
+	return result, nil
+}
+
+// words memecah text menjadi kata-kata berhuruf kecil
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsAny mengembalikan entri list pertama yang muncul sebagai kata atau frasa utuh di dalam text yang sudah dinormalisasi
+func containsAny(text string, list []string) (string, bool) {
+	for _, entry := range list {
+		normalized := strings.Join(words(entry), " ")
+		if normalized != "" && strings.Contains(text, " "+normalized+" ") {
+			return strings.TrimSpace(entry), true
+		}
+	}
+	return "", false
+}
+
+// hasRepeatedRune menentukan apakah text mengandung karakter yang sama sebanyak minimal n kali berturut-turut
+func hasRepeatedRune(text string, n int) bool {
+	var last rune
+	count := 0
+	for _, r := range text {
+		if r == last && !unicode.IsSpace(r) {
+			count++
+		} else {
+			last, count = r, 1
+		}
+		if count >= n {
+			return true
+		}
+	}
+	return false
+}
+
+// isShouting menentukan apakah text yang cukup panjang didominasi huruf kapital
+func isShouting(text string) bool {
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	return letters >= 20 && upper*10 >= letters*8
+}
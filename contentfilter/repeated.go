@@ -0,0 +1,47 @@
+package contentfilter
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// RepeatedMessageFilter menolak komentar yang isinya sama dengan komentar lain dari penulis yang sama dalam rentang waktu tertentu.
+type RepeatedMessageFilter struct {
+	DB     *gorm.DB
+	Window time.Duration // Rentang waktu pemeriksaan, default 10 menit
+	Limit  int           // Jumlah komentar sama yang sudah ada sebelum komentar berikutnya ditolak, default 2
+}
+
+// Name mengembalikan nama filter.
+func (f RepeatedMessageFilter) Name() string { return "repeated_message" }
+
+// Check menghitung komentar dengan isi yang sama dari penulis yang sama dalam rentang Window.
+// Komentar yang sedang diedit tidak ikut dihitung.
+func (f RepeatedMessageFilter) Check(ctx context.Context, input Input) (Result, error) {
+	window, limit := f.Window, f.Limit
+	if window <= 0 {
+		window = 10 * time.Minute
+	}
+	if limit <= 0 {
+		limit = 2
+	}
+
+	var count int64
+	err := f.DB.WithContext(ctx).Model(&models.Comment{}).
+		Where("user_id = ? AND id <> ? AND tombstoned = ? AND created_at > ?", input.UserID, input.CommentID, false, time.Now().Add(-window)).
+		Where("LOWER(TRIM(message)) = ?", strings.ToLower(strings.TrimSpace(input.Text))).
+		Count(&count).Error
+	if err != nil {
+		return Result{}, err
+	}
+
+	if count >= int64(limit) {
+		return Result{Decision: Reject, Reasons: []string{"pesan yang sama sudah dikirim berulang kali"}}, nil
+	}
+	return Result{Decision: Allow}, nil
+}
@@ -0,0 +1,487 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"mygram-final-project/accounts"
+	"mygram-final-project/audit"
+	"mygram-final-project/initializers"
+	"mygram-final-project/jobs"
+	"mygram-final-project/models"
+	"mygram-final-project/utils"
+)
+
+const (
+	defaultImpersonationTTL = 15 * time.Minute    // Masa berlaku default token impersonasi
+	maxBulkRemoveTargets    = 100                 // Jumlah konten maksimal pada satu permintaan penghapusan massal
+	defaultStatsRange       = 30 * 24 * time.Hour // Rentang default statistik situs
+	maxStatsRange           = 2 * 366 * 24 * time.Hour
+)
+
+// AdminController adalah kontroler untuk pengelolaan pengguna dan konten oleh admin
+type AdminController struct {
+	DB               *gorm.DB
+	ImpersonationTTL time.Duration
+	PasswordResetTTL time.Duration
+}
+
+// NewAdminController digunakan untuk membuat instance baru dari AdminController
+func NewAdminController(DB *gorm.DB, impersonationTTL time.Duration, passwordResetTTL time.Duration) AdminController {
+	if impersonationTTL <= 0 {
+		impersonationTTL = defaultImpersonationTTL
+	}
+	if passwordResetTTL <= 0 {
+		passwordResetTTL = models.DefaultPasswordResetTTL
+	}
+	return AdminController{DB, impersonationTTL, passwordResetTTL}
+}
+
+// SearchUsers mencari pengguna berdasarkan username atau email dengan opsi paging. Query yang didukung: q, role,
+// dan status (active, suspended, deletion_scheduled, atau password_reset_required).
+func (adc *AdminController) SearchUsers(ctx *gin.Context) {
+	page, limit, offset := parsePagination(ctx)
+	now := time.Now()
+
+	query := adc.DB.Model(&models.User{})
+	if q := strings.TrimSpace(ctx.Query("q")); q != "" {
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q) + "%"
+		query = query.Where("username ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+	if role := ctx.Query("role"); role != "" {
+		query = query.Where("role = ?", role)
+	}
+	switch ctx.Query("status") {
+	case "":
+	case "active":
+		query = query.Where("(suspended_at IS NULL OR suspended_until <= ?) AND deletion_scheduled_for IS NULL", now)
+	case "suspended":
+		query = query.Where("suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > ?)", now)
+	case "deletion_scheduled":
+		query = query.Where("deletion_scheduled_for IS NOT NULL")
+	case "password_reset_required":
+		query = query.Where("password_reset_required = ?", true)
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Status harus salah satu dari active, suspended, deletion_scheduled, atau password_reset_required."})
+		return
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var users []models.User
+	if err := query.Order("id ASC").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, user := range users {
+		responseData = append(responseData, adminUserResponse(user, now))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// SuspendUser menangguhkan akun pengguna. Tindakan dicatat di log moderasi seperti penangguhan oleh moderator.
+func (adc *AdminController) SuspendUser(ctx *gin.Context) {
+	adc.moderateUser(ctx, models.ModerationSuspendUser)
+}
+
+// UnsuspendUser memulihkan akun pengguna yang sedang ditangguhkan
+func (adc *AdminController) UnsuspendUser(ctx *gin.Context) {
+	adc.moderateUser(ctx, models.ModerationUnsuspendUser)
+}
+
+// ForcePasswordReset mewajibkan pengguna mengganti password. Semua sesi pengguna dicabut dan token reset sekali pakai
+// dikirim ke email pengguna oleh job latar belakang, sehingga admin tidak pernah melihat token tersebut.
+// Akun admin tidak dapat dipaksa reset password agar admin tidak dapat mengambil alih akun admin lain.
+func (adc *AdminController) ForcePasswordReset(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var user models.User
+	if err := adc.DB.First(&user, "username = ?", ctx.Param("username")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada user dengan username tersebut."})
+		return
+	}
+	if user.ID == currentUser.ID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Kamu tidak dapat mewajibkan reset password untuk akunmu sendiri."})
+		return
+	}
+	if user.Role == models.RoleAdmin {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": "Akun admin tidak dapat dipaksa reset password."})
+		return
+	}
+
+	// Token ini tidak pernah dikirim, job pengiriman email mengganti hash-nya dengan token baru
+	token, err := accounts.NewResetToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	now := time.Now()
+	reset := models.PasswordReset{
+		UserID:      user.ID,
+		TokenHash:   models.HashResetToken(token),
+		CreatedByID: &currentUser.ID,
+		ExpiresAt:   now.Add(adc.PasswordResetTTL),
+	}
+	err = adc.DB.Transaction(func(tx *gorm.DB) error {
+		// Token reset sebelumnya tidak berlaku lagi
+		if err := tx.Model(&models.PasswordReset{}).Where("user_id = ? AND used_at IS NULL", user.ID).
+			UpdateColumn("used_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&reset).Error; err != nil {
+			return err
+		}
+		if err := jobs.Enqueue(tx, accounts.JobSendPasswordReset, accounts.PasswordResetPayload{PasswordResetID: reset.ID}, jobs.EnqueueOptions{
+			UniqueKey: fmt.Sprintf("password-reset:%d", reset.ID),
+		}); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{
+			"password_reset_required": true,
+			"sessions_revoked_at":     now,
+		}).Error; err != nil {
+			return err
+		}
+
+		entry := auditEntry(ctx, &currentUser.ID, models.AuditForcePasswordReset, models.TargetUser, user.ID)
+		entry.Metadata = map[string]interface{}{"password_reset_id": reset.ID, "expires_at": reset.ExpiresAt}
+		return audit.Record(tx, entry)
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mewajibkan reset password."})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"user_id":    user.ID,
+		"expires_at": reset.ExpiresAt,
+	}})
+}
+
+// Impersonate menerbitkan token akses berumur pendek agar admin dapat melihat dan menggunakan aplikasi sebagai pengguna lain.
+// Token tidak disimpan di cookie sehingga sesi admin tetap utuh, dan setiap tindakan selama impersonasi tercatat di audit log
+// dengan ID admin sebagai impersonator_id.
+func (adc *AdminController) Impersonate(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.ImpersonateRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Reason harus diisi."})
+		return
+	}
+
+	var user models.User
+	if err := adc.DB.First(&user, "username = ?", ctx.Param("username")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada user dengan username tersebut."})
+		return
+	}
+	if user.ID == currentUser.ID || user.Role == models.RoleAdmin {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": "Akun admin tidak dapat diimpersonasi."})
+		return
+	}
+
+	config, _ := initializers.LoadConfig(".")
+	expiresAt := time.Now().Add(adc.ImpersonationTTL)
+	accessToken, err := utils.CreateTokenWithClaims(adc.ImpersonationTTL, user.ID, map[string]interface{}{"imp": currentUser.ID}, config.AccessTokenPrivateKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	entry := auditEntry(ctx, &currentUser.ID, models.AuditImpersonate, models.TargetUser, user.ID)
+	entry.Metadata = map[string]interface{}{"reason": payload.Reason, "expires_at": expiresAt}
+	if err := audit.Record(adc.DB, entry); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"user_id":      user.ID,
+		"username":     user.Username,
+		"access_token": accessToken,
+		"expires_at":   expiresAt,
+	}})
+}
+
+// BulkRemoveContent menghapus banyak foto dan komentar sekaligus dalam satu transaksi. Setiap penghapusan dicatat di log
+// moderasi dan audit log. Konten yang tidak ditemukan atau sudah dihapus dilewati.
+func (adc *AdminController) BulkRemoveContent(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.BulkRemoveContentRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if len(payload.Targets) == 0 && payload.Username == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Targets atau username harus diisi."})
+		return
+	}
+	if len(payload.Targets) > maxBulkRemoveTargets {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Targets maksimal berisi 100 konten."})
+		return
+	}
+	for _, target := range payload.Targets {
+		if target.TargetType != models.TargetPhoto && target.TargetType != models.TargetComment {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Target type harus salah satu dari photo atau comment."})
+			return
+		}
+	}
+
+	targets := payload.Targets
+	if payload.Username != "" {
+		var user models.User
+		if err := adc.DB.First(&user, "username = ?", payload.Username).Error; err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada user dengan username tersebut."})
+			return
+		}
+		var photoIDs, commentIDs []int64
+		if err := adc.DB.Model(&models.Photo{}).Where("user_id = ?", user.ID).Order("id").Pluck("id", &photoIDs).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+		if err := adc.DB.Model(&models.Comment{}).Where("user_id = ? AND tombstoned = ?", user.ID, false).Order("id").Pluck("id", &commentIDs).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+		for _, id := range photoIDs {
+			targets = append(targets, models.ContentTarget{TargetType: models.TargetPhoto, TargetID: id})
+		}
+		for _, id := range commentIDs {
+			targets = append(targets, models.ContentTarget{TargetType: models.TargetComment, TargetID: id})
+		}
+	}
+
+	removed := []gin.H{}
+	skipped := []models.ContentTarget{}
+	err := adc.DB.Transaction(func(tx *gorm.DB) error {
+		for _, target := range targets {
+			targetUserID, err := findTargetOwner(tx, target.TargetType, target.TargetID)
+			if err != nil {
+				skipped = append(skipped, target)
+				continue
+			}
+
+			action, err := applyModerationAction(tx, currentUser, nil, models.ModerationActionRequest{
+				Action:     models.ModerationRemoveContent,
+				TargetType: target.TargetType,
+				TargetID:   target.TargetID,
+				Note:       payload.Note,
+			}, targetUserID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				skipped = append(skipped, target)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := recordModerationAudit(ctx, tx, action); err != nil {
+				return err
+			}
+			removed = append(removed, moderationActionResponse(action))
+		}
+		return nil
+	})
+	if err != nil {
+		respondActionError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"removed": removed, "skipped": skipped}})
+}
+
+// statsMetric adalah jenis data yang dihitung pada statistik situs
+type statsMetric struct {
+	name  string
+	query func() *gorm.DB
+}
+
+// statsBucket adalah jumlah data yang dibuat pada satu periode
+type statsBucket struct {
+	Period time.Time
+	Count  int64
+}
+
+// GetStats mengambil jumlah pengguna, foto, dan komentar saat ini beserta jumlah yang dibuat per periode. Query yang didukung:
+// interval (day, week, atau month), serta from dan to dalam format RFC3339. Periode dihitung dalam UTC.
+func (adc *AdminController) GetStats(ctx *gin.Context) {
+	interval := ctx.DefaultQuery("interval", "day")
+	if interval != "day" && interval != "week" && interval != "month" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Interval harus salah satu dari day, week, atau month."})
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-defaultStatsRange)
+	for _, param := range []struct {
+		name string
+		dest *time.Time
+	}{
+		{"from", &from},
+		{"to", &to},
+	} {
+		if value := ctx.Query(param.name); value != "" {
+			at, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": param.name + " harus dalam format RFC3339."})
+				return
+			}
+			*param.dest = at
+		}
+	}
+	if !from.Before(to) || to.Sub(from) > maxStatsRange {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "from harus sebelum to dengan rentang maksimal 2 tahun."})
+		return
+	}
+
+	metrics := []statsMetric{
+		{"users", func() *gorm.DB { return adc.DB.Model(&models.User{}) }},
+		{"photos", func() *gorm.DB { return adc.DB.Model(&models.Photo{}) }},
+		{"comments", func() *gorm.DB { return adc.DB.Model(&models.Comment{}).Where("tombstoned = ?", false) }},
+	}
+
+	totals := gin.H{}
+	counts := map[string]map[int64]int64{}
+	for _, metric := range metrics {
+		var total int64
+		if err := metric.query().Count(&total).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+		totals[metric.name] = total
+
+		var buckets []statsBucket
+		if err := metric.query().
+			Select("date_trunc(?, created_at AT TIME ZONE 'UTC') AS period, COUNT(*) AS count", interval).
+			Where("created_at >= ? AND created_at < ?", from, to).
+			Group("period").Find(&buckets).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+		counts[metric.name] = map[int64]int64{}
+		for _, bucket := range buckets {
+			counts[metric.name][bucket.Period.Unix()] = bucket.Count
+		}
+	}
+
+	var suspended int64
+	if err := adc.DB.Model(&models.User{}).
+		Where("suspended_at IS NOT NULL AND (suspended_until IS NULL OR suspended_until > ?)", time.Now()).
+		Count(&suspended).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+	totals["suspended_users"] = suspended
+
+	// Periode tanpa data tetap ditampilkan dengan jumlah 0
+	series := []gin.H{}
+	for period := truncatePeriod(from, interval); period.Before(to); period = nextPeriod(period, interval) {
+		point := gin.H{"period": period}
+		for _, metric := range metrics {
+			point[metric.name] = counts[metric.name][period.Unix()]
+		}
+		series = append(series, point)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"totals":   totals,
+		"interval": interval,
+		"from":     from,
+		"to":       to,
+		"series":   series,
+	}})
+}
+
+// moderateUser menangguhkan atau memulihkan akun pengguna pada parameter username dengan tindakan moderasi action
+func (adc *AdminController) moderateUser(ctx *gin.Context, action string) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.AdminSuspendRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	var user models.User
+	if err := adc.DB.First(&user, "username = ?", ctx.Param("username")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada user dengan username tersebut."})
+		return
+	}
+
+	var moderationAction models.ModerationAction
+	err := adc.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if moderationAction, err = applyModerationAction(tx, currentUser, nil, models.ModerationActionRequest{
+			Action:      action,
+			TargetType:  models.TargetUser,
+			TargetID:    user.ID,
+			Note:        payload.Note,
+			SuspendDays: payload.SuspendDays,
+		}, user.ID); err != nil {
+			return err
+		}
+		return recordModerationAudit(ctx, tx, moderationAction)
+	})
+	if err != nil {
+		respondActionError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": moderationActionResponse(moderationAction)})
+}
+
+// adminUserResponse membuat data respons pengguna untuk admin, termasuk status akun
+func adminUserResponse(user models.User, now time.Time) gin.H {
+	return gin.H{
+		"id":                      user.ID,
+		"username":                user.Username,
+		"email":                   user.Email,
+		"birthdate":               user.BirthdateText(),
+		"birthdate_estimated":     user.BirthdateEstimated,
+		"age":                     ageOrNil(user),
+		"role":                    user.Role,
+		"suspended":               user.IsSuspended(now),
+		"suspended_until":         user.SuspendedUntil,
+		"deletion_scheduled_for":  user.DeletionScheduledFor,
+		"password_reset_required": user.PasswordResetRequired,
+		"created_at":              user.CreatedAt,
+	}
+}
+
+// truncatePeriod mengembalikan awal periode interval yang memuat t dalam UTC, sama dengan date_trunc di PostgreSQL.
+// Minggu dimulai pada hari Senin.
+func truncatePeriod(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case "week":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// nextPeriod mengembalikan awal periode interval setelah period
+func nextPeriod(period time.Time, interval string) time.Time {
+	switch interval {
+	case "week":
+		return period.AddDate(0, 0, 7)
+	case "month":
+		return period.AddDate(0, 1, 0)
+	}
+	return period.AddDate(0, 0, 1)
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"mygram-final-project/models"
+)
+
+// maxAge adalah usia maksimal yang masuk akal untuk tanggal lahir yang diisi pengguna
+const maxAge = 150
+
+// agePolicy adalah aturan batas usia yang dipakai seluruh kontroler. Nilainya diatur dari konfigurasi melalui SetAgePolicy.
+var agePolicy = models.AgePolicy{MinimumAge: models.DefaultMinimumAge, RestrictedContentAge: models.DefaultRestrictedContentAge}
+
+// SetAgePolicy mengatur batas usia pendaftaran dan batas usia foto yang ditandai berbatasan usia.
+// Batas yang kosong atau tidak positif memakai nilai default.
+func SetAgePolicy(policy models.AgePolicy) {
+	if policy.MinimumAge <= 0 {
+		policy.MinimumAge = models.DefaultMinimumAge
+	}
+	if policy.RestrictedContentAge <= 0 {
+		policy.RestrictedContentAge = models.DefaultRestrictedContentAge
+	}
+	agePolicy = policy
+}
+
+// parseBirthdate membaca tanggal lahir berformat YYYY-MM-DD lalu memastikan usianya memenuhi batas usia pendaftaran.
+// Jika tidak valid, pesan error dikembalikan.
+func parseBirthdate(value string) (time.Time, string) {
+	birthdate, err := time.Parse(models.BirthdateLayout, value)
+	if err != nil {
+		return birthdate, "Format birthdate harus YYYY-MM-DD."
+	}
+
+	age, _ := models.User{Birthdate: &birthdate}.AgeAt(time.Now())
+	if birthdate.After(time.Now()) || age > maxAge {
+		return birthdate, "Birthdate tidak valid."
+	}
+	if age < agePolicy.MinimumAge {
+		return birthdate, fmt.Sprintf("Umur minimal %d tahun.", agePolicy.MinimumAge)
+	}
+	return birthdate, ""
+}
+
+// canViewRestrictedContent menentukan apakah viewer cukup umur untuk melihat foto yang ditandai berbatasan usia.
+// Pengguna yang tanggal lahirnya tidak diketahui dianggap belum cukup umur.
+func canViewRestrictedContent(viewer models.User) bool {
+	age, ok := viewer.AgeAt(time.Now())
+	return ok && age >= agePolicy.RestrictedContentAge
+}
+
+// ageOrNil mengembalikan usia user saat ini untuk respons, atau nil jika tanggal lahirnya tidak diketahui
+func ageOrNil(user models.User) *int {
+	age, ok := user.AgeAt(time.Now())
+	if !ok {
+		return nil
+	}
+	return &age
+}
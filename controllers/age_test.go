@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"mygram-final-project/models"
+)
+
+func TestParseBirthdate(t *testing.T) {
+	today := time.Now()
+	date := func(years int, days int) string {
+		return today.AddDate(-years, 0, days).Format(models.BirthdateLayout)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantMsg string
+	}{
+		{"dewasa", "1990-05-17", ""},
+		{"tepat batas usia hari ini", date(agePolicy.MinimumAge, 0), ""},
+		{"sehari sebelum batas usia", date(agePolicy.MinimumAge, 1), "Umur minimal 13 tahun."},
+		{"anak-anak", date(5, 0), "Umur minimal 13 tahun."},
+		{"di masa depan", date(0, 1), "Birthdate tidak valid."},
+		{"terlalu tua", date(maxAge+1, 0), "Birthdate tidak valid."},
+		{"format salah", "17-05-1990", "Format birthdate harus YYYY-MM-DD."},
+		{"tanggal tidak ada", "1990-02-30", "Format birthdate harus YYYY-MM-DD."},
+		{"kosong", "", "Format birthdate harus YYYY-MM-DD."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			birthdate, msg := parseBirthdate(tt.value)
+			if msg != tt.wantMsg {
+				t.Fatalf("parseBirthdate(%q) = %q, ingin %q", tt.value, msg, tt.wantMsg)
+			}
+			if msg == "" && birthdate.Format(models.BirthdateLayout) != tt.value {
+				t.Errorf("birthdate = %v, ingin %s", birthdate, tt.value)
+			}
+		})
+	}
+}
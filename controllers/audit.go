@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"mygram-final-project/audit"
+	"mygram-final-project/models"
+)
+
+// auditEntry membuat entri audit log untuk tindakan actorID pada permintaan ctx, lengkap dengan IP dan ID permintaan.
+// Pada sesi impersonasi, ID admin yang sebenarnya melakukan tindakan ikut dicatat.
+func auditEntry(ctx *gin.Context, actorID *int64, action string, targetType string, targetID int64) audit.Entry {
+	entry := audit.Entry{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   audit.ID(targetID),
+		IP:         ctx.ClientIP(),
+		RequestID:  ctx.GetString("requestId"),
+	}
+	if impersonatorID, ok := ctx.Get("impersonatorId"); ok {
+		entry.ImpersonatorID = audit.ID(impersonatorID.(int64))
+	}
+	return entry
+}
+
+// userAuditFields mengembalikan field profil pengguna yang dicatat di audit log. Password tidak pernah dicatat,
+// dan nilai data pribadi disamarkan oleh audit.Record.
+func userAuditFields(user models.User) audit.Fields {
+	return audit.Fields{
+		"username":          user.Username,
+		"email":             user.Email,
+		"birthdate":         user.BirthdateText(),
+		"profile_image_url": user.ProfileImageURL,
+		"display_name":      user.DisplayName,
+		"bio":               user.Bio,
+		"website":           user.Website,
+		"pronouns":          user.Pronouns,
+		"location":          user.Location,
+		"allow_mentions":    user.AllowMentions,
+		"is_private":        user.IsPrivate,
+	}
+}
+
+// photoAuditFields mengembalikan field foto yang dicatat di audit log
+func photoAuditFields(photo models.Photo) audit.Fields {
+	return audit.Fields{
+		"title":          photo.Title,
+		"caption":        photo.Caption,
+		"photo_url":      photo.PhotoURL,
+		"age_restricted": photo.AgeRestricted,
+	}
+}
+
+// commentAuditFields mengembalikan field komentar yang dicatat di audit log
+func commentAuditFields(comment models.Comment) audit.Fields {
+	return audit.Fields{
+		"photo_id": comment.PhotoID,
+		"message":  comment.Message,
+	}
+}
+
+// socialMediaAuditFields mengembalikan field media sosial yang dicatat di audit log
+func socialMediaAuditFields(socialMedia models.SocialMedia) audit.Fields {
+	return audit.Fields{
+		"name":             socialMedia.Name,
+		"social_media_url": socialMedia.SocialMediaURL,
+	}
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// AuditController adalah kontroler untuk membaca audit log
+type AuditController struct {
+	DB *gorm.DB
+}
+
+// NewAuditController digunakan untuk membuat instance baru dari AuditController
+func NewAuditController(DB *gorm.DB) AuditController {
+	return AuditController{DB}
+}
+
+// GetAuditLogs mengambil catatan audit log dengan opsi paging, dari yang terbaru. Query yang didukung:
+// actor_id, impersonator_id, action (diakhiri titik untuk mencocokkan awalan, misalnya "moderation."), target_type, target_id,
+// serta from dan to dalam format RFC3339.
+func (ac *AuditController) GetAuditLogs(ctx *gin.Context) {
+	page, limit, offset := parsePagination(ctx)
+
+	query := ac.DB.Model(&models.AuditLog{})
+	for _, filter := range []struct {
+		param  string
+		column string
+	}{
+		{"actor_id", "actor_id"},
+		{"impersonator_id", "impersonator_id"},
+		{"target_id", "target_id"},
+	} {
+		value := ctx.Query(filter.param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": filter.param + " harus berupa angka."})
+			return
+		}
+		query = query.Where(filter.column+" = ?", id)
+	}
+	if action := ctx.Query("action"); strings.HasSuffix(action, ".") {
+		query = query.Where("action LIKE ?", action+"%")
+	} else if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if targetType := ctx.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	for _, filter := range []struct {
+		param     string
+		condition string
+	}{
+		{"from", "created_at >= ?"},
+		{"to", "created_at < ?"},
+	} {
+		value := ctx.Query(filter.param)
+		if value == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": filter.param + " harus dalam format RFC3339."})
+			return
+		}
+		query = query.Where(filter.condition, at)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var logs []models.AuditLog
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&logs).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, log := range logs {
+		responseData = append(responseData, auditLogResponse(log))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// auditLogResponse membuat data respons catatan audit log. Changes dan metadata dikirim sebagai objek JSON.
+func auditLogResponse(log models.AuditLog) gin.H {
+	return gin.H{
+		"id":              log.ID,
+		"actor_id":        log.ActorID,
+		"impersonator_id": log.ImpersonatorID,
+		"action":          log.Action,
+		"target_type":     log.TargetType,
+		"target_id":       log.TargetID,
+		"changes":         rawJSON(log.Changes),
+		"metadata":        rawJSON(log.Metadata),
+		"ip":              log.IP,
+		"request_id":      log.RequestID,
+		"created_at":      log.CreatedAt,
+	}
+}
+
+// rawJSON mengembalikan teks JSON tersimpan apa adanya, atau null jika kosong
+func rawJSON(value string) json.RawMessage {
+	if value == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(value)
+}
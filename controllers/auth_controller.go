@@ -1,13 +1,18 @@
 package controllers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"mygram-final-project/accounts"
+	"mygram-final-project/audit"
 	"mygram-final-project/initializers"
 	"mygram-final-project/models"
 	"mygram-final-project/utils"
@@ -62,9 +67,10 @@ func (ac *AuthController) SignUpUser(ctx *gin.Context) {
 		return
 	}
 
-	// Validasi age
-	if payload.Age < 8 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Umur minimal 8 tahun."})
+	// Validasi tanggal lahir dan batas usia pendaftaran
+	birthdate, message := parseBirthdate(payload.Birthdate)
+	if message != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": message})
 		return
 	}
 
@@ -86,15 +92,23 @@ func (ac *AuthController) SignUpUser(ctx *gin.Context) {
 		Username:        payload.Username,
 		Email:           strings.ToLower(payload.Email),
 		Password:        hashedPassword,
-		Age:             payload.Age,
+		Birthdate:       &birthdate,
 		ProfileImageURL: payload.ProfileImageURL,
+		Role:            models.RoleUser,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
 
-	// Simpan pengguna baru ke database
-	result := ac.DB.Create(&newUser)
-	if result.Error != nil {
+	// Simpan pengguna baru ke database beserta catatan audit pendaftarannya
+	err = ac.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newUser).Error; err != nil {
+			return err
+		}
+		entry := auditEntry(ctx, &newUser.ID, models.AuditRegister, models.TargetUser, newUser.ID)
+		entry.After = userAuditFields(newUser)
+		return audit.Record(tx, entry)
+	})
+	if err != nil {
 		ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": "Something bad happened"})
 		return
 	}
@@ -104,7 +118,8 @@ func (ac *AuthController) SignUpUser(ctx *gin.Context) {
 		"id":                newUser.ID,
 		"email":             newUser.Email,
 		"username":          newUser.Username,
-		"age":               newUser.Age,
+		"birthdate":         newUser.BirthdateText(),
+		"age":               ageOrNil(newUser),
 		"profile_image_url": newUser.ProfileImageURL,
 	}
 	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": userResponse})
@@ -124,16 +139,50 @@ func (ac *AuthController) SignInUser(ctx *gin.Context) {
 	var user models.User
 	result := ac.DB.First(&user, "email = ?", strings.ToLower(payload.Email))
 	if result.Error != nil {
+		ac.recordFailedLogin(ctx, payload.Email, 0, "unknown_email")
 		ctx.JSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": "Email atau password tidak valid."})
 		return
 	}
 
 	// Verifikasi password
 	if err := utils.VerifyPassword(user.Password, payload.Password); err != nil {
+		ac.recordFailedLogin(ctx, payload.Email, user.ID, "invalid_password")
 		ctx.JSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": "Email atau password tidak valid."})
 		return
 	}
 
+	// Tolak login dari akun yang sedang ditangguhkan
+	if user.IsSuspended(time.Now()) {
+		ac.recordFailedLogin(ctx, payload.Email, user.ID, "suspended")
+		ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Akun kamu sedang ditangguhkan."})
+		return
+	}
+
+	// Akun yang diwajibkan reset password oleh admin hanya dapat dipakai lagi setelah password diganti dengan token reset
+	if user.PasswordResetRequired {
+		ac.recordFailedLogin(ctx, payload.Email, user.ID, "password_reset_required")
+		ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu harus mengganti password dengan token reset dari admin sebelum dapat login."})
+		return
+	}
+
+	// Login kembali membatalkan penghapusan akun yang masih dalam masa tenggang. Login dan pembatalannya dicatat di audit log.
+	var deletionCancelled bool
+	if err := ac.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if deletionCancelled, err = accounts.Cancel(tx, &user); err != nil {
+			return err
+		}
+		if deletionCancelled {
+			if err := audit.Record(tx, auditEntry(ctx, &user.ID, models.AuditDeletionCancelled, models.TargetUser, user.ID)); err != nil {
+				return err
+			}
+		}
+		return audit.Record(tx, auditEntry(ctx, &user.ID, models.AuditLogin, models.TargetUser, user.ID))
+	}); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"status": "fail", "message": "Gagal memproses login."})
+		return
+	}
+
 	// Load konfigurasi dari file .env
 	config, _ := initializers.LoadConfig(".")
 
@@ -156,5 +205,90 @@ func (ac *AuthController) SignInUser(ctx *gin.Context) {
 	ctx.SetCookie("refresh_token", refresh_token, config.RefreshTokenMaxAge*60, "/", "localhost", false, true)
 
 	// Respon dengan token akses
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "access_token": access_token})
+	response := gin.H{"status": "success", "access_token": access_token}
+	if deletionCancelled {
+		response["message"] = "Penghapusan akun kamu dibatalkan."
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ResetPassword mengganti password dengan token reset sekali pakai yang dibuat admin. Semua sesi lama pengguna dicabut
+// dan pengguna harus login kembali dengan password baru.
+func (ac *AuthController) ResetPassword(ctx *gin.Context) {
+	var payload models.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": err.Error()})
+		return
+	}
+
+	// Validasi password
+	if len(payload.Password) < 6 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Password setidaknya berisi 6 karakter."})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(payload.Password)
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	err = ac.DB.Transaction(func(tx *gorm.DB) error {
+		var reset models.PasswordReset
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", models.HashResetToken(payload.Token), now).
+			First(&reset).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&reset).UpdateColumn("used_at", now).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.User{}).Where("id = ?", reset.UserID).UpdateColumns(map[string]interface{}{
+			"password":                hashedPassword,
+			"password_reset_required": false,
+			"sessions_revoked_at":     now,
+			"updated_at":              now,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Token reset yang lain untuk pengguna yang sama tidak berlaku lagi
+		if err := tx.Model(&models.PasswordReset{}).Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			UpdateColumn("used_at", now).Error; err != nil {
+			return err
+		}
+
+		entry := auditEntry(ctx, &reset.UserID, models.AuditPasswordReset, models.TargetUser, reset.UserID)
+		entry.Metadata = map[string]interface{}{"password_reset_id": reset.ID}
+		return audit.Record(tx, entry)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Token reset tidak valid atau sudah kedaluwarsa."})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"status": "fail", "message": "Gagal mengganti password."})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "Password berhasil diganti. Silakan login kembali."})
+}
+
+// recordFailedLogin mencatat percobaan login yang gagal beserta alasannya. Email yang dicoba hanya disimpan dalam bentuk hash.
+// Kegagalan menulis catatan tidak menggagalkan respons login.
+func (ac *AuthController) recordFailedLogin(ctx *gin.Context, email string, userID int64, reason string) {
+	entry := auditEntry(ctx, nil, models.AuditLoginFailed, models.TargetUser, userID)
+	if userID == 0 {
+		entry.TargetType, entry.TargetID = "", nil
+	}
+	entry.Metadata = map[string]interface{}{"reason": reason, "email_hash": models.HashEmail(email)}
+	if err := audit.Record(ac.DB, entry); err != nil {
+		log.Printf("audit: gagal mencatat login yang gagal: %v", err)
+	}
 }
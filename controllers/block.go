@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// isBlocked memeriksa apakah salah satu dari dua pengguna memblokir pengguna lainnya
+func isBlocked(db *gorm.DB, userID int64, otherID int64) bool {
+	var count int64
+	db.Model(&models.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count)
+	return count > 0
+}
+
+// blockedUserIDs mengembalikan ID pengguna yang memblokir atau diblokir oleh userID
+func blockedUserIDs(db *gorm.DB, userID int64) []int64 {
+	var ids []int64
+	db.Raw(`
+		SELECT blocked_id FROM blocks WHERE blocker_id = ?
+		UNION
+		SELECT blocker_id FROM blocks WHERE blocked_id = ?`, userID, userID).Scan(&ids)
+	return ids
+}
+
+// mutedUserIDs mengembalikan ID pengguna yang dibisukan oleh userID
+func mutedUserIDs(db *gorm.DB, userID int64) []int64 {
+	var ids []int64
+	db.Model(&models.Mute{}).Where("muter_id = ?", userID).Pluck("muted_id", &ids)
+	return ids
+}
+
+// feedHiddenUserIDs mengembalikan ID pengguna yang kontennya tidak ditampilkan di feed userID, yaitu pengguna yang diblokir atau dibisukan
+func feedHiddenUserIDs(db *gorm.DB, userID int64) []int64 {
+	return append(blockedUserIDs(db, userID), mutedUserIDs(db, userID)...)
+}
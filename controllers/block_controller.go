@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/models"
+)
+
+// BlockController adalah kontroler untuk memblokir dan membisukan pengguna lain
+type BlockController struct {
+	DB *gorm.DB
+}
+
+// NewBlockController digunakan untuk membuat instance baru dari BlockController
+func NewBlockController(DB *gorm.DB) BlockController {
+	return BlockController{DB}
+}
+
+// Block digunakan untuk memblokir pengguna berdasarkan username. Hubungan follow di kedua arah ikut dihapus.
+func (bc *BlockController) Block(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	target, ok := bc.findTarget(ctx, currentUser, "Kamu tidak dapat memblokir diri sendiri.")
+	if !ok {
+		return
+	}
+
+	err := bc.DB.Transaction(func(tx *gorm.DB) error {
+		block := models.Block{BlockerID: currentUser.ID, BlockedID: target.ID, CreatedAt: time.Now()}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&block).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("(follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)",
+			currentUser.ID, target.ID, target.ID, currentUser.ID).Delete(&models.Follow{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("(requester_id = ? AND target_id = ?) OR (requester_id = ? AND target_id = ?)",
+			currentUser.ID, target.ID, target.ID, currentUser.ID).Delete(&models.FollowRequest{}).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user_id": target.ID, "blocked": true}})
+}
+
+// Unblock digunakan untuk membuka blokir pengguna berdasarkan username
+func (bc *BlockController) Unblock(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	target, ok := bc.findTarget(ctx, currentUser, "Kamu tidak dapat membuka blokir diri sendiri.")
+	if !ok {
+		return
+	}
+
+	if err := bc.DB.Where("blocker_id = ? AND blocked_id = ?", currentUser.ID, target.ID).Delete(&models.Block{}).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user_id": target.ID, "blocked": false}})
+}
+
+// GetBlocked mengambil daftar pengguna yang diblokir oleh pengguna saat ini dengan opsi paging
+func (bc *BlockController) GetBlocked(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	var total int64
+	bc.DB.Model(&models.Block{}).Where("blocker_id = ?", currentUser.ID).Count(&total)
+
+	var blocks []models.Block
+	result := bc.DB.Preload("Blocked").Where("blocker_id = ?", currentUser.ID).
+		Order("created_at DESC").Limit(limit).Offset(offset).Find(&blocks)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, block := range blocks {
+		responseData = append(responseData, gin.H{
+			"id":                block.Blocked.ID,
+			"username":          block.Blocked.Username,
+			"profile_image_url": block.Blocked.ProfileImageURL,
+			"blocked_at":        block.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// Mute digunakan untuk membisukan pengguna berdasarkan username
+func (bc *BlockController) Mute(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	target, ok := bc.findTarget(ctx, currentUser, "Kamu tidak dapat membisukan diri sendiri.")
+	if !ok {
+		return
+	}
+
+	mute := models.Mute{MuterID: currentUser.ID, MutedID: target.ID, CreatedAt: time.Now()}
+	if err := bc.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&mute).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user_id": target.ID, "muted": true}})
+}
+
+// Unmute digunakan untuk berhenti membisukan pengguna berdasarkan username
+func (bc *BlockController) Unmute(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	target, ok := bc.findTarget(ctx, currentUser, "Kamu tidak dapat berhenti membisukan diri sendiri.")
+	if !ok {
+		return
+	}
+
+	if err := bc.DB.Where("muter_id = ? AND muted_id = ?", currentUser.ID, target.ID).Delete(&models.Mute{}).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user_id": target.ID, "muted": false}})
+}
+
+// GetMuted mengambil daftar pengguna yang dibisukan oleh pengguna saat ini dengan opsi paging
+func (bc *BlockController) GetMuted(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	var total int64
+	bc.DB.Model(&models.Mute{}).Where("muter_id = ?", currentUser.ID).Count(&total)
+
+	var mutes []models.Mute
+	result := bc.DB.Preload("Muted").Where("muter_id = ?", currentUser.ID).
+		Order("created_at DESC").Limit(limit).Offset(offset).Find(&mutes)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, mute := range mutes {
+		responseData = append(responseData, gin.H{
+			"id":                mute.Muted.ID,
+			"username":          mute.Muted.Username,
+			"profile_image_url": mute.Muted.ProfileImageURL,
+			"muted_at":          mute.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// findTarget mengambil pengguna pada parameter username dan menolak jika pengguna tersebut adalah pengguna saat ini
+func (bc *BlockController) findTarget(ctx *gin.Context, currentUser models.User, selfMessage string) (models.User, bool) {
+	var target models.User
+	if err := bc.DB.First(&target, "username = ?", ctx.Param("username")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada user dengan username tersebut."})
+		return target, false
+	}
+
+	if target.ID == currentUser.ID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": selfMessage})
+		return target, false
+	}
+
+	return target, true
+}
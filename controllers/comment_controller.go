@@ -1,23 +1,47 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"mygram-final-project/audit"
+	"mygram-final-project/contentfilter"
+	"mygram-final-project/events"
 	"mygram-final-project/models"
+	"mygram-final-project/realtime"
 )
 
+// maxCommentDepth adalah jumlah tingkat maksimal dalam satu thread komentar, termasuk komentar utama
+const maxCommentDepth = 3
+
+// maxPinnedComments adalah jumlah maksimal komentar yang dapat disematkan pada satu foto
+const maxPinnedComments = 3
+
+// errPinLimitReached dikembalikan saat foto sudah memiliki maxPinnedComments komentar yang disematkan
+var errPinLimitReached = errors.New("batas komentar yang disematkan tercapai")
+
+// errParentDeleted dikembalikan saat komentar induk dihapus bersamaan dengan pembuatan balasan
+var errParentDeleted = errors.New("komentar induk sudah dihapus")
+
+// errParentChanged dikembalikan saat photo atau kedalaman komentar induk berubah bersamaan dengan pembuatan balasan
+var errParentChanged = errors.New("komentar induk sudah berubah")
+
 // CommentController adalah kontroler untuk operasi yang berhubungan dengan komentar
 type CommentController struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Filter *contentfilter.Pipeline
 }
 
-// NewCommentController digunakan untuk membuat instance baru dari CommentController
-func NewCommentController(DB *gorm.DB) CommentController {
-	return CommentController{DB}
+// NewCommentController digunakan untuk membuat instance baru dari CommentController dengan filter konten untuk isi komentar
+func NewCommentController(DB *gorm.DB, filter *contentfilter.Pipeline) CommentController {
+	return CommentController{DB, filter}
 }
 
 // CreateComment digunakan untuk menangani permintaan pembuatan komentar baru
@@ -46,38 +70,176 @@ func (cc *CommentController) CreateComment(ctx *gin.Context) {
 		UpdatedAt: now,
 	}
 
-	// Simpan komentar baru ke database
-	result := cc.DB.Create(&newComment)
-	if result.Error != nil {
+	// Validasi komentar induk jika komentar ini merupakan balasan
+	var parentAuthorID int64
+	if payload.ParentID != nil {
+		var parent models.Comment
+		if err := cc.DB.Preload("Photo").First(&parent, "id = ?", *payload.ParentID).Error; err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar induk dengan ID tersebut."})
+			return
+		}
+		if !canViewComment(currentUser, parent, parent.Photo.UserID) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar induk dengan ID tersebut."})
+			return
+		}
+		if parent.Tombstoned {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Komentar induk sudah dihapus."})
+			return
+		}
+		if isBlocked(cc.DB, currentUser.ID, parent.UserID) {
+			ctx.JSON(http.StatusForbidden, gin.H{"message": "Kamu tidak dapat membalas komentar ini."})
+			return
+		}
+		if newComment.PhotoID == 0 {
+			newComment.PhotoID = parent.PhotoID
+		}
+		if parent.PhotoID != newComment.PhotoID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Komentar induk tidak berada pada photo yang sama."})
+			return
+		}
+		if parent.Depth+1 >= maxCommentDepth {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Balasan maksimal %d tingkat.", maxCommentDepth-1)})
+			return
+		}
+		newComment.ParentID = &parent.ID
+		newComment.Depth = parent.Depth + 1
+		parentAuthorID = parent.UserID
+	}
+
+	// Validasi foto yang dikomentari
+	if newComment.PhotoID == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Photo ID harus diisi."})
+		return
+	}
+	var photo models.Photo
+	if err := cc.DB.First(&photo, "id = ?", newComment.PhotoID).Error; err != nil || !canViewPhoto(cc.DB, currentUser, photo) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada photo dengan ID tersebut."})
+		return
+	}
+	if isBlocked(cc.DB, currentUser.ID, photo.UserID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": "Kamu tidak dapat mengomentari photo ini."})
+		return
+	}
+	if message, ok := canCommentOn(cc.DB, currentUser, photo); !ok {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": message})
+		return
+	}
+
+	// Periksa isi komentar dengan filter konten. Komentar yang ditahan disimpan sebagai pending sampai ditinjau moderator.
+	verdict := cc.Filter.Check(ctx.Request.Context(), contentfilter.Input{
+		UserID:  currentUser.ID,
+		PhotoID: newComment.PhotoID,
+		Text:    newComment.Message,
+	})
+	if verdict.Decision == contentfilter.Reject {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"message": "Komentar ditolak oleh filter konten.", "reasons": verdict.Reasons})
+		return
+	}
+	newComment.Status = models.CommentPublished
+	if verdict.Decision == contentfilter.Hold {
+		newComment.Status = models.CommentPending
+		newComment.FilterNote = strings.Join(verdict.Reasons, "; ")
+	}
+
+	// Simpan komentar baru ke database bersama jumlah balasan pada komentar induk
+	err := cc.DB.Transaction(func(tx *gorm.DB) error {
+		// Kunci dan periksa ulang komentar induk agar tidak dihapus bersamaan sebelum balasan tersimpan.
+		// Penghapusan yang menunggu kunci ini akan melihat reply_count yang baru dan mengubah induk menjadi tombstone.
+		var parent models.Comment
+		if newComment.ParentID != nil {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, "id = ?", *newComment.ParentID).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errParentDeleted
+			}
+			if err != nil {
+				return err
+			}
+			if parent.Tombstoned {
+				return errParentDeleted
+			}
+			if parent.PhotoID != newComment.PhotoID || parent.Depth+1 != newComment.Depth {
+				return errParentChanged
+			}
+		}
+
+		if err := tx.Create(&newComment).Error; err != nil {
+			return err
+		}
+		if newComment.ParentID != nil {
+			if err := tx.Model(&parent).UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+		if newComment.Status == models.CommentPending {
+			return nil
+		}
+		return publishComment(tx, newComment, currentUser, photo.UserID, parentAuthorID)
+	})
+	if errors.Is(err, errParentDeleted) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Komentar induk sudah dihapus."})
+		return
+	}
+	if errors.Is(err, errParentChanged) {
+		ctx.JSON(http.StatusConflict, gin.H{"message": "Komentar induk sudah berubah. Silakan coba lagi."})
+		return
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
 		return
 	}
 
+	mentions := mentionEntities(cc.DB, models.MentionSourceComment, []int64{newComment.ID})
 	ctx.JSON(http.StatusCreated, gin.H{
-		"id":       newComment.ID,
-		"message":  newComment.Message,
-		"photo_id": newComment.PhotoID,
-		"user_id":  newComment.UserID,
+		"id":        newComment.ID,
+		"message":   newComment.Message,
+		"photo_id":  newComment.PhotoID,
+		"user_id":   newComment.UserID,
+		"parent_id": newComment.ParentID,
+		"depth":     newComment.Depth,
+		"status":    newComment.Status,
+		"mentions":  mentionsOrEmpty(mentions[newComment.ID]),
 	})
 }
 
-// GetComments digunakan untuk menangani permintaan untuk mendapatkan semua komentar
+// GetComments digunakan untuk menangani permintaan untuk mendapatkan semua komentar dengan opsi paging (khusus admin)
 func (cc *CommentController) GetComment(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	var total int64
+	cc.DB.Model(&models.Comment{}).Count(&total)
+
 	var comments []models.Comment
-	result := cc.DB.Preload("User").Preload("Photo").Find(&comments)
+	result := cc.DB.Preload("User").Preload("Photo").Order("id DESC").Limit(limit).Offset(offset).Find(&comments)
 	if result.Error != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
 		return
 	}
 
+	// Ambil status like pengguna saat ini untuk seluruh komentar sekaligus
+	commentIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+	liked := likedCommentIDs(cc.DB, currentUser.ID, commentIDs)
+	mentions := mentionEntities(cc.DB, models.MentionSourceComment, commentIDs)
+
 	// Membuat slice untuk menyimpan respons yang sesuai dengan spesifikasi OpenAPI
 	var responseData []gin.H
 	for _, comment := range comments {
-		responseData = append(responseData, gin.H{
-			"id":       comment.ID,
-			"message":  comment.Message,
-			"photo_id": comment.PhotoID,
-			"user_id":  comment.UserID,
+		item := gin.H{
+			"id":          comment.ID,
+			"message":     comment.Message,
+			"photo_id":    comment.PhotoID,
+			"user_id":     comment.UserID,
+			"like_count":  comment.LikeCount,
+			"liked_by_me": liked[comment.ID],
+			"parent_id":   comment.ParentID,
+			"depth":       comment.Depth,
+			"reply_count": comment.ReplyCount,
+			"is_deleted":  comment.Tombstoned,
+			"status":      comment.Status,
+			"mentions":    mentionsOrEmpty(mentions[comment.ID]),
 			"user": gin.H{
 				"id":       comment.User.ID,
 				"email":    comment.User.Email,
@@ -90,29 +252,46 @@ func (cc *CommentController) GetComment(ctx *gin.Context) {
 				"photo_url": comment.Photo.PhotoURL,
 				"user_id":   comment.Photo.UserID,
 			},
-		})
+		}
+		hideTombstonedAuthor(item, comment)
+		responseData = append(responseData, item)
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
 }
 
-// GetCommentByID digunakan untuk menangani permintaan untuk mendapatkan komentar berdasarkan ID
+// GetCommentByID digunakan untuk menangani permintaan untuk mendapatkan komentar berdasarkan ID. ETag respons dapat
+// dipakai untuk If-None-Match dan If-Match.
 func (cc *CommentController) GetCommentByID(ctx *gin.Context) {
 	commentID := ctx.Param("commentId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
 
 	var comment models.Comment
 	result := cc.DB.Preload("User").Preload("Photo").First(&comment, "id = ?", commentID)
-	if result.Error != nil {
+	if result.Error != nil || isBlocked(cc.DB, currentUser.ID, comment.UserID) || isBlocked(cc.DB, currentUser.ID, comment.Photo.UserID) ||
+		!canViewComment(currentUser, comment, comment.Photo.UserID) || !canViewPhoto(cc.DB, currentUser, comment.Photo) {
 		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada komentar dengan ID tersebut."})
 		return
 	}
 
+	liked := likedCommentIDs(cc.DB, currentUser.ID, []int64{comment.ID})
+	mentions := mentionEntities(cc.DB, models.MentionSourceComment, []int64{comment.ID})
+
 	// Membuat objek JSON yang sesuai dengan spesifikasi OpenAPI
 	responseData := gin.H{
-		"id":       comment.ID,
-		"message":  comment.Message,
-		"photo_id": comment.PhotoID,
-		"user_id":  comment.UserID,
+		"id":          comment.ID,
+		"message":     comment.Message,
+		"photo_id":    comment.PhotoID,
+		"user_id":     comment.UserID,
+		"like_count":  comment.LikeCount,
+		"liked_by_me": liked[comment.ID],
+		"parent_id":   comment.ParentID,
+		"depth":       comment.Depth,
+		"reply_count": comment.ReplyCount,
+		"is_deleted":  comment.Tombstoned,
+		"status":      comment.Status,
+		"is_pinned":   comment.PinnedAt != nil,
+		"mentions":    mentionsOrEmpty(mentions[comment.ID]),
 		"user": gin.H{
 			"id":       comment.User.ID,
 			"email":    comment.User.Email,
@@ -126,8 +305,10 @@ func (cc *CommentController) GetCommentByID(ctx *gin.Context) {
 			"user_id":   comment.Photo.UserID,
 		},
 	}
+	hideTombstonedAuthor(responseData, comment)
+	addOwnerHiddenFlag(responseData, comment, currentUser, comment.Photo.UserID)
 
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+	respondWithETag(ctx, comment.Version, gin.H{"status": "success", "data": responseData})
 }
 
 // UpdateComment digunakan untuk menangani permintaan untuk memperbarui komentar
@@ -147,21 +328,113 @@ func (cc *CommentController) UpdateComment(ctx *gin.Context) {
 		return
 	}
 
+	updatedComment, ok := cc.findOwnComment(ctx, commentID, currentUser)
+	if !ok {
+		return
+	}
+	cc.saveCommentMessage(ctx, currentUser, updatedComment, payload.Message)
+}
+
+// PatchComment digunakan untuk memperbarui komentar dengan JSON Merge Patch. Hanya message yang dapat diubah.
+func (cc *CommentController) PatchComment(ctx *gin.Context) {
+	commentID := ctx.Param("commentId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	patch, ok := bindMergePatch(ctx)
+	if !ok {
+		return
+	}
+
+	updatedComment, ok := cc.findOwnComment(ctx, commentID, currentUser)
+	if !ok {
+		return
+	}
+
+	message := updatedComment.Message
+	patch.String("message", &message, false)
+	if patch.Has("message") && message == "" {
+		patch.Fail("message", "Message harus diisi.")
+	}
+	if respondPatchErrors(ctx, patch) {
+		return
+	}
+
+	cc.saveCommentMessage(ctx, currentUser, updatedComment, message)
+}
+
+// findOwnComment mengambil komentar dengan ID commentID milik currentUser yang belum dihapus dan versinya cocok dengan If-Match.
+// Jika komentar tidak ditemukan, bukan milik currentUser, atau sudah diubah, respons error langsung dikirim dan fungsi mengembalikan false.
+func (cc *CommentController) findOwnComment(ctx *gin.Context, commentID string, currentUser models.User) (models.Comment, bool) {
 	var updatedComment models.Comment
 	result := cc.DB.First(&updatedComment, "id = ?", commentID)
 	if result.Error != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
-		return
+		return updatedComment, false
+	}
+
+	// Komentar yang sudah dihapus tidak dapat diedit kembali
+	if updatedComment.Tombstoned {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+		return updatedComment, false
 	}
 
 	// Periksa apakah pengguna yang sedang masuk adalah pemilik komentar yang akan diperbarui
 	if updatedComment.UserID != currentUser.ID {
 		ctx.JSON(http.StatusForbidden, gin.H{"message": "Kamu tidak diizinkan untuk mengedit komentar ini."})
+		return updatedComment, false
+	}
+	return updatedComment, checkIfMatch(ctx, updatedComment.Version)
+}
+
+// saveCommentMessage mengganti isi komentar setelah diperiksa filter konten, menyimpannya beserta mention-nya,
+// lalu mengirim komentar yang diperbarui sebagai respons
+func (cc *CommentController) saveCommentMessage(ctx *gin.Context, currentUser models.User, updatedComment models.Comment, message string) {
+	// Isi komentar yang diedit diperiksa ulang oleh filter konten
+	verdict := cc.Filter.Check(ctx.Request.Context(), contentfilter.Input{
+		UserID:    currentUser.ID,
+		PhotoID:   updatedComment.PhotoID,
+		CommentID: updatedComment.ID,
+		Text:      message,
+	})
+	if verdict.Decision == contentfilter.Reject {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"message": "Komentar ditolak oleh filter konten.", "reasons": verdict.Reasons})
+		return
+	}
+
+	wasPending := updatedComment.Status == models.CommentPending
+	updatedComment.Message = message
+	updatedComment.Status = models.CommentPublished
+	updatedComment.FilterNote = ""
+	if verdict.Decision == contentfilter.Hold {
+		updatedComment.Status = models.CommentPending
+		updatedComment.FilterNote = strings.Join(verdict.Reasons, "; ")
+	}
+	err := cc.DB.Transaction(func(tx *gorm.DB) error {
+		// Kolom penghitung dikecualikan agar tidak menimpa perubahan yang terjadi bersamaan
+		if err := saveVersioned(tx, &updatedComment, &updatedComment.Version, "LikeCount", "ReplyCount"); err != nil {
+			return err
+		}
+		switch {
+		case updatedComment.Status == models.CommentPending:
+			// Mention pada komentar yang ditahan baru diberitahukan setelah komentar disetujui
+			return deleteMentions(tx, models.MentionSourceComment, updatedComment.ID)
+		case wasPending:
+			return publishStoredComment(tx, updatedComment, currentUser)
+		default:
+			return syncMentions(tx, models.MentionSourceComment, updatedComment.ID, currentUser, updatedComment.Message)
+		}
+	})
+	if errors.Is(err, errVersionConflict) {
+		respondVersionConflict(ctx, nil)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
 		return
 	}
 
-	updatedComment.Message = payload.Message
-	cc.DB.Save(&updatedComment)
+	setETag(ctx, updatedComment.Version)
+	mentions := mentionEntities(cc.DB, models.MentionSourceComment, []int64{updatedComment.ID})
 
 	// Membuat objek JSON yang sesuai dengan spesifikasi OpenAPI
 	responseData := gin.H{
@@ -169,6 +442,8 @@ func (cc *CommentController) UpdateComment(ctx *gin.Context) {
 		"message":  updatedComment.Message,
 		"photo_id": updatedComment.PhotoID,
 		"user_id":  updatedComment.UserID,
+		"status":   updatedComment.Status,
+		"mentions": mentionsOrEmpty(mentions[updatedComment.ID]),
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
@@ -180,23 +455,520 @@ func (cc *CommentController) DeleteComment(ctx *gin.Context) {
 	currentUser := ctx.MustGet("currentUser").(models.User)
 
 	var comment models.Comment
-	result := cc.DB.First(&comment, "id = ?", commentID)
+	result := cc.DB.Preload("Photo").First(&comment, "id = ?", commentID)
 	if result.Error != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
 		return
 	}
 
-	// Periksa apakah pengguna yang sedang masuk adalah pemilik komentar yang akan dihapus
-	if comment.UserID != currentUser.ID {
+	if comment.Tombstoned || !canViewPhoto(cc.DB, currentUser, comment.Photo) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+		return
+	}
+
+	// Komentar hanya dapat dihapus oleh penulisnya atau pemilik foto
+	if comment.UserID != currentUser.ID && comment.Photo.UserID != currentUser.ID {
 		ctx.JSON(http.StatusForbidden, gin.H{"message": "Kamu tidak diizinkan untuk menghapus komentar ini."})
 		return
 	}
+	if !checkIfMatch(ctx, comment.Version) {
+		return
+	}
 
-	result = cc.DB.Delete(&comment)
-	if result.RowsAffected == 0 {
-		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+	err := cc.DB.Transaction(func(tx *gorm.DB) error {
+		if ctx.GetHeader("If-Match") != "" {
+			if err := lockVersion(tx, &models.Comment{}, comment.ID, comment.Version); err != nil {
+				return err
+			}
+		}
+		if err := removeComment(tx, comment, currentUser.ID); err != nil {
+			return err
+		}
+		entry := auditEntry(ctx, &currentUser.ID, models.AuditCommentDelete, models.TargetComment, comment.ID)
+		entry.Before = commentAuditFields(comment)
+		return audit.Record(tx, entry)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+			return
+		}
+		if errors.Is(err, errVersionConflict) {
+			respondVersionConflict(ctx, nil)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
 }
+
+// GetPhotoComments digunakan untuk menangani permintaan untuk mendapatkan komentar utama pada sebuah foto
+// dengan pagination berbasis cursor. Urutan yang didukung: oldest (default), newest, dan top.
+func (cc *CommentController) GetPhotoComments(ctx *gin.Context) {
+	photoID := ctx.Param("photoId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	_, limit, _ := parsePagination(ctx)
+	order := ctx.DefaultQuery("order", "oldest")
+
+	var orderClause string
+	switch order {
+	case "oldest":
+		orderClause = "id ASC"
+	case "newest":
+		orderClause = "id DESC"
+	case "top":
+		orderClause = "like_count DESC, id DESC"
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Order harus salah satu dari oldest, newest, atau top."})
+		return
+	}
+
+	var photo models.Photo
+	if err := cc.DB.First(&photo, "id = ?", photoID).Error; err != nil || isBlocked(cc.DB, currentUser.ID, photo.UserID) || !canViewPhoto(cc.DB, currentUser, photo) {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada photo dengan ID tersebut."})
+		return
+	}
+
+	hidden := blockedUserIDs(cc.DB, currentUser.ID)
+	topLevel := func(db *gorm.DB) *gorm.DB {
+		db = db.Preload("User").Scopes(visibleComments(currentUser)).Where("photo_id = ? AND parent_id IS NULL", photo.ID)
+		if len(hidden) > 0 {
+			db = db.Where("user_id NOT IN ?", hidden)
+		}
+		return db
+	}
+
+	// Komentar yang disematkan ditampilkan di awal halaman pertama dan tidak diulang pada halaman berikutnya
+	cursorParam := ctx.Query("cursor")
+	var pinned []models.Comment
+	if cursorParam == "" {
+		if err := cc.DB.Scopes(topLevel).Where("pinned_at IS NOT NULL").Order("pinned_at ASC").Find(&pinned).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+	}
+
+	query := cc.DB.Scopes(topLevel).Where("pinned_at IS NULL")
+	if cursorParam != "" {
+		cursor, err := decodeCursor(cursorParam)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		switch order {
+		case "oldest":
+			query = query.Where("id > ?", cursor.ID)
+		case "newest":
+			query = query.Where("id < ?", cursor.ID)
+		case "top":
+			query = query.Where("(like_count, id) < (?, ?)", cursor.Key, cursor.ID)
+		}
+	}
+
+	// Ambil satu data lebih banyak untuk mengetahui apakah masih ada halaman berikutnya
+	var comments []models.Comment
+	if err := query.Order(orderClause).Limit(limit + 1).Find(&comments).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	hasMore := len(comments) > limit
+	if hasMore {
+		comments = comments[:limit]
+	}
+
+	var nextCursor interface{}
+	if hasMore {
+		last := comments[len(comments)-1]
+		nextCursor = encodeCursor(commentCursor{Key: last.LikeCount, ID: last.ID})
+	}
+	comments = append(pinned, comments...)
+
+	commentIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+	liked := likedCommentIDs(cc.DB, currentUser.ID, commentIDs)
+	mentions := mentionEntities(cc.DB, models.MentionSourceComment, commentIDs)
+
+	responseData := []gin.H{}
+	for _, comment := range comments {
+		item := gin.H{
+			"id":          comment.ID,
+			"message":     comment.Message,
+			"photo_id":    comment.PhotoID,
+			"user_id":     comment.UserID,
+			"like_count":  comment.LikeCount,
+			"liked_by_me": liked[comment.ID],
+			"parent_id":   comment.ParentID,
+			"depth":       comment.Depth,
+			"reply_count": comment.ReplyCount,
+			"is_deleted":  comment.Tombstoned,
+			"status":      comment.Status,
+			"is_pinned":   comment.PinnedAt != nil,
+			"mentions":    mentionsOrEmpty(mentions[comment.ID]),
+			"created_at":  comment.CreatedAt,
+			"user": gin.H{
+				"id":       comment.User.ID,
+				"username": comment.User.Username,
+			},
+		}
+		hideTombstonedAuthor(item, comment)
+		addOwnerHiddenFlag(item, comment, currentUser, photo.UserID)
+		responseData = append(responseData, item)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   responseData,
+		"pagination": gin.H{
+			"limit":       limit,
+			"order":       order,
+			"next_cursor": nextCursor,
+			"has_more":    hasMore,
+		},
+	})
+}
+
+// GetReplies digunakan untuk menangani permintaan untuk mendapatkan balasan langsung dari sebuah komentar dengan opsi paging
+func (cc *CommentController) GetReplies(ctx *gin.Context) {
+	commentID := ctx.Param("commentId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	var parent models.Comment
+	if err := cc.DB.Preload("Photo").First(&parent, "id = ?", commentID).Error; err != nil || isBlocked(cc.DB, currentUser.ID, parent.Photo.UserID) ||
+		!canViewComment(currentUser, parent, parent.Photo.UserID) || !canViewPhoto(cc.DB, currentUser, parent.Photo) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+		return
+	}
+
+	query := cc.DB.Model(&models.Comment{}).Scopes(visibleComments(currentUser)).Where("parent_id = ?", parent.ID)
+	if hidden := blockedUserIDs(cc.DB, currentUser.ID); len(hidden) > 0 {
+		query = query.Where("user_id NOT IN ?", hidden)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var replies []models.Comment
+	result := query.Preload("User").
+		Order("created_at ASC, id ASC").Limit(limit).Offset(offset).Find(&replies)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	replyIDs := make([]int64, 0, len(replies))
+	for _, reply := range replies {
+		replyIDs = append(replyIDs, reply.ID)
+	}
+	liked := likedCommentIDs(cc.DB, currentUser.ID, replyIDs)
+	mentions := mentionEntities(cc.DB, models.MentionSourceComment, replyIDs)
+
+	responseData := []gin.H{}
+	for _, reply := range replies {
+		item := gin.H{
+			"id":          reply.ID,
+			"message":     reply.Message,
+			"photo_id":    reply.PhotoID,
+			"user_id":     reply.UserID,
+			"like_count":  reply.LikeCount,
+			"liked_by_me": liked[reply.ID],
+			"parent_id":   reply.ParentID,
+			"depth":       reply.Depth,
+			"reply_count": reply.ReplyCount,
+			"is_deleted":  reply.Tombstoned,
+			"status":      reply.Status,
+			"mentions":    mentionsOrEmpty(mentions[reply.ID]),
+			"user": gin.H{
+				"id":       reply.User.ID,
+				"username": reply.User.Username,
+			},
+		}
+		hideTombstonedAuthor(item, reply)
+		addOwnerHiddenFlag(item, reply, currentUser, parent.Photo.UserID)
+		responseData = append(responseData, item)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// HideComment digunakan pemilik foto untuk menyembunyikan komentar pada fotonya.
+// Komentar yang disembunyikan tetap terlihat oleh penulisnya, pemilik foto, dan moderator.
+func (cc *CommentController) HideComment(ctx *gin.Context) {
+	cc.setOwnerHidden(ctx, true)
+}
+
+// UnhideComment digunakan pemilik foto untuk menampilkan kembali komentar yang disembunyikannya
+func (cc *CommentController) UnhideComment(ctx *gin.Context) {
+	cc.setOwnerHidden(ctx, false)
+}
+
+// PinComment digunakan pemilik foto untuk menyematkan komentar utama di awal daftar komentar fotonya
+func (cc *CommentController) PinComment(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	comment, ok := cc.findPhotoOwnerComment(ctx, currentUser)
+	if !ok {
+		return
+	}
+	if comment.ParentID != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Hanya komentar utama yang dapat disematkan."})
+		return
+	}
+	if comment.Status != models.CommentPublished || comment.HiddenAt != nil || comment.OwnerHiddenAt != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Komentar yang disembunyikan atau menunggu peninjauan tidak dapat disematkan."})
+		return
+	}
+
+	if comment.PinnedAt == nil {
+		err := cc.DB.Transaction(func(tx *gorm.DB) error {
+			// Kunci baris foto agar batas jumlah komentar yang disematkan tetap terjaga saat permintaan bersamaan
+			var photo models.Photo
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&photo, "id = ?", comment.PhotoID).Error; err != nil {
+				return err
+			}
+			var pinnedCount int64
+			if err := tx.Model(&models.Comment{}).Where("photo_id = ? AND pinned_at IS NOT NULL", photo.ID).Count(&pinnedCount).Error; err != nil {
+				return err
+			}
+			if pinnedCount >= maxPinnedComments {
+				return errPinLimitReached
+			}
+			return tx.Model(&comment).UpdateColumn("pinned_at", time.Now()).Error
+		})
+		if errors.Is(err, errPinLimitReached) {
+			ctx.JSON(http.StatusConflict, gin.H{"message": fmt.Sprintf("Maksimal %d komentar dapat disematkan pada satu photo.", maxPinnedComments)})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"id": comment.ID, "is_pinned": true}})
+}
+
+// UnpinComment digunakan pemilik foto untuk melepas komentar yang disematkan
+func (cc *CommentController) UnpinComment(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	comment, ok := cc.findPhotoOwnerComment(ctx, currentUser)
+	if !ok {
+		return
+	}
+
+	if err := cc.DB.Model(&comment).UpdateColumn("pinned_at", nil).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"id": comment.ID, "is_pinned": false}})
+}
+
+// setOwnerHidden menyembunyikan atau menampilkan kembali komentar atas nama pemilik foto.
+// Komentar yang disembunyikan juga dilepas dari sematan.
+func (cc *CommentController) setOwnerHidden(ctx *gin.Context, hidden bool) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	comment, ok := cc.findPhotoOwnerComment(ctx, currentUser)
+	if !ok {
+		return
+	}
+	if hidden && comment.UserID == currentUser.ID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Kamu tidak dapat menyembunyikan komentarmu sendiri."})
+		return
+	}
+
+	updates := map[string]interface{}{"owner_hidden_at": nil}
+	if hidden {
+		updates["owner_hidden_at"] = time.Now()
+		updates["pinned_at"] = nil
+	}
+	if err := cc.DB.Model(&comment).UpdateColumns(updates).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"id": comment.ID, "is_hidden": hidden}})
+}
+
+// findPhotoOwnerComment mengambil komentar pada parameter commentId dan memastikan pengguna saat ini adalah pemilik fotonya
+func (cc *CommentController) findPhotoOwnerComment(ctx *gin.Context, currentUser models.User) (models.Comment, bool) {
+	var comment models.Comment
+	err := cc.DB.Preload("Photo").First(&comment, "id = ?", ctx.Param("commentId")).Error
+	if err != nil || comment.Tombstoned || !canViewComment(currentUser, comment, comment.Photo.UserID) || !canViewPhoto(cc.DB, currentUser, comment.Photo) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+		return comment, false
+	}
+	if comment.Photo.UserID != currentUser.ID {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": "Hanya pemilik photo yang dapat mengatur komentar ini."})
+		return comment, false
+	}
+	return comment, true
+}
+
+// canCommentOn menentukan apakah user boleh mengomentari photo sesuai pengaturan komentar foto tersebut.
+// Pemilik foto selalu boleh berkomentar. Jika tidak boleh, pesan penolakan ikut dikembalikan.
+func canCommentOn(db *gorm.DB, user models.User, photo models.Photo) (string, bool) {
+	if photo.UserID == user.ID {
+		return "", true
+	}
+
+	switch photo.CommentPolicy {
+	case models.CommentPolicyNobody:
+		return "Komentar pada photo ini dinonaktifkan.", false
+	case models.CommentPolicyFollowers:
+		if !isFollowing(db, user.ID, photo.UserID) {
+			return "Hanya pengikut pemilik photo yang dapat berkomentar.", false
+		}
+	}
+	return "", true
+}
+
+// addOwnerHiddenFlag menambahkan penanda is_hidden pada respons komentar untuk pemilik foto dan moderator
+func addOwnerHiddenFlag(item gin.H, comment models.Comment, viewer models.User, photoOwnerID int64) {
+	if viewer.ID == photoOwnerID || viewer.IsModerator() {
+		item["is_hidden"] = comment.OwnerHiddenAt != nil
+	}
+}
+
+// hideTombstonedAuthor menyembunyikan pesan dan penulis dari respons komentar yang sudah dihapus
+func hideTombstonedAuthor(item gin.H, comment models.Comment) {
+	if comment.Tombstoned {
+		item["message"] = ""
+		item["user_id"] = nil
+		item["user"] = nil
+	}
+}
+
+// publishComment menjalankan efek samping publikasi komentar: mention, event real-time, dan domain event CommentCreated.
+// Dipanggil saat komentar dibuat, atau saat komentar yang ditahan filter konten akhirnya dipublikasikan.
+func publishComment(tx *gorm.DB, comment models.Comment, author models.User, photoUserID, parentAuthorID int64) error {
+	if err := syncMentions(tx, models.MentionSourceComment, comment.ID, author, comment.Message); err != nil {
+		return err
+	}
+	err := realtime.Publish(tx, realtime.Event{
+		Type:    realtime.EventCommentCreated,
+		PhotoID: comment.PhotoID,
+		Data: gin.H{
+			"id":        comment.ID,
+			"message":   comment.Message,
+			"photo_id":  comment.PhotoID,
+			"parent_id": comment.ParentID,
+			"depth":     comment.Depth,
+			"user": gin.H{
+				"id":       author.ID,
+				"username": author.Username,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return events.Record(tx, events.Event{
+		Type:          events.CommentCreated,
+		AggregateType: models.TargetComment,
+		AggregateID:   comment.ID,
+		ActorID:       author.ID,
+		Data: map[string]interface{}{
+			"id":             comment.ID,
+			"message":        comment.Message,
+			"photo_id":       comment.PhotoID,
+			"photo_user_id":  photoUserID,
+			"parent_id":      comment.ParentID,
+			"parent_user_id": parentAuthorID,
+			"user_id":        comment.UserID,
+			"created_at":     comment.CreatedAt,
+		},
+	})
+}
+
+// publishStoredComment menjalankan publishComment untuk komentar yang sudah tersimpan dengan mengambil pemilik foto dan penulis komentar induk
+func publishStoredComment(tx *gorm.DB, comment models.Comment, author models.User) error {
+	var photo models.Photo
+	if err := tx.Select("id", "user_id").First(&photo, "id = ?", comment.PhotoID).Error; err != nil {
+		return err
+	}
+	var parentAuthorID int64
+	if comment.ParentID != nil {
+		var parent models.Comment
+		if err := tx.Select("id", "user_id").First(&parent, "id = ?", *comment.ParentID).Error; err != nil {
+			return err
+		}
+		parentAuthorID = parent.UserID
+	}
+	return publishComment(tx, comment, author, photo.UserID, parentAuthorID)
+}
+
+// removeComment menghapus komentar dengan deleteCommentThreadAware dan mencatat domain event atas nama actorID
+func removeComment(tx *gorm.DB, comment models.Comment, actorID int64) error {
+	if err := deleteCommentThreadAware(tx, comment.ID, actorID); err != nil {
+		return err
+	}
+	return events.Record(tx, events.Event{
+		Type:          events.CommentDeleted,
+		AggregateType: models.TargetComment,
+		AggregateID:   comment.ID,
+		ActorID:       actorID,
+		Data:          map[string]interface{}{"id": comment.ID, "photo_id": comment.PhotoID, "parent_id": comment.ParentID},
+	})
+}
+
+// deleteCommentThreadAware menghapus komentar atas nama actorID tanpa menghapus balasannya.
+// Komentar yang masih memiliki balasan diubah menjadi tombstone (pesan disembunyikan) agar thread tetap utuh,
+// sedangkan komentar tanpa balasan dipindahkan ke tempat sampah. Induk tombstone yang sudah tidak memiliki balasan ikut dibersihkan.
+// Pesan dan like tetap disimpan agar komentar dapat dipulihkan selama masa retensi.
+func deleteCommentThreadAware(tx *gorm.DB, commentID int64, actorID int64) error {
+	var comment models.Comment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&comment, "id = ?", commentID).Error; err != nil {
+		return err
+	}
+
+	if err := deleteMentions(tx, models.MentionSourceComment, comment.ID); err != nil {
+		return err
+	}
+
+	// Induk tombstone yang ikut dibersihkan tetap dicatat atas nama penghapus aslinya
+	deletedByID := actorID
+	if comment.Tombstoned && comment.DeletedByID != nil {
+		deletedByID = *comment.DeletedByID
+	}
+
+	now := time.Now()
+	if comment.ReplyCount > 0 {
+		return tx.Model(&comment).Updates(map[string]interface{}{
+			"tombstoned":    true,
+			"tombstoned_at": now,
+			"deleted_by_id": deletedByID,
+			"pinned_at":     nil,
+			"updated_at":    now,
+		}).Error
+	}
+
+	if err := tx.Model(&comment).UpdateColumns(map[string]interface{}{"deleted_by_id": deletedByID, "pinned_at": nil}).Error; err != nil {
+		return err
+	}
+	if err := tx.Delete(&comment).Error; err != nil {
+		return err
+	}
+	if comment.ParentID == nil {
+		return nil
+	}
+
+	var parent models.Comment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, "id = ?", *comment.ParentID).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&parent).UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count - 1, 0)")).Error; err != nil {
+		return err
+	}
+	if parent.Tombstoned && parent.ReplyCount <= 1 {
+		return deleteCommentThreadAware(tx, parent.ID, actorID)
+	}
+	return nil
+}
@@ -0,0 +1,545 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/models"
+	"mygram-final-project/realtime"
+)
+
+const (
+	maxConversationMembers = 10   // Jumlah anggota maksimal sebuah percakapan, termasuk pembuatnya
+	maxMessageLength       = 1000 // Panjang maksimal isi pesan dalam karakter
+)
+
+// ConversationController adalah kontroler untuk pesan pribadi antar pengguna
+type ConversationController struct {
+	DB *gorm.DB
+}
+
+// NewConversationController digunakan untuk membuat instance baru dari ConversationController
+func NewConversationController(DB *gorm.DB) ConversationController {
+	return ConversationController{DB}
+}
+
+// CreateConversation digunakan untuk membuat percakapan dengan pengguna lain.
+// Percakapan 1:1 tanpa judul yang sudah ada dengan pengguna yang sama akan dikembalikan alih-alih dibuat ulang.
+func (cc *ConversationController) CreateConversation(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.CreateConversationRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	usernames := map[string]bool{}
+	for _, username := range payload.Usernames {
+		usernames[strings.ToLower(strings.TrimSpace(username))] = true
+	}
+	delete(usernames, strings.ToLower(currentUser.Username))
+	if len(usernames) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Kamu tidak dapat membuat percakapan dengan diri sendiri."})
+		return
+	}
+	if len(usernames)+1 > maxConversationMembers {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": fmt.Sprintf("Percakapan maksimal berisi %d anggota.", maxConversationMembers)})
+		return
+	}
+
+	names := make([]string, 0, len(usernames))
+	for username := range usernames {
+		names = append(names, username)
+	}
+
+	var others []models.User
+	if err := cc.DB.Where("LOWER(username) IN ?", names).Find(&others).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+	if len(others) != len(names) {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada user dengan username tersebut."})
+		return
+	}
+
+	for _, other := range others {
+		if isBlocked(cc.DB, currentUser.ID, other.ID) {
+			ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu tidak dapat memulai percakapan dengan pengguna ini."})
+			return
+		}
+	}
+
+	now := time.Now()
+	conversation := models.Conversation{
+		IsGroup:   len(others) > 1 || payload.Title != "",
+		Title:     strings.TrimSpace(payload.Title),
+		CreatorID: currentUser.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if !conversation.IsGroup {
+		conversation.DirectKey = directConversationKey(currentUser.ID, others[0].ID)
+	}
+
+	created := false
+	err := cc.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "direct_key"}}, DoNothing: true}).Create(&conversation)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Percakapan 1:1 dengan pengguna ini sudah ada
+			return tx.First(&conversation, "direct_key = ?", *conversation.DirectKey).Error
+		}
+		created = true
+
+		members := []models.ConversationMember{{ConversationID: conversation.ID, UserID: currentUser.ID, JoinedAt: now}}
+		for _, other := range others {
+			members = append(members, models.ConversationMember{ConversationID: conversation.ID, UserID: other.ID, JoinedAt: now})
+		}
+		return tx.Create(&members).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	cc.respondConversation(ctx, status, conversation.ID, currentUser)
+}
+
+// GetConversations mengambil daftar percakapan pengguna saat ini, diurutkan dari pesan terbaru, dengan opsi paging
+func (cc *ConversationController) GetConversations(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	query := cc.DB.Model(&models.Conversation{}).
+		Where("id IN (?)", cc.DB.Model(&models.ConversationMember{}).Select("conversation_id").Where("user_id = ?", currentUser.ID))
+
+	var total int64
+	query.Count(&total)
+
+	var conversations []models.Conversation
+	result := query.Preload("Members.User").
+		Order("COALESCE(last_message_at, created_at) DESC, id DESC").
+		Limit(limit).Offset(offset).Find(&conversations)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ids := make([]int64, 0, len(conversations))
+	for _, conversation := range conversations {
+		ids = append(ids, conversation.ID)
+	}
+	hidden := blockedUserIDs(cc.DB, currentUser.ID)
+	lastMessages := cc.lastMessages(ids, hidden)
+	unread := cc.unreadCounts(ids, currentUser.ID, hidden)
+
+	responseData := []gin.H{}
+	for _, conversation := range conversations {
+		item := conversationResponse(conversation, unread[conversation.ID])
+		if message, ok := lastMessages[conversation.ID]; ok {
+			item["last_message"] = cc.messageResponse(currentUser, message, conversation.Members)
+		}
+		responseData = append(responseData, item)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// GetConversationByID mengambil detail percakapan beserta anggota dan posisi baca masing-masing
+func (cc *ConversationController) GetConversationByID(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	conversationID, ok := cc.findMembership(ctx, currentUser)
+	if !ok {
+		return
+	}
+
+	cc.respondConversation(ctx, http.StatusOK, conversationID, currentUser)
+}
+
+// GetMessages mengambil riwayat pesan percakapan dari yang terbaru dengan pagination berbasis cursor.
+// Query before berisi ID pesan paling lama dari halaman sebelumnya.
+func (cc *ConversationController) GetMessages(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	_, limit, _ := parsePagination(ctx)
+
+	conversationID, ok := cc.findMembership(ctx, currentUser)
+	if !ok {
+		return
+	}
+
+	query := cc.DB.Preload("Sender").Preload("Photo").Where("conversation_id = ?", conversationID)
+	if before := ctx.Query("before"); before != "" {
+		beforeID, err := strconv.ParseInt(before, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": "Format before tidak valid."})
+			return
+		}
+		query = query.Where("id < ?", beforeID)
+	}
+	if hidden := blockedUserIDs(cc.DB, currentUser.ID); len(hidden) > 0 {
+		query = query.Where("sender_id NOT IN ?", hidden)
+	}
+
+	var messages []models.Message
+	if err := query.Order("id DESC").Limit(limit).Find(&messages).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	var members []models.ConversationMember
+	cc.DB.Where("conversation_id = ?", conversationID).Find(&members)
+
+	responseData := []gin.H{}
+	for _, message := range messages {
+		responseData = append(responseData, cc.messageResponse(currentUser, message, members))
+	}
+
+	var nextCursor interface{}
+	if len(messages) == limit {
+		nextCursor = messages[len(messages)-1].ID
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   responseData,
+		"pagination": gin.H{
+			"limit":       limit,
+			"next_cursor": nextCursor,
+		},
+	})
+}
+
+// SendMessage digunakan untuk mengirim pesan teks dan/atau membagikan foto ke dalam percakapan.
+// Anggota yang sedang terhubung ke stream menerima pesan secara real-time.
+func (cc *ConversationController) SendMessage(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	conversationID, ok := cc.findMembership(ctx, currentUser)
+	if !ok {
+		return
+	}
+
+	var payload models.SendMessageRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	body := strings.TrimSpace(payload.Body)
+	if body == "" && payload.PhotoID == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Pesan harus berisi teks atau foto."})
+		return
+	}
+	if utf8.RuneCountInString(body) > maxMessageLength {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Pesan maksimal %d karakter.", maxMessageLength)})
+		return
+	}
+
+	var photo *models.Photo
+	if payload.PhotoID != nil {
+		photo = &models.Photo{}
+		if err := cc.DB.First(photo, "id = ?", *payload.PhotoID).Error; err != nil || isBlocked(cc.DB, currentUser.ID, photo.UserID) ||
+			!canViewPhoto(cc.DB, currentUser, *photo) {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada foto dengan ID tersebut."})
+			return
+		}
+	}
+
+	var conversation models.Conversation
+	if err := cc.DB.Preload("Members.User").First(&conversation, "id = ?", conversationID).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	// Pada percakapan 1:1, pemblokiran oleh salah satu pihak menghentikan pengiriman pesan
+	recipients := []models.ConversationMember{}
+	for _, member := range conversation.Members {
+		if member.UserID == currentUser.ID {
+			recipients = append(recipients, member)
+			continue
+		}
+		if isBlocked(cc.DB, currentUser.ID, member.UserID) {
+			if !conversation.IsGroup {
+				ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu tidak dapat mengirim pesan ke pengguna ini."})
+				return
+			}
+			continue
+		}
+		recipients = append(recipients, member)
+	}
+
+	now := time.Now()
+	message := models.Message{
+		ConversationID: conversationID,
+		SenderID:       currentUser.ID,
+		Body:           body,
+		PhotoID:        payload.PhotoID,
+		CreatedAt:      now,
+	}
+
+	err := cc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Conversation{}).Where("id = ?", conversationID).
+			UpdateColumns(map[string]interface{}{"last_message_at": now, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		// Pengirim otomatis sudah membaca pesannya sendiri
+		if err := tx.Model(&models.ConversationMember{}).
+			Where("conversation_id = ? AND user_id = ?", conversationID, currentUser.ID).
+			UpdateColumns(map[string]interface{}{"last_read_message_id": message.ID, "last_read_at": now}).Error; err != nil {
+			return err
+		}
+
+		message.Sender = currentUser
+		message.Photo = photo
+		for _, recipient := range recipients {
+			data := cc.messageResponse(recipient.User, message, conversation.Members)
+			event := realtime.Event{Type: realtime.EventMessageCreated, UserID: recipient.UserID, Data: data}
+			if err := realtime.Publish(tx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": cc.messageResponse(currentUser, message, conversation.Members)})
+}
+
+// MarkAsRead menandai percakapan sudah dibaca sampai pesan tertentu (default pesan terbaru) dan
+// mengirim tanda baca ke anggota lain. Posisi baca tidak pernah mundur.
+func (cc *ConversationController) MarkAsRead(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	conversationID, ok := cc.findMembership(ctx, currentUser)
+	if !ok {
+		return
+	}
+
+	var payload models.MarkConversationReadRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	var message models.Message
+	query := cc.DB.Where("conversation_id = ?", conversationID)
+	if payload.MessageID != 0 {
+		query = query.Where("id = ?", payload.MessageID)
+	}
+	if err := query.Order("id DESC").First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) && payload.MessageID == 0 {
+			// Percakapan belum memiliki pesan
+			ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"last_read_message_id": 0}})
+			return
+		}
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada pesan dengan ID tersebut."})
+		return
+	}
+
+	now := time.Now()
+	err := cc.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ConversationMember{}).
+			Where("conversation_id = ? AND user_id = ? AND last_read_message_id < ?", conversationID, currentUser.ID, message.ID).
+			UpdateColumns(map[string]interface{}{"last_read_message_id": message.ID, "last_read_at": now})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		var memberIDs []int64
+		if err := tx.Model(&models.ConversationMember{}).Where("conversation_id = ? AND user_id <> ?", conversationID, currentUser.ID).
+			Pluck("user_id", &memberIDs).Error; err != nil {
+			return err
+		}
+		data := gin.H{"conversation_id": conversationID, "user_id": currentUser.ID, "message_id": message.ID, "read_at": now}
+		for _, memberID := range memberIDs {
+			if isBlocked(tx, currentUser.ID, memberID) {
+				continue
+			}
+			if err := realtime.Publish(tx, realtime.Event{Type: realtime.EventMessageRead, UserID: memberID, Data: data}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"last_read_message_id": message.ID}})
+}
+
+// findMembership memastikan pengguna saat ini merupakan anggota percakapan pada parameter conversationId.
+// Percakapan milik orang lain diperlakukan seolah tidak ada.
+func (cc *ConversationController) findMembership(ctx *gin.Context, currentUser models.User) (int64, bool) {
+	conversationID, err := strconv.ParseInt(ctx.Param("conversationId"), 10, 64)
+	if err == nil {
+		var member models.ConversationMember
+		err = cc.DB.First(&member, "conversation_id = ? AND user_id = ?", conversationID, currentUser.ID).Error
+	}
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada percakapan dengan ID tersebut."})
+		return 0, false
+	}
+	return conversationID, true
+}
+
+// respondConversation mengirim detail percakapan beserta anggota, pesan terakhir, dan jumlah pesan belum dibaca
+func (cc *ConversationController) respondConversation(ctx *gin.Context, status int, conversationID int64, currentUser models.User) {
+	var conversation models.Conversation
+	if err := cc.DB.Preload("Members.User").First(&conversation, "id = ?", conversationID).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	hidden := blockedUserIDs(cc.DB, currentUser.ID)
+	item := conversationResponse(conversation, cc.unreadCounts([]int64{conversationID}, currentUser.ID, hidden)[conversationID])
+	if message, ok := cc.lastMessages([]int64{conversationID}, hidden)[conversationID]; ok {
+		item["last_message"] = cc.messageResponse(currentUser, message, conversation.Members)
+	}
+
+	ctx.JSON(status, gin.H{"status": "success", "data": item})
+}
+
+// lastMessages mengambil pesan terakhir setiap percakapan, tanpa pesan dari pengguna pada hidden
+func (cc *ConversationController) lastMessages(conversationIDs []int64, hidden []int64) map[int64]models.Message {
+	result := map[int64]models.Message{}
+	if len(conversationIDs) == 0 {
+		return result
+	}
+
+	query := cc.DB.Preload("Sender").Preload("Photo").
+		Select("DISTINCT ON (conversation_id) *").
+		Where("conversation_id IN ?", conversationIDs)
+	if len(hidden) > 0 {
+		query = query.Where("sender_id NOT IN ?", hidden)
+	}
+
+	var messages []models.Message
+	query.Order("conversation_id, id DESC").Find(&messages)
+	for _, message := range messages {
+		result[message.ConversationID] = message
+	}
+	return result
+}
+
+// unreadCounts menghitung pesan dari anggota lain yang belum dibaca userID pada setiap percakapan
+func (cc *ConversationController) unreadCounts(conversationIDs []int64, userID int64, hidden []int64) map[int64]int64 {
+	result := map[int64]int64{}
+	if len(conversationIDs) == 0 {
+		return result
+	}
+
+	query := cc.DB.Table("messages").
+		Select("messages.conversation_id, COUNT(*) AS count").
+		Joins("JOIN conversation_members ON conversation_members.conversation_id = messages.conversation_id AND conversation_members.user_id = ?", userID).
+		Where("messages.conversation_id IN ? AND messages.id > conversation_members.last_read_message_id AND messages.sender_id <> ?", conversationIDs, userID)
+	if len(hidden) > 0 {
+		query = query.Where("messages.sender_id NOT IN ?", hidden)
+	}
+
+	var rows []struct {
+		ConversationID int64
+		Count          int64
+	}
+	query.Group("messages.conversation_id").Scan(&rows)
+	for _, row := range rows {
+		result[row.ConversationID] = row.Count
+	}
+	return result
+}
+
+// directConversationKey membuat kunci unik percakapan 1:1 yang sama untuk kedua urutan pengguna
+func directConversationKey(userID int64, otherID int64) *string {
+	if userID > otherID {
+		userID, otherID = otherID, userID
+	}
+	key := fmt.Sprintf("%d:%d", userID, otherID)
+	return &key
+}
+
+// conversationResponse membuat data respons percakapan beserta anggota dan posisi baca masing-masing
+func conversationResponse(conversation models.Conversation, unreadCount int64) gin.H {
+	members := []gin.H{}
+	for _, member := range conversation.Members {
+		members = append(members, gin.H{
+			"id":                   member.User.ID,
+			"username":             member.User.Username,
+			"profile_image_url":    member.User.ProfileImageURL,
+			"last_read_message_id": member.LastReadMessageID,
+			"last_read_at":         member.LastReadAt,
+		})
+	}
+
+	return gin.H{
+		"id":              conversation.ID,
+		"is_group":        conversation.IsGroup,
+		"title":           conversation.Title,
+		"creator_id":      conversation.CreatorID,
+		"members":         members,
+		"unread_count":    unreadCount,
+		"last_message_at": conversation.LastMessageAt,
+		"created_at":      conversation.CreatedAt,
+	}
+}
+
+// messageResponse membuat data respons pesan untuk viewer. read_by berisi ID anggota lain yang sudah membaca pesan ini.
+// Foto yang dibagikan hanya disertakan jika viewer boleh melihatnya, misalnya belum disembunyikan, tidak dibatasi usia
+// untuk viewer, dan pemiliknya tidak berubah menjadi akun privat yang tidak diikuti viewer. Jika tidak, photo bernilai null.
+func (cc *ConversationController) messageResponse(viewer models.User, message models.Message, members []models.ConversationMember) gin.H {
+	readBy := []int64{}
+	for _, member := range members {
+		if member.UserID != message.SenderID && member.LastReadMessageID >= message.ID {
+			readBy = append(readBy, member.UserID)
+		}
+	}
+
+	var photo interface{}
+	if message.Photo != nil && canViewPhoto(cc.DB, viewer, *message.Photo) {
+		photo = gin.H{
+			"id":        message.Photo.ID,
+			"title":     message.Photo.Title,
+			"photo_url": message.Photo.PhotoURL,
+			"user_id":   message.Photo.UserID,
+		}
+	}
+
+	return gin.H{
+		"id":              message.ID,
+		"conversation_id": message.ConversationID,
+		"body":            message.Body,
+		"photo":           photo,
+		"read_by":         readBy,
+		"created_at":      message.CreatedAt,
+		"sender": gin.H{
+			"id":                message.Sender.ID,
+			"username":          message.Sender.Username,
+			"profile_image_url": message.Sender.ProfileImageURL,
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errVersionConflict dikembalikan saat data sudah diubah permintaan lain sejak dibaca
+var errVersionConflict = errors.New("versi data sudah berubah")
+
+// entityTag membuat ETag kuat dari versi data. ETag ini hanya mewakili field yang dapat diubah pemiliknya, bukan penghitung
+// seperti like_count, sehingga dipakai untuk If-Match dan dikirim pada respons perubahan data.
+func entityTag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// responseTag membuat ETag lemah untuk respons GET dari versi data dan hash seluruh isi respons, sehingga ETag ikut
+// berubah saat penghitung, status, atau data yang bergantung pada pengguna saat ini berubah. Versi di awal tag
+// memungkinkan ETag ini dipakai juga untuk If-Match.
+func responseTag(version int64, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + strconv.FormatInt(version, 10) + "-" + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// setETag menambahkan header ETag untuk versi data pada respons
+func setETag(ctx *gin.Context, version int64) {
+	ctx.Header("ETag", entityTag(version))
+}
+
+// respondWithETag mengirim body sebagai JSON dengan ETag dari responseTag, atau respons 304 tanpa isi jika salah satu
+// ETag pada If-None-Match masih sama. Respons bergantung pada pengguna yang login sehingga hanya boleh disimpan cache
+// milik pengguna tersebut.
+func respondWithETag(ctx *gin.Context, version int64, body gin.H) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	current := responseTag(version, data)
+	ctx.Header("ETag", current)
+	ctx.Header("Cache-Control", "private")
+	ctx.Header("Vary", "Authorization, Cookie")
+
+	if header := ctx.GetHeader("If-None-Match"); header != "" {
+		for _, tag := range strings.Split(header, ",") {
+			// If-None-Match memakai perbandingan lemah sehingga awalan W/ diabaikan
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == strings.TrimPrefix(current, "W/") {
+				ctx.Status(http.StatusNotModified)
+				return
+			}
+		}
+	}
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// responseTagVersion mengembalikan versi data dari ETag buatan responseTag
+func responseTagVersion(tag string) (int64, bool) {
+	if !strings.HasPrefix(tag, `W/"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, false
+	}
+	value, _, found := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(tag, `W/"`), `"`), "-")
+	if !found {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	return version, err == nil
+}
+
+// checkIfMatch memeriksa header If-Match terhadap versi data saat ini. Permintaan tanpa If-Match selalu lolos.
+// ETag versi dari respons perubahan dan ETag respons GET dari responseTag sama-sama diterima, dan keduanya dibandingkan
+// berdasarkan versinya saja.
+// Jika tidak ada ETag yang cocok, respons 412 dikirim dan fungsi mengembalikan false.
+func checkIfMatch(ctx *gin.Context, version int64) bool {
+	header := ctx.GetHeader("If-Match")
+	if header == "" {
+		return true
+	}
+	current := entityTag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+		if tagVersion, ok := responseTagVersion(tag); ok && tagVersion == version {
+			return true
+		}
+	}
+	respondVersionConflict(ctx, &version)
+	return false
+}
+
+// respondVersionConflict mengirim respons 412 untuk data yang sudah diubah. ETag versi terbaru ditambahkan jika diketahui.
+func respondVersionConflict(ctx *gin.Context, version *int64) {
+	if version != nil {
+		setETag(ctx, *version)
+	}
+	ctx.JSON(http.StatusPreconditionFailed, gin.H{"status": "fail", "message": "Data sudah diubah. Ambil versi terbaru lalu coba lagi."})
+}
+
+// saveVersioned menyimpan seluruh field value hanya jika versinya di basis data masih sama dengan *version, lalu menaikkan
+// *version. Field pada omit tidak ikut disimpan. errVersionConflict dikembalikan jika data sudah diubah permintaan lain.
+func saveVersioned(tx *gorm.DB, value interface{}, version *int64, omit ...string) error {
+	expected := *version
+	*version = expected + 1
+
+	query := tx.Select("*")
+	if len(omit) > 0 {
+		query = query.Omit(omit...)
+	}
+	result := query.Where("version = ?", expected).Save(value)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = errVersionConflict
+	}
+	if result.Error != nil {
+		*version = expected
+		return result.Error
+	}
+	return nil
+}
+
+// lockVersion mengunci baris model dengan ID id lalu memastikan versinya masih expected. Dipakai sebelum menghapus data
+// dengan If-Match. gorm.ErrRecordNotFound dikembalikan jika baris tidak ada, dan errVersionConflict jika versinya berubah.
+func lockVersion(tx *gorm.DB, model interface{}, id int64, expected int64) error {
+	var versions []int64
+	if err := tx.Model(model).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Pluck("version", &versions).Error; err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	if versions[0] != expected {
+		return errVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serveETag menjalankan respondWithETag untuk body dengan header If-None-Match ifNoneMatch
+func serveETag(version int64, body gin.H, ifNoneMatch string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if ifNoneMatch != "" {
+		ctx.Request.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	respondWithETag(ctx, version, body)
+	ctx.Writer.WriteHeaderNow()
+	return recorder
+}
+
+func TestRespondWithETag(t *testing.T) {
+	body := gin.H{"data": gin.H{"id": 1, "like_count": 3, "liked_by_me": true}}
+	first := serveETag(2, body, "")
+	tag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || first.Body.Len() == 0 {
+		t.Fatalf("respons pertama = %d dengan %d byte, ingin 200 dengan isi", first.Code, first.Body.Len())
+	}
+	if got := first.Header().Get("Cache-Control"); got != "private" {
+		t.Errorf("Cache-Control = %q, ingin private", got)
+	}
+	if got := first.Header().Get("Vary"); got != "Authorization, Cookie" {
+		t.Errorf("Vary = %q, ingin Authorization, Cookie", got)
+	}
+
+	changed := gin.H{"data": gin.H{"id": 1, "like_count": 4, "liked_by_me": true}}
+	tests := []struct {
+		name        string
+		version     int64
+		body        gin.H
+		ifNoneMatch string
+		want        int
+	}{
+		{"ETag sama", 2, body, tag, http.StatusNotModified},
+		{"ETag sama tanpa awalan W/", 2, body, tag[2:], http.StatusNotModified},
+		{"salah satu dari beberapa ETag", 2, body, `W/"1-abc", ` + tag, http.StatusNotModified},
+		{"wildcard", 2, body, "*", http.StatusNotModified},
+		{"like_count berubah", 2, changed, tag, http.StatusOK},
+		{"versi berubah", 3, body, tag, http.StatusOK},
+		{"ETag versi saja", 2, body, `"2"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serveETag(tt.version, tt.body, tt.ifNoneMatch)
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, ingin %d", recorder.Code, tt.want)
+			}
+			if tt.want == http.StatusNotModified && recorder.Body.Len() != 0 {
+				t.Errorf("respons 304 berisi %d byte", recorder.Body.Len())
+			}
+		})
+	}
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    bool
+	}{
+		{"tanpa If-Match", "", true},
+		{"ETag versi", `"5"`, true},
+		{"ETag versi lama", `"4"`, false},
+		{"ETag respons GET dengan versi sama", `W/"5-0123456789abcdef"`, true},
+		{"ETag respons GET dengan versi lama", `W/"4-0123456789abcdef"`, false},
+		{"ETag lemah tanpa hash", `W/"5"`, false},
+		{"awalan versi lain", `W/"55-0123"`, false},
+		{"wildcard", "*", true},
+		{"salah satu dari beberapa ETag", `"4", "5"`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				ctx.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+			if got := checkIfMatch(ctx, 5); got != tt.want {
+				t.Fatalf("checkIfMatch = %v, ingin %v", got, tt.want)
+			}
+			if !tt.want && recorder.Code != http.StatusPreconditionFailed {
+				t.Errorf("status = %d, ingin 412", recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"mygram-final-project/exports"
+	"mygram-final-project/jobs"
+	"mygram-final-project/models"
+	"mygram-final-project/storage"
+)
+
+// maxListedExports adalah jumlah permintaan ekspor terbaru yang ditampilkan kepada pengguna
+const maxListedExports = 10
+
+// ExportController adalah kontroler untuk ekspor data pribadi pengguna
+type ExportController struct {
+	DB     *gorm.DB
+	Store  storage.Store
+	Signer exports.Signer
+}
+
+// NewExportController digunakan untuk membuat instance baru dari ExportController
+func NewExportController(DB *gorm.DB, store storage.Store, signer exports.Signer) ExportController {
+	return ExportController{DB, store, signer}
+}
+
+// RequestExport meminta pembuatan arsip data pengguna saat ini. Arsip dibuat oleh worker dan pengguna diberi notifikasi saat siap.
+// Jika masih ada permintaan yang sedang diproses, permintaan tersebut yang dikembalikan.
+func (ec *ExportController) RequestExport(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var export models.DataExport
+	err := ec.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND status = ?", currentUser.ID, models.DataExportPending).First(&export).Error
+		if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		export = models.DataExport{UserID: currentUser.ID, Status: models.DataExportPending}
+		if err := tx.Create(&export).Error; err != nil {
+			return err
+		}
+		return jobs.Enqueue(tx, exports.JobBuild, exports.BuildPayload{ExportID: export.ID}, jobs.EnqueueOptions{
+			UniqueKey: fmt.Sprintf("data-export:%d", export.ID),
+		})
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal meminta ekspor data."})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"status": "success", "data": ec.exportResponse(export, time.Now())})
+}
+
+// GetExports mengambil permintaan ekspor terbaru milik pengguna saat ini beserta tautan unduhan untuk arsip yang siap
+func (ec *ExportController) GetExports(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var items []models.DataExport
+	if err := ec.DB.Where("user_id = ?", currentUser.ID).Order("id DESC").Limit(maxListedExports).Find(&items).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	now := time.Now()
+	responseData := []gin.H{}
+	for _, export := range items {
+		responseData = append(responseData, ec.exportResponse(export, now))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+}
+
+// DownloadExport mengirim arsip ekspor melalui tautan bertanda tangan. Tautan tidak memerlukan login sehingga dapat dibuka
+// langsung dari notifikasi, tetapi hanya berlaku sampai waktu expires pada tautan.
+func (ec *ExportController) DownloadExport(ctx *gin.Context) {
+	exportID, err := strconv.ParseInt(ctx.Param("exportId"), 10, 64)
+	if err != nil || !ec.Signer.Verify(exportID, ctx.Query("expires"), ctx.Query("signature")) {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": "Tautan unduhan tidak valid."})
+		return
+	}
+	expires, _ := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	now := time.Now()
+	if now.Unix() > expires {
+		ctx.JSON(http.StatusGone, gin.H{"message": "Tautan unduhan sudah kedaluwarsa."})
+		return
+	}
+
+	var export models.DataExport
+	if err := ec.DB.First(&export, exportID).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Arsip tidak ditemukan."})
+		return
+	}
+	if export.Status != models.DataExportReady || export.ExpiresAt == nil || now.After(*export.ExpiresAt) {
+		ctx.JSON(http.StatusGone, gin.H{"message": "Arsip sudah tidak tersedia."})
+		return
+	}
+
+	file, err := ec.Store.Open(ctx.Request.Context(), export.FileKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		ctx.JSON(http.StatusGone, gin.H{"message": "Arsip sudah tidak tersedia."})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+	defer file.Close()
+
+	ctx.DataFromReader(http.StatusOK, export.Size, "application/zip", file, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="mygram-data-%d.zip"`, export.ID),
+		"Cache-Control":       "no-store",
+	})
+}
+
+// exportResponse membuat respons permintaan ekspor. Arsip yang siap diberi tautan unduhan baru yang berlaku selama
+// exports.LinkTTL, tetapi tidak melewati masa berlaku arsip.
+func (ec *ExportController) exportResponse(export models.DataExport, now time.Time) gin.H {
+	response := gin.H{
+		"id":           export.ID,
+		"status":       export.Status,
+		"size":         export.Size,
+		"created_at":   export.CreatedAt,
+		"completed_at": export.CompletedAt,
+		"expires_at":   export.ExpiresAt,
+	}
+	if export.Status == models.DataExportReady && export.ExpiresAt != nil && now.Before(*export.ExpiresAt) {
+		linkExpires := now.Add(exports.LinkTTL)
+		if linkExpires.After(*export.ExpiresAt) {
+			linkExpires = *export.ExpiresAt
+		}
+		response["download_url"] = ec.Signer.DownloadURL(export.ID, linkExpires)
+		response["download_url_expires_at"] = linkExpires
+	}
+	return response
+}
@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/events"
+	"mygram-final-project/models"
+)
+
+// FollowController adalah kontroler untuk operasi mengikuti pengguna lain
+type FollowController struct {
+	DB *gorm.DB
+}
+
+// NewFollowController digunakan untuk membuat instance baru dari FollowController
+func NewFollowController(DB *gorm.DB) FollowController {
+	return FollowController{DB}
+}
+
+// Follow digunakan untuk mengikuti pengguna berdasarkan username. Permintaan berulang tidak membuat data ganda.
+// Mengikuti akun privat membuat permintaan mengikuti yang harus disetujui pemilik akun terlebih dahulu.
+func (fc *FollowController) Follow(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var target models.User
+	if err := fc.DB.First(&target, "username = ?", ctx.Param("username")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada user dengan username tersebut."})
+		return
+	}
+
+	if target.ID == currentUser.ID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Kamu tidak dapat mengikuti diri sendiri."})
+		return
+	}
+
+	if isBlocked(fc.DB, currentUser.ID, target.ID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu tidak dapat mengikuti pengguna ini."})
+		return
+	}
+
+	if target.IsPrivate && !isFollowing(fc.DB, currentUser.ID, target.ID) {
+		fc.requestFollow(ctx, currentUser, target)
+		return
+	}
+
+	err := fc.DB.Transaction(func(tx *gorm.DB) error {
+		// Permintaan yang masih tertunda tidak lagi diperlukan jika akun sudah tidak privat
+		if err := tx.Where("requester_id = ? AND target_id = ?", currentUser.ID, target.ID).Delete(&models.FollowRequest{}).Error; err != nil {
+			return err
+		}
+		follow := models.Follow{FollowerID: currentUser.ID, FollowingID: target.ID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return events.Record(tx, events.Event{
+			Type:          events.UserFollowed,
+			AggregateType: models.TargetUser,
+			AggregateID:   target.ID,
+			ActorID:       currentUser.ID,
+			Data:          map[string]interface{}{"follower_id": currentUser.ID, "following_id": target.ID},
+		})
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user_id": target.ID, "following": true, "requested": false}})
+}
+
+// requestFollow membuat permintaan mengikuti akun privat target. Pemilik akun mendapat notifikasi hanya untuk permintaan baru.
+func (fc *FollowController) requestFollow(ctx *gin.Context, currentUser models.User, target models.User) {
+	err := fc.DB.Transaction(func(tx *gorm.DB) error {
+		request := models.FollowRequest{RequesterID: currentUser.ID, TargetID: target.ID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&request)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return events.Record(tx, events.Event{
+			Type:          events.FollowRequested,
+			AggregateType: models.TargetUser,
+			AggregateID:   target.ID,
+			ActorID:       currentUser.ID,
+			Data:          map[string]interface{}{"requester_id": currentUser.ID, "target_id": target.ID},
+		})
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"status": "success", "data": gin.H{"user_id": target.ID, "following": false, "requested": true}})
+}
+
+// Unfollow digunakan untuk berhenti mengikuti pengguna berdasarkan username. Permintaan mengikuti yang masih tertunda ikut dibatalkan.
+func (fc *FollowController) Unfollow(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var target models.User
+	if err := fc.DB.First(&target, "username = ?", ctx.Param("username")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada user dengan username tersebut."})
+		return
+	}
+
+	err := fc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("follower_id = ? AND following_id = ?", currentUser.ID, target.ID).Delete(&models.Follow{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("requester_id = ? AND target_id = ?", currentUser.ID, target.ID).Delete(&models.FollowRequest{}).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user_id": target.ID, "following": false, "requested": false}})
+}
+
+// GetFollowRequests mengambil daftar permintaan mengikuti yang menunggu persetujuan pengguna saat ini dengan opsi paging
+func (fc *FollowController) GetFollowRequests(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	query := fc.DB.Model(&models.FollowRequest{}).Where("target_id = ?", currentUser.ID)
+	if hidden := blockedUserIDs(fc.DB, currentUser.ID); len(hidden) > 0 {
+		query = query.Where("requester_id NOT IN ?", hidden)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var requests []models.FollowRequest
+	result := query.Preload("Requester").Order("created_at DESC").Limit(limit).Offset(offset).Find(&requests)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, request := range requests {
+		responseData = append(responseData, gin.H{
+			"id":                request.Requester.ID,
+			"username":          request.Requester.Username,
+			"profile_image_url": request.Requester.ProfileImageURL,
+			"requested_at":      request.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// ApproveFollowRequest menyetujui permintaan mengikuti dari username sehingga pengguna tersebut menjadi pengikut
+func (fc *FollowController) ApproveFollowRequest(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var requester models.User
+	if err := fc.DB.First(&requester, "username = ?", ctx.Param("username")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada permintaan mengikuti dari username tersebut."})
+		return
+	}
+
+	err := fc.DB.Transaction(func(tx *gorm.DB) error {
+		// Menghapus permintaan sekaligus memastikan permintaan yang sama tidak disetujui dua kali
+		result := tx.Where("requester_id = ? AND target_id = ?", requester.ID, currentUser.ID).Delete(&models.FollowRequest{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		follow := models.Follow{FollowerID: requester.ID, FollowingID: currentUser.ID, CreatedAt: time.Now()}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error; err != nil {
+			return err
+		}
+		return events.Record(tx, events.Event{
+			Type:          events.FollowApproved,
+			AggregateType: models.TargetUser,
+			AggregateID:   requester.ID,
+			ActorID:       currentUser.ID,
+			Data:          map[string]interface{}{"follower_id": requester.ID, "following_id": currentUser.ID},
+		})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada permintaan mengikuti dari username tersebut."})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user_id": requester.ID, "follows_me": true}})
+}
+
+// DeclineFollowRequest menolak permintaan mengikuti dari username. Pengirim permintaan tidak diberi tahu.
+func (fc *FollowController) DeclineFollowRequest(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var requester models.User
+	if err := fc.DB.First(&requester, "username = ?", ctx.Param("username")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada permintaan mengikuti dari username tersebut."})
+		return
+	}
+
+	result := fc.DB.Where("requester_id = ? AND target_id = ?", requester.ID, currentUser.ID).Delete(&models.FollowRequest{})
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada permintaan mengikuti dari username tersebut."})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"user_id": requester.ID, "follows_me": false}})
+}
+
+// GetFollowers mengambil daftar pengikut seorang pengguna dengan opsi paging
+func (fc *FollowController) GetFollowers(ctx *gin.Context) {
+	fc.listFollows(ctx, "following_id", "Follower")
+}
+
+// GetFollowing mengambil daftar pengguna yang diikuti oleh seorang pengguna dengan opsi paging
+func (fc *FollowController) GetFollowing(ctx *gin.Context) {
+	fc.listFollows(ctx, "follower_id", "Following")
+}
+
+// listFollows menampilkan daftar relasi follow milik pengguna pada kolom column, dengan relasi preload sebagai isi daftar
+// Pengguna yang terhalang pemblokiran dengan pengguna saat ini tidak ditampilkan.
+func (fc *FollowController) listFollows(ctx *gin.Context, column string, preload string) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	var target models.User
+	if err := fc.DB.First(&target, "username = ?", ctx.Param("username")).Error; err != nil || isBlocked(fc.DB, currentUser.ID, target.ID) {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada user dengan username tersebut."})
+		return
+	}
+
+	// Kolom pengguna yang ditampilkan adalah kebalikan dari kolom pengguna target
+	listed := "follower_id"
+	if column == "follower_id" {
+		listed = "following_id"
+	}
+
+	query := fc.DB.Model(&models.Follow{}).Where(column+" = ?", target.ID)
+	if hidden := blockedUserIDs(fc.DB, currentUser.ID); len(hidden) > 0 {
+		query = query.Where(listed+" NOT IN ?", hidden)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var follows []models.Follow
+	result := query.Preload(preload).
+		Order("created_at DESC").Limit(limit).Offset(offset).Find(&follows)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, follow := range follows {
+		user := follow.Follower
+		if preload == "Following" {
+			user = follow.Following
+		}
+		responseData = append(responseData, gin.H{
+			"id":                user.ID,
+			"username":          user.Username,
+			"profile_image_url": user.ProfileImageURL,
+			"followed_at":       follow.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
@@ -0,0 +1,297 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/events"
+	"mygram-final-project/models"
+	"mygram-final-project/realtime"
+)
+
+// LikeController adalah kontroler untuk operasi like pada foto dan komentar
+type LikeController struct {
+	DB *gorm.DB
+}
+
+// NewLikeController digunakan untuk membuat instance baru dari LikeController
+func NewLikeController(DB *gorm.DB) LikeController {
+	return LikeController{DB}
+}
+
+// LikePhoto memberi like pada foto. Permintaan berulang tidak menambah jumlah like.
+func (lc *LikeController) LikePhoto(ctx *gin.Context) {
+	photoID := ctx.Param("photoId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var photo models.Photo
+	if err := lc.DB.First(&photo, "id = ?", photoID).Error; err != nil || isBlocked(lc.DB, currentUser.ID, photo.UserID) || !canViewPhoto(lc.DB, currentUser, photo) {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada photo dengan ID tersebut."})
+		return
+	}
+
+	err := lc.DB.Transaction(func(tx *gorm.DB) error {
+		like := models.PhotoLike{UserID: currentUser.ID, PhotoID: photo.ID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like)
+		if result.Error != nil {
+			return result.Error
+		}
+		// Jumlah like hanya bertambah jika baris like benar-benar baru dibuat
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.Photo{}).Where("id = ?", photo.ID).
+			UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error; err != nil {
+			return err
+		}
+		if err := publishLike(tx, realtime.EventPhotoLiked, photo.ID, currentUser, gin.H{"photo_id": photo.ID}); err != nil {
+			return err
+		}
+		return events.Record(tx, events.Event{
+			Type:          events.PhotoLiked,
+			AggregateType: models.TargetPhoto,
+			AggregateID:   photo.ID,
+			ActorID:       currentUser.ID,
+			Data:          map[string]interface{}{"photo_id": photo.ID, "photo_user_id": photo.UserID},
+		})
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	lc.DB.Model(&models.Photo{}).Select("like_count").Where("id = ?", photo.ID).Scan(&photo.LikeCount)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"photo_id": photo.ID, "liked_by_me": true, "like_count": photo.LikeCount}})
+}
+
+// UnlikePhoto menghapus like pada foto. Permintaan berulang tidak mengurangi jumlah like.
+func (lc *LikeController) UnlikePhoto(ctx *gin.Context) {
+	photoID := ctx.Param("photoId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var photo models.Photo
+	if err := lc.DB.First(&photo, "id = ?", photoID).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada photo dengan ID tersebut."})
+		return
+	}
+
+	err := lc.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND photo_id = ?", currentUser.ID, photo.ID).Delete(&models.PhotoLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.Photo{}).Where("id = ?", photo.ID).
+			UpdateColumn("like_count", gorm.Expr("GREATEST(like_count - 1, 0)")).Error; err != nil {
+			return err
+		}
+		return publishLike(tx, realtime.EventPhotoUnliked, photo.ID, currentUser, gin.H{"photo_id": photo.ID})
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	lc.DB.Model(&models.Photo{}).Select("like_count").Where("id = ?", photo.ID).Scan(&photo.LikeCount)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"photo_id": photo.ID, "liked_by_me": false, "like_count": photo.LikeCount}})
+}
+
+// GetPhotoLikers mengambil daftar pengguna yang memberi like pada foto dengan opsi paging
+func (lc *LikeController) GetPhotoLikers(ctx *gin.Context) {
+	photoID := ctx.Param("photoId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	var photo models.Photo
+	if err := lc.DB.First(&photo, "id = ?", photoID).Error; err != nil || isBlocked(lc.DB, currentUser.ID, photo.UserID) || !canViewPhoto(lc.DB, currentUser, photo) {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada photo dengan ID tersebut."})
+		return
+	}
+
+	query := lc.DB.Model(&models.PhotoLike{}).Where("photo_id = ?", photo.ID)
+	if hidden := blockedUserIDs(lc.DB, currentUser.ID); len(hidden) > 0 {
+		query = query.Where("user_id NOT IN ?", hidden)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var likes []models.PhotoLike
+	result := query.Preload("User").
+		Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&likes)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, like := range likes {
+		responseData = append(responseData, likerResponse(like.User, like.CreatedAt))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// LikeComment memberi like pada komentar. Permintaan berulang tidak menambah jumlah like.
+func (lc *LikeController) LikeComment(ctx *gin.Context) {
+	commentID := ctx.Param("commentId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var comment models.Comment
+	if err := lc.DB.Preload("Photo").First(&comment, "id = ?", commentID).Error; err != nil || comment.Tombstoned || isBlocked(lc.DB, currentUser.ID, comment.UserID) ||
+		!canViewComment(currentUser, comment, comment.Photo.UserID) || !canViewPhoto(lc.DB, currentUser, comment.Photo) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+		return
+	}
+
+	err := lc.DB.Transaction(func(tx *gorm.DB) error {
+		like := models.CommentLike{UserID: currentUser.ID, CommentID: comment.ID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.Comment{}).Where("id = ?", comment.ID).
+			UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error; err != nil {
+			return err
+		}
+		if err := publishLike(tx, realtime.EventCommentLiked, comment.PhotoID, currentUser, gin.H{"comment_id": comment.ID, "photo_id": comment.PhotoID}); err != nil {
+			return err
+		}
+		return events.Record(tx, events.Event{
+			Type:          events.CommentLiked,
+			AggregateType: models.TargetComment,
+			AggregateID:   comment.ID,
+			ActorID:       currentUser.ID,
+			Data:          map[string]interface{}{"comment_id": comment.ID, "comment_user_id": comment.UserID, "photo_id": comment.PhotoID},
+		})
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	lc.DB.Model(&models.Comment{}).Select("like_count").Where("id = ?", comment.ID).Scan(&comment.LikeCount)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"comment_id": comment.ID, "liked_by_me": true, "like_count": comment.LikeCount}})
+}
+
+// UnlikeComment menghapus like pada komentar. Permintaan berulang tidak mengurangi jumlah like.
+func (lc *LikeController) UnlikeComment(ctx *gin.Context) {
+	commentID := ctx.Param("commentId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var comment models.Comment
+	if err := lc.DB.First(&comment, "id = ?", commentID).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+		return
+	}
+
+	err := lc.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND comment_id = ?", currentUser.ID, comment.ID).Delete(&models.CommentLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Model(&models.Comment{}).Where("id = ?", comment.ID).
+			UpdateColumn("like_count", gorm.Expr("GREATEST(like_count - 1, 0)")).Error
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	lc.DB.Model(&models.Comment{}).Select("like_count").Where("id = ?", comment.ID).Scan(&comment.LikeCount)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"comment_id": comment.ID, "liked_by_me": false, "like_count": comment.LikeCount}})
+}
+
+// GetCommentLikers mengambil daftar pengguna yang memberi like pada komentar dengan opsi paging
+func (lc *LikeController) GetCommentLikers(ctx *gin.Context) {
+	commentID := ctx.Param("commentId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	var comment models.Comment
+	if err := lc.DB.First(&comment, "id = ?", commentID).Error; err != nil || isBlocked(lc.DB, currentUser.ID, comment.UserID) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada komentar dengan ID tersebut."})
+		return
+	}
+
+	query := lc.DB.Model(&models.CommentLike{}).Where("comment_id = ?", comment.ID)
+	if hidden := blockedUserIDs(lc.DB, currentUser.ID); len(hidden) > 0 {
+		query = query.Where("user_id NOT IN ?", hidden)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var likes []models.CommentLike
+	result := query.Preload("User").
+		Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&likes)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, like := range likes {
+		responseData = append(responseData, likerResponse(like.User, like.CreatedAt))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// publishLike mengirim event like ke pengguna yang sedang melihat foto photoID
+func publishLike(tx *gorm.DB, eventType string, photoID int64, actor models.User, data gin.H) error {
+	data["user"] = gin.H{"id": actor.ID, "username": actor.Username}
+	return realtime.Publish(tx, realtime.Event{Type: eventType, PhotoID: photoID, Data: data})
+}
+
+// likerResponse membuat objek JSON untuk satu pengguna pada daftar likers
+func likerResponse(user models.User, likedAt time.Time) gin.H {
+	return gin.H{
+		"id":                user.ID,
+		"username":          user.Username,
+		"profile_image_url": user.ProfileImageURL,
+		"liked_at":          likedAt,
+	}
+}
+
+// likedPhotoIDs mengembalikan himpunan ID foto (dari photoIDs) yang sudah di-like oleh userID
+func likedPhotoIDs(db *gorm.DB, userID int64, photoIDs []int64) map[int64]bool {
+	liked := map[int64]bool{}
+	if len(photoIDs) == 0 {
+		return liked
+	}
+
+	var ids []int64
+	db.Model(&models.PhotoLike{}).Where("user_id = ? AND photo_id IN ?", userID, photoIDs).Pluck("photo_id", &ids)
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked
+}
+
+// likedCommentIDs mengembalikan himpunan ID komentar (dari commentIDs) yang sudah di-like oleh userID
+func likedCommentIDs(db *gorm.DB, userID int64, commentIDs []int64) map[int64]bool {
+	liked := map[int64]bool{}
+	if len(commentIDs) == 0 {
+		return liked
+	}
+
+	var ids []int64
+	db.Model(&models.CommentLike{}).Where("user_id = ? AND comment_id IN ?", userID, commentIDs).Pluck("comment_id", &ids)
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked
+}
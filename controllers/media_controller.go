@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"mygram-final-project/media"
+	"mygram-final-project/storage"
+)
+
+// MediaController adalah kontroler untuk berkas media publik seperti avatar
+type MediaController struct {
+	Store storage.Store
+}
+
+// NewMediaController digunakan untuk membuat instance baru dari MediaController
+func NewMediaController(store storage.Store) MediaController {
+	return MediaController{store}
+}
+
+// GetMedia mengirim berkas media publik dari penyimpanan. Key berkas selalu baru untuk setiap unggahan,
+// sehingga berkas dapat di-cache tanpa batas waktu.
+func (mc *MediaController) GetMedia(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	if !media.IsPublicKey(key) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Berkas tidak ditemukan."})
+		return
+	}
+
+	file, err := mc.Store.Open(ctx.Request.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Berkas tidak ditemukan."})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+	defer file.Close()
+
+	ctx.DataFromReader(http.StatusOK, -1, "image/jpeg", file, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
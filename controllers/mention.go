@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"mygram-final-project/events"
+	"mygram-final-project/models"
+	"mygram-final-project/utils"
+)
+
+// syncMentions menyimpan ulang mention dari sebuah teks dan mengirim notifikasi ke pengguna yang baru disebut.
+// Fungsi ini dipanggil di dalam transaksi yang sama dengan penyimpanan foto atau komentar.
+func syncMentions(tx *gorm.DB, sourceType string, sourceID int64, author models.User, text string) error {
+	// Simpan daftar pengguna yang sudah disebut sebelumnya agar notifikasi tidak terkirim dua kali saat edit
+	var previous []int64
+	if err := tx.Model(&models.Mention{}).
+		Where("source_type = ? AND source_id = ?", sourceType, sourceID).
+		Distinct().Pluck("mentioned_user_id", &previous).Error; err != nil {
+		return err
+	}
+	if err := deleteMentions(tx, sourceType, sourceID); err != nil {
+		return err
+	}
+
+	tokens := utils.ExtractMentions(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	usernames := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		usernames = append(usernames, strings.ToLower(token.Username))
+	}
+
+	var users []models.User
+	if err := tx.Where("LOWER(username) IN ?", usernames).Find(&users).Error; err != nil {
+		return err
+	}
+
+	usersByName := map[string]models.User{}
+	for _, user := range users {
+		if !canMention(tx, author, user) {
+			continue
+		}
+		usersByName[strings.ToLower(user.Username)] = user
+	}
+
+	alreadyMentioned := map[int64]bool{}
+	for _, id := range previous {
+		alreadyMentioned[id] = true
+	}
+
+	now := time.Now()
+	notified := map[int64]bool{}
+	for _, token := range tokens {
+		user, ok := usersByName[strings.ToLower(token.Username)]
+		if !ok {
+			continue
+		}
+
+		mention := models.Mention{
+			SourceType:      sourceType,
+			SourceID:        sourceID,
+			AuthorID:        author.ID,
+			MentionedUserID: user.ID,
+			Offset:          token.Offset,
+			Length:          token.Length,
+			CreatedAt:       now,
+		}
+		if err := tx.Create(&mention).Error; err != nil {
+			return err
+		}
+
+		// Pengguna tidak diberi notifikasi saat menyebut dirinya sendiri
+		if user.ID == author.ID || alreadyMentioned[user.ID] || notified[user.ID] {
+			continue
+		}
+		notified[user.ID] = true
+
+		err := events.Record(tx, events.Event{
+			Type:          events.UserMentioned,
+			AggregateType: models.TargetUser,
+			AggregateID:   user.ID,
+			ActorID:       author.ID,
+			Data:          map[string]interface{}{"source_type": sourceType, "source_id": sourceID},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteMentions menghapus semua mention milik sebuah foto atau komentar
+func deleteMentions(tx *gorm.DB, sourceType string, sourceID int64) error {
+	return tx.Where("source_type = ? AND source_id = ?", sourceType, sourceID).Delete(&models.Mention{}).Error
+}
+
+// canMention menentukan apakah author boleh menyebut target sesuai pengaturan privasi target dan pemblokiran
+func canMention(tx *gorm.DB, author models.User, target models.User) bool {
+	if author.ID == target.ID {
+		return true
+	}
+	return target.AllowMentions && !isBlocked(tx, author.ID, target.ID)
+}
+
+// mentionEntities mengambil mention untuk beberapa sumber sekaligus dan mengubahnya menjadi entitas respons
+func mentionEntities(db *gorm.DB, sourceType string, sourceIDs []int64) map[int64][]gin.H {
+	entities := map[int64][]gin.H{}
+	if len(sourceIDs) == 0 {
+		return entities
+	}
+
+	var mentions []models.Mention
+	db.Preload("MentionedUser").
+		Where("source_type = ? AND source_id IN ?", sourceType, sourceIDs).
+		Order("source_id, mention_offset").Find(&mentions)
+
+	for _, mention := range mentions {
+		entities[mention.SourceID] = append(entities[mention.SourceID], gin.H{
+			"user_id":  mention.MentionedUserID,
+			"username": mention.MentionedUser.Username,
+			"offset":   mention.Offset,
+			"length":   mention.Length,
+		})
+	}
+	return entities
+}
+
+// mentionsOrEmpty memastikan field mentions pada respons selalu berupa array
+func mentionsOrEmpty(entities []gin.H) []gin.H {
+	if entities == nil {
+		return []gin.H{}
+	}
+	return entities
+}
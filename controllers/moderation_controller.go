@@ -0,0 +1,561 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/audit"
+	"mygram-final-project/models"
+)
+
+// errReportNotPending dikembalikan saat laporan yang akan ditangani sudah selesai atau diklaim moderator lain
+var errReportNotPending = errors.New("laporan sudah ditangani")
+
+// ModerationController adalah kontroler untuk laporan konten dan antrean moderasi
+type ModerationController struct {
+	DB *gorm.DB
+}
+
+// NewModerationController digunakan untuk membuat instance baru dari ModerationController
+func NewModerationController(DB *gorm.DB) ModerationController {
+	return ModerationController{DB}
+}
+
+// CreateReport digunakan untuk melaporkan foto, komentar, atau pengguna. Laporan berulang atas objek yang sama tidak membuat data ganda.
+func (mc *ModerationController) CreateReport(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.CreateReportRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	validReason := false
+	for _, reason := range models.ReportReasons {
+		validReason = validReason || reason == payload.Reason
+	}
+	if !validReason {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Reason harus salah satu dari %s.", strings.Join(models.ReportReasons, ", "))})
+		return
+	}
+
+	targetUserID, err := findTargetOwner(mc.DB, payload.TargetType, payload.TargetID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+	if targetUserID == currentUser.ID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Kamu tidak dapat melaporkan konten milikmu sendiri."})
+		return
+	}
+
+	now := time.Now()
+	report := models.Report{
+		ReporterID:   currentUser.ID,
+		TargetType:   payload.TargetType,
+		TargetID:     payload.TargetID,
+		TargetUserID: targetUserID,
+		Reason:       payload.Reason,
+		Details:      payload.Details,
+		Status:       models.ReportOpen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	result := mc.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "Kamu sudah pernah melaporkan konten ini."})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": gin.H{"id": report.ID, "status": report.Status}})
+}
+
+// GetReports mengambil antrean laporan untuk moderator dengan opsi paging, dari yang paling lama.
+// Query status (default open), target_type, dan reason dapat dipakai untuk memfilter.
+func (mc *ModerationController) GetReports(ctx *gin.Context) {
+	page, limit, offset := parsePagination(ctx)
+
+	query := mc.DB.Model(&models.Report{}).Where("status = ?", ctx.DefaultQuery("status", models.ReportOpen))
+	if targetType := ctx.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	if reason := ctx.Query("reason"); reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var reports []models.Report
+	if err := query.Preload("Reporter").Order("created_at ASC, id ASC").Limit(limit).Offset(offset).Find(&reports).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, report := range reports {
+		responseData = append(responseData, mc.reportResponse(report))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// GetReportByID mengambil detail laporan beserta konten yang dilaporkan dan riwayat tindakan moderasi atas konten tersebut
+func (mc *ModerationController) GetReportByID(ctx *gin.Context) {
+	var report models.Report
+	if err := mc.DB.Preload("Reporter").First(&report, "id = ?", ctx.Param("reportId")).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada laporan dengan ID tersebut."})
+		return
+	}
+
+	var actions []models.ModerationAction
+	mc.DB.Where("target_type = ? AND target_id = ?", report.TargetType, report.TargetID).Order("id ASC").Find(&actions)
+
+	history := []gin.H{}
+	for _, action := range actions {
+		history = append(history, moderationActionResponse(action))
+	}
+
+	responseData := mc.reportResponse(report)
+	responseData["target"] = mc.targetSnapshot(report.TargetType, report.TargetID)
+	responseData["actions"] = history
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+}
+
+// ClaimReport digunakan moderator untuk mulai menangani laporan agar tidak dikerjakan moderator lain
+func (mc *ModerationController) ClaimReport(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	now := time.Now()
+	result := mc.DB.Model(&models.Report{}).
+		Where("id = ? AND status = ?", ctx.Param("reportId"), models.ReportOpen).
+		UpdateColumns(map[string]interface{}{
+			"status":        models.ReportClaimed,
+			"claimed_by_id": currentUser.ID,
+			"claimed_at":    now,
+			"updated_at":    now,
+		})
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		ctx.JSON(http.StatusConflict, gin.H{"message": "Laporan tidak ditemukan atau sudah ditangani."})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"id": ctx.Param("reportId"), "status": models.ReportClaimed}})
+}
+
+// ResolveReport digunakan moderator untuk menyelesaikan laporan dengan tindakan hide_content, remove_content, atau suspend_user.
+// Laporan lain yang masih terbuka atas objek yang sama ikut diselesaikan.
+func (mc *ModerationController) ResolveReport(ctx *gin.Context) {
+	mc.decideReport(ctx, models.ReportResolved)
+}
+
+// DismissReport digunakan moderator untuk menolak laporan tanpa tindakan
+func (mc *ModerationController) DismissReport(ctx *gin.Context) {
+	mc.decideReport(ctx, models.ReportDismissed)
+}
+
+// CreateAction digunakan moderator untuk mengambil tindakan langsung tanpa laporan, misalnya unhide_content atau unsuspend_user
+func (mc *ModerationController) CreateAction(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.ModerationActionRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if payload.Action == models.ModerationDismiss {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Tindakan dismiss hanya berlaku untuk laporan."})
+		return
+	}
+
+	targetUserID, err := findTargetOwner(mc.DB, payload.TargetType, payload.TargetID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	var action models.ModerationAction
+	err = mc.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if action, err = applyModerationAction(tx, currentUser, nil, payload, targetUserID); err != nil {
+			return err
+		}
+		return recordModerationAudit(ctx, tx, action)
+	})
+	if err != nil {
+		respondActionError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": moderationActionResponse(action)})
+}
+
+// GetPendingComments mengambil komentar yang ditahan filter konten dengan opsi paging, dari yang terlama.
+// Komentar disetujui atau ditolak melalui tindakan approve_comment dan reject_comment.
+func (mc *ModerationController) GetPendingComments(ctx *gin.Context) {
+	page, limit, offset := parsePagination(ctx)
+
+	query := mc.DB.Model(&models.Comment{}).Where("status = ?", models.CommentPending)
+
+	var total int64
+	query.Count(&total)
+
+	var comments []models.Comment
+	if err := query.Preload("User").Order("id ASC").Limit(limit).Offset(offset).Find(&comments).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, comment := range comments {
+		responseData = append(responseData, gin.H{
+			"id":          comment.ID,
+			"message":     comment.Message,
+			"photo_id":    comment.PhotoID,
+			"parent_id":   comment.ParentID,
+			"filter_note": comment.FilterNote,
+			"created_at":  comment.CreatedAt,
+			"updated_at":  comment.UpdatedAt,
+			"user": gin.H{
+				"id":       comment.User.ID,
+				"username": comment.User.Username,
+			},
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// GetActions mengambil catatan tindakan moderasi dengan opsi paging, dari yang terbaru.
+// Query target_type dan target_id dapat dipakai untuk melihat riwayat satu objek.
+func (mc *ModerationController) GetActions(ctx *gin.Context) {
+	page, limit, offset := parsePagination(ctx)
+
+	query := mc.DB.Model(&models.ModerationAction{})
+	if targetType := ctx.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ? AND target_id = ?", targetType, ctx.Query("target_id"))
+	}
+	if userID := ctx.Query("target_user_id"); userID != "" {
+		query = query.Where("target_user_id = ?", userID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var actions []models.ModerationAction
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&actions).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, action := range actions {
+		responseData = append(responseData, moderationActionResponse(action))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// decideReport menyelesaikan laporan dengan status, menjalankan tindakan moderasi, lalu mencatatnya
+func (mc *ModerationController) decideReport(ctx *gin.Context, status string) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.ModerationActionRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	if status == models.ReportDismissed {
+		payload.Action = models.ModerationDismiss
+	} else if payload.Action == models.ModerationDismiss || payload.Action == models.ModerationUnhideContent ||
+		payload.Action == models.ModerationUnsuspendUser || payload.Action == models.ModerationApproveComment {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Action harus salah satu dari hide_content, remove_content, reject_comment, atau suspend_user."})
+		return
+	}
+
+	var action models.ModerationAction
+	err := mc.DB.Transaction(func(tx *gorm.DB) error {
+		var report models.Report
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&report, "id = ?", ctx.Param("reportId")).Error; err != nil {
+			return err
+		}
+		// Laporan yang diklaim moderator lain hanya dapat diambil alih oleh admin
+		pending := report.Status == models.ReportOpen || report.Status == models.ReportClaimed
+		claimedByOther := report.ClaimedByID != nil && *report.ClaimedByID != currentUser.ID && currentUser.Role != models.RoleAdmin
+		if !pending || (report.Status == models.ReportClaimed && claimedByOther) {
+			return errReportNotPending
+		}
+
+		payload.TargetType = report.TargetType
+		payload.TargetID = report.TargetID
+		var err error
+		if action, err = applyModerationAction(tx, currentUser, &report.ID, payload, report.TargetUserID); err != nil {
+			return err
+		}
+		if err := recordModerationAudit(ctx, tx, action); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&models.Report{}).
+			Where("(id = ?) OR (target_type = ? AND target_id = ? AND status IN ?)",
+				report.ID, report.TargetType, report.TargetID, []string{models.ReportOpen, models.ReportClaimed}).
+			UpdateColumns(map[string]interface{}{"status": status, "resolved_at": now, "updated_at": now}).Error
+	})
+	if err != nil {
+		respondActionError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": moderationActionResponse(action)})
+}
+
+// respondActionError mengubah error dari tindakan moderasi menjadi respons HTTP
+func respondActionError(ctx *gin.Context, err error) {
+	var validationErr moderationValidationError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Laporan atau konten tidak ditemukan."})
+	case errors.Is(err, errReportNotPending):
+		ctx.JSON(http.StatusConflict, gin.H{"message": "Laporan sudah selesai atau sedang ditangani moderator lain."})
+	case errors.As(err, &validationErr):
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": validationErr.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+	}
+}
+
+// findTargetOwner memastikan objek laporan ada dan mengembalikan ID pemiliknya (atau ID pengguna itu sendiri)
+func findTargetOwner(db *gorm.DB, targetType string, targetID int64) (int64, error) {
+	switch targetType {
+	case models.TargetPhoto:
+		var photo models.Photo
+		if err := db.First(&photo, "id = ?", targetID).Error; err != nil {
+			return 0, errors.New("Tidak ada photo dengan ID tersebut.")
+		}
+		return photo.UserID, nil
+	case models.TargetComment:
+		var comment models.Comment
+		if err := db.First(&comment, "id = ? AND tombstoned = ?", targetID, false).Error; err != nil {
+			return 0, errors.New("Tidak ada komentar dengan ID tersebut.")
+		}
+		return comment.UserID, nil
+	case models.TargetUser:
+		var user models.User
+		if err := db.First(&user, "id = ?", targetID).Error; err != nil {
+			return 0, errors.New("Tidak ada user dengan ID tersebut.")
+		}
+		return user.ID, nil
+	default:
+		return 0, errors.New("Target type harus salah satu dari photo, comment, atau user.")
+	}
+}
+
+// targetSnapshot mengambil isi objek yang dilaporkan untuk ditinjau moderator, atau nil jika objek sudah dihapus
+func (mc *ModerationController) targetSnapshot(targetType string, targetID int64) interface{} {
+	switch targetType {
+	case models.TargetPhoto:
+		var photo models.Photo
+		if mc.DB.First(&photo, "id = ?", targetID).Error == nil {
+			return gin.H{"id": photo.ID, "title": photo.Title, "caption": photo.Caption, "photo_url": photo.PhotoURL, "user_id": photo.UserID, "hidden_at": photo.HiddenAt}
+		}
+	case models.TargetComment:
+		var comment models.Comment
+		if mc.DB.First(&comment, "id = ?", targetID).Error == nil {
+			return gin.H{"id": comment.ID, "message": comment.Message, "photo_id": comment.PhotoID, "user_id": comment.UserID, "hidden_at": comment.HiddenAt, "status": comment.Status}
+		}
+	case models.TargetUser:
+		var user models.User
+		if mc.DB.First(&user, "id = ?", targetID).Error == nil {
+			return gin.H{"id": user.ID, "username": user.Username, "profile_image_url": user.ProfileImageURL, "suspended_at": user.SuspendedAt, "suspended_until": user.SuspendedUntil}
+		}
+	}
+	return nil
+}
+
+// reportResponse membuat data respons laporan beserta jumlah laporan atas objek yang sama
+func (mc *ModerationController) reportResponse(report models.Report) gin.H {
+	var reportCount int64
+	mc.DB.Model(&models.Report{}).Where("target_type = ? AND target_id = ?", report.TargetType, report.TargetID).Count(&reportCount)
+
+	return gin.H{
+		"id":             report.ID,
+		"target_type":    report.TargetType,
+		"target_id":      report.TargetID,
+		"target_user_id": report.TargetUserID,
+		"reason":         report.Reason,
+		"details":        report.Details,
+		"status":         report.Status,
+		"report_count":   reportCount,
+		"claimed_by_id":  report.ClaimedByID,
+		"claimed_at":     report.ClaimedAt,
+		"resolved_at":    report.ResolvedAt,
+		"created_at":     report.CreatedAt,
+		"reporter": gin.H{
+			"id":       report.Reporter.ID,
+			"username": report.Reporter.Username,
+		},
+	}
+}
+
+// moderationValidationError adalah error karena permintaan tindakan moderasi tidak valid
+type moderationValidationError struct {
+	message string
+}
+
+func (e moderationValidationError) Error() string {
+	return e.message
+}
+
+// applyModerationAction menjalankan tindakan moderasi pada objek di dalam transaksi lalu mencatatnya secara permanen
+func applyModerationAction(tx *gorm.DB, moderator models.User, reportID *int64, payload models.ModerationActionRequest, targetUserID int64) (models.ModerationAction, error) {
+	now := time.Now()
+	action := models.ModerationAction{
+		ReportID:     reportID,
+		ModeratorID:  moderator.ID,
+		Action:       payload.Action,
+		TargetType:   payload.TargetType,
+		TargetID:     payload.TargetID,
+		TargetUserID: targetUserID,
+		Note:         payload.Note,
+		CreatedAt:    now,
+	}
+
+	isContent := payload.TargetType == models.TargetPhoto || payload.TargetType == models.TargetComment
+	var err error
+	switch payload.Action {
+	case models.ModerationHideContent, models.ModerationUnhideContent:
+		if !isContent {
+			return action, moderationValidationError{"Hanya photo atau komentar yang dapat disembunyikan."}
+		}
+		var hiddenAt interface{}
+		if payload.Action == models.ModerationHideContent {
+			hiddenAt = now
+		}
+		table := "photos"
+		if payload.TargetType == models.TargetComment {
+			table = "comments"
+		}
+		err = tx.Table(table).Where("id = ?", payload.TargetID).UpdateColumn("hidden_at", hiddenAt).Error
+	case models.ModerationRemoveContent:
+		if !isContent {
+			return action, moderationValidationError{"Hanya photo atau komentar yang dapat dihapus."}
+		}
+		if payload.TargetType == models.TargetPhoto {
+			var photo models.Photo
+			if err = tx.First(&photo, "id = ?", payload.TargetID).Error; err == nil {
+				err = removePhoto(tx, photo, moderator.ID)
+			}
+		} else {
+			var comment models.Comment
+			if err = tx.First(&comment, "id = ?", payload.TargetID).Error; err == nil {
+				err = removeComment(tx, comment, moderator.ID)
+			}
+		}
+	case models.ModerationApproveComment, models.ModerationRejectComment:
+		if payload.TargetType != models.TargetComment {
+			return action, moderationValidationError{"Hanya komentar yang dapat disetujui atau ditolak."}
+		}
+		var comment models.Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("User").First(&comment, "id = ?", payload.TargetID).Error; err != nil {
+			return action, err
+		}
+		if comment.Status != models.CommentPending {
+			return action, moderationValidationError{"Komentar tidak sedang menunggu peninjauan."}
+		}
+		if payload.Action == models.ModerationRejectComment {
+			err = removeComment(tx, comment, moderator.ID)
+			break
+		}
+		comment.Status = models.CommentPublished
+		comment.FilterNote = ""
+		if err = tx.Model(&comment).UpdateColumns(map[string]interface{}{"status": comment.Status, "filter_note": ""}).Error; err == nil {
+			err = publishStoredComment(tx, comment, comment.User)
+		}
+	case models.ModerationSuspendUser:
+		if targetUserID == moderator.ID {
+			return action, moderationValidationError{"Kamu tidak dapat menangguhkan akunmu sendiri."}
+		}
+		var target models.User
+		if err := tx.First(&target, "id = ?", targetUserID).Error; err != nil {
+			return action, err
+		}
+		if target.Role == models.RoleAdmin {
+			return action, moderationValidationError{"Akun admin tidak dapat ditangguhkan."}
+		}
+		if payload.SuspendDays < 0 {
+			return action, moderationValidationError{"Suspend days tidak boleh negatif."}
+		}
+		if payload.SuspendDays > 0 {
+			until := now.AddDate(0, 0, payload.SuspendDays)
+			action.SuspendedUntil = &until
+		}
+		err = tx.Model(&models.User{}).Where("id = ?", targetUserID).
+			UpdateColumns(map[string]interface{}{"suspended_at": now, "suspended_until": action.SuspendedUntil}).Error
+	case models.ModerationUnsuspendUser:
+		err = tx.Model(&models.User{}).Where("id = ?", targetUserID).
+			UpdateColumns(map[string]interface{}{"suspended_at": nil, "suspended_until": nil}).Error
+	case models.ModerationDismiss:
+	default:
+		return action, moderationValidationError{"Action tidak dikenal."}
+	}
+	if err != nil {
+		return action, err
+	}
+
+	return action, tx.Create(&action).Error
+}
+
+// recordModerationAudit mencatat tindakan moderasi di audit log bersama IP dan ID permintaan moderator
+func recordModerationAudit(ctx *gin.Context, tx *gorm.DB, action models.ModerationAction) error {
+	entry := auditEntry(ctx, &action.ModeratorID, models.AuditModerationPrefix+action.Action, action.TargetType, action.TargetID)
+	entry.Metadata = map[string]interface{}{
+		"moderation_action_id": action.ID,
+		"target_user_id":       action.TargetUserID,
+	}
+	if action.ReportID != nil {
+		entry.Metadata["report_id"] = *action.ReportID
+	}
+	if action.Note != "" {
+		entry.Metadata["note"] = action.Note
+	}
+	if action.SuspendedUntil != nil {
+		entry.Metadata["suspended_until"] = action.SuspendedUntil
+	}
+	return audit.Record(tx, entry)
+}
+
+// moderationActionResponse membuat data respons catatan tindakan moderasi
+func moderationActionResponse(action models.ModerationAction) gin.H {
+	return gin.H{
+		"id":              action.ID,
+		"report_id":       action.ReportID,
+		"moderator_id":    action.ModeratorID,
+		"action":          action.Action,
+		"target_type":     action.TargetType,
+		"target_id":       action.TargetID,
+		"target_user_id":  action.TargetUserID,
+		"note":            action.Note,
+		"suspended_until": action.SuspendedUntil,
+		"created_at":      action.CreatedAt,
+	}
+}
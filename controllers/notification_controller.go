@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/models"
+	"mygram-final-project/notifications"
+)
+
+// NotificationController adalah kontroler untuk pusat notifikasi pengguna
+type NotificationController struct {
+	DB *gorm.DB
+}
+
+// NewNotificationController digunakan untuk membuat instance baru dari NotificationController
+func NewNotificationController(DB *gorm.DB) NotificationController {
+	return NotificationController{DB}
+}
+
+// GetNotifications mengambil daftar notifikasi pengguna saat ini dengan opsi paging.
+// Query unread=true hanya mengembalikan notifikasi yang belum dibaca.
+func (nc *NotificationController) GetNotifications(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	query := nc.DB.Model(&models.Notification{}).Where("user_id = ?", currentUser.ID)
+	if ctx.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var items []models.Notification
+	result := query.Preload("Actor").Order("updated_at DESC, id DESC").Limit(limit).Offset(offset).Find(&items)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, notification := range items {
+		responseData = append(responseData, gin.H{
+			"id":          notification.ID,
+			"type":        notification.Type,
+			"target_type": notification.TargetType,
+			"target_id":   notification.TargetID,
+			"actor_count": notification.ActorCount,
+			"text":        notifications.Text(notification),
+			"read":        notification.ReadAt != nil,
+			"created_at":  notification.CreatedAt,
+			"updated_at":  notification.UpdatedAt,
+			"actor": gin.H{
+				"id":                notification.Actor.ID,
+				"username":          notification.Actor.Username,
+				"profile_image_url": notification.Actor.ProfileImageURL,
+			},
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// GetUnreadCount mengambil jumlah notifikasi yang belum dibaca oleh pengguna saat ini
+func (nc *NotificationController) GetUnreadCount(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var count int64
+	if err := nc.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", currentUser.ID).Count(&count).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"unread_count": count}})
+}
+
+// MarkAsRead menandai satu notifikasi milik pengguna saat ini sebagai sudah dibaca
+func (nc *NotificationController) MarkAsRead(ctx *gin.Context) {
+	notificationID := ctx.Param("notificationId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var notification models.Notification
+	if err := nc.DB.First(&notification, "id = ? AND user_id = ?", notificationID, currentUser.ID).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada notifikasi dengan ID tersebut."})
+		return
+	}
+
+	if notification.ReadAt == nil {
+		if err := nc.DB.Model(&notification).UpdateColumn("read_at", time.Now()).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// MarkAllAsRead menandai semua notifikasi pengguna saat ini sebagai sudah dibaca
+func (nc *NotificationController) MarkAllAsRead(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	result := nc.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", currentUser.ID).
+		UpdateColumn("read_at", time.Now())
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"updated": result.RowsAffected}})
+}
+
+// GetPreferences mengambil preferensi notifikasi pengguna saat ini untuk setiap jenis notifikasi
+func (nc *NotificationController) GetPreferences(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var preferences []models.NotificationPreference
+	if err := nc.DB.Where("user_id = ?", currentUser.ID).Find(&preferences).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := gin.H{}
+	for _, notificationType := range models.NotificationTypes {
+		responseData[notificationType] = true
+	}
+	for _, preference := range preferences {
+		responseData[preference.Type] = preference.Enabled
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+}
+
+// UpdatePreferences memperbarui preferensi notifikasi pengguna saat ini
+func (nc *NotificationController) UpdatePreferences(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.UpdateNotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	// Validasi jenis notifikasi
+	validTypes := map[string]bool{}
+	for _, notificationType := range models.NotificationTypes {
+		validTypes[notificationType] = true
+	}
+	for notificationType := range payload.Preferences {
+		if !validTypes[notificationType] {
+			ctx.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("Jenis notifikasi %q tidak dikenal.", notificationType)})
+			return
+		}
+	}
+
+	err := nc.DB.Transaction(func(tx *gorm.DB) error {
+		for notificationType, enabled := range payload.Preferences {
+			preference := models.NotificationPreference{UserID: currentUser.ID, Type: notificationType, Enabled: enabled}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+				DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+			}).Create(&preference).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	nc.GetPreferences(ctx)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 10  // Jumlah data default per halaman
+	maxPageLimit     = 100 // Jumlah data maksimal per halaman
+)
+
+// parsePagination membaca query page dan limit lalu mengembalikan nilai yang sudah dinormalisasi beserta offset-nya
+func parsePagination(ctx *gin.Context) (page int, limit int, offset int) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err = strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultPageLimit)))
+	if err != nil || limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	return page, limit, (page - 1) * limit
+}
+
+// paginationMeta membuat objek metadata paging untuk respons
+func paginationMeta(page int, limit int, total int64) gin.H {
+	return gin.H{
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	}
+}
+
+// commentCursor adalah posisi terakhir pada pagination berbasis cursor untuk komentar
+type commentCursor struct {
+	Key int64 // Nilai kolom urutan (like_count untuk urutan top, selain itu tidak dipakai)
+	ID  int64 // ID komentar terakhir pada halaman sebelumnya
+}
+
+// encodeCursor mengubah cursor menjadi string yang aman dipakai pada query URL
+func encodeCursor(cursor commentCursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.Key, cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor membaca kembali cursor yang dihasilkan oleh encodeCursor
+func decodeCursor(value string) (commentCursor, error) {
+	var cursor commentCursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, fmt.Errorf("cursor tidak valid")
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return cursor, fmt.Errorf("cursor tidak valid")
+	}
+
+	if cursor.Key, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return cursor, fmt.Errorf("cursor tidak valid")
+	}
+	if cursor.ID, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return cursor, fmt.Errorf("cursor tidak valid")
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	tests := []struct {
+		name    string
+		value   string
+		want    commentCursor
+		wantErr bool
+	}{
+		{"hasil encodeCursor", encodeCursor(commentCursor{Key: 12, ID: 345}), commentCursor{Key: 12, ID: 345}, false},
+		{"key nol", encodeCursor(commentCursor{ID: 9}), commentCursor{ID: 9}, false},
+		{"bilangan negatif", encode("-1:-2"), commentCursor{Key: -1, ID: -2}, false},
+		{"kosong", "", commentCursor{}, true},
+		{"bukan base64", "!!!", commentCursor{}, true},
+		{"base64 dengan padding", base64.URLEncoding.EncodeToString([]byte("12:3")), commentCursor{}, true},
+		{"tanpa pemisah", encode("12"), commentCursor{}, true},
+		{"key bukan angka", encode("a:2"), commentCursor{}, true},
+		{"ID bukan angka", encode("1:b"), commentCursor{}, true},
+		{"terlalu banyak bagian", encode("1:2:3"), commentCursor{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeCursor(%q) error = %v, ingin error %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("decodeCursor(%q) = %+v, ingin %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
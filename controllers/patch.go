@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"mygram-final-project/mergepatch"
+)
+
+// bindMergePatch membaca body permintaan sebagai dokumen JSON Merge Patch. Jika body tidak dapat dibaca, respons error
+// langsung dikirim dan fungsi mengembalikan false.
+func bindMergePatch(ctx *gin.Context) (*mergepatch.Patch, bool) {
+	if !mergepatch.IsSupportedContentType(ctx.GetHeader("Content-Type")) {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"status": "fail", "message": "Content-Type harus " + mergepatch.ContentType + "."})
+		return nil, false
+	}
+
+	patch, err := mergepatch.Parse(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Body harus berupa objek JSON."})
+		return nil, false
+	}
+	return patch, true
+}
+
+// respondPatchErrors mengirim respons 400 berisi kesalahan per field jika patch tidak valid, lalu mengembalikan true
+func respondPatchErrors(ctx *gin.Context, patch *mergepatch.Patch) bool {
+	errors := patch.Errors()
+	if errors == nil {
+		return false
+	}
+	ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Patch tidak valid.", "errors": errors})
+	return true
+}
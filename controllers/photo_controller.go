@@ -1,14 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"mygram-final-project/audit"
+	"mygram-final-project/events"
 	"mygram-final-project/models"
 	"mygram-final-project/utils"
 )
@@ -52,19 +54,44 @@ func (pc *PhotoController) CreatePhoto(ctx *gin.Context) {
 		return
 	}
 
+	// Validasi pengaturan komentar
+	if payload.CommentPolicy == "" {
+		payload.CommentPolicy = models.CommentPolicyEveryone
+	}
+	if !isValidCommentPolicy(payload.CommentPolicy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Comment policy harus salah satu dari everyone, followers, atau nobody."})
+		return
+	}
+
 	now := time.Now()
 	newPhoto := models.Photo{
-		Title:     payload.Title,
-		Caption:   payload.Caption,
-		PhotoURL:  payload.PhotoURL,
-		UserID:    currentUser.ID,
-		CreatedAt: now,
-		UpdatedAt: now,
+		Title:         payload.Title,
+		Caption:       payload.Caption,
+		PhotoURL:      payload.PhotoURL,
+		UserID:        currentUser.ID,
+		CommentPolicy: payload.CommentPolicy,
+		AgeRestricted: payload.AgeRestricted,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 
-	result := pc.DB.Create(&newPhoto)
-	if result.Error != nil {
-		if strings.Contains(result.Error.Error(), "duplicate key") {
+	err := pc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newPhoto).Error; err != nil {
+			return err
+		}
+		if err := syncMentions(tx, models.MentionSourcePhoto, newPhoto.ID, currentUser, newPhoto.Caption); err != nil {
+			return err
+		}
+		return events.Record(tx, events.Event{
+			Type:          events.PhotoCreated,
+			AggregateType: models.TargetPhoto,
+			AggregateID:   newPhoto.ID,
+			ActorID:       currentUser.ID,
+			Data:          photoEventData(newPhoto),
+		})
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
 			ctx.JSON(http.StatusConflict, gin.H{"message": "Photo dengan title tersebut sudah ada."})
 			return
 		}
@@ -72,12 +99,16 @@ func (pc *PhotoController) CreatePhoto(ctx *gin.Context) {
 		return
 	}
 
+	mentions := mentionEntities(pc.DB, models.MentionSourcePhoto, []int64{newPhoto.ID})
 	ctx.JSON(http.StatusCreated, gin.H{
-		"id":        newPhoto.ID,
-		"caption":   newPhoto.Caption,
-		"title":     newPhoto.Title,
-		"photo_url": newPhoto.PhotoURL,
-		"user_id":   newPhoto.UserID,
+		"id":             newPhoto.ID,
+		"caption":        newPhoto.Caption,
+		"title":          newPhoto.Title,
+		"photo_url":      newPhoto.PhotoURL,
+		"user_id":        newPhoto.UserID,
+		"comment_policy": newPhoto.CommentPolicy,
+		"age_restricted": newPhoto.AgeRestricted,
+		"mentions":       mentionsOrEmpty(mentions[newPhoto.ID]),
 	})
 }
 
@@ -110,46 +141,140 @@ func (pc *PhotoController) UpdatePhoto(ctx *gin.Context) {
 		return
 	}
 
-	var updatedPhoto models.Photo
-	result := pc.DB.First(&updatedPhoto, "id = ?", photoID)
-	if result.Error != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada photo dengan ID tersebut."})
+	// Validasi pengaturan komentar, kosong berarti tidak diubah
+	if payload.CommentPolicy != "" && !isValidCommentPolicy(payload.CommentPolicy) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Comment policy harus salah satu dari everyone, followers, atau nobody."})
 		return
 	}
 
-	// Periksa apakah pengguna yang sedang masuk adalah pemilik foto yang akan diperbarui
-	if updatedPhoto.UserID != currentUser.ID {
-		ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu tidak diizinkan untuk mengedit photo ini."})
+	updatedPhoto, ok := pc.findOwnPhoto(ctx, photoID, currentUser)
+	if !ok {
 		return
 	}
 
-	now := time.Now()
 	updatedPhoto.Title = payload.Title
 	updatedPhoto.Caption = payload.Caption
 	updatedPhoto.PhotoURL = payload.PhotoURL
-	updatedPhoto.UpdatedAt = now
+	if payload.CommentPolicy != "" {
+		updatedPhoto.CommentPolicy = payload.CommentPolicy
+	}
+	if payload.AgeRestricted != nil {
+		updatedPhoto.AgeRestricted = *payload.AgeRestricted
+	}
+	pc.savePhoto(ctx, currentUser, updatedPhoto)
+}
+
+// PatchPhoto digunakan untuk memperbarui sebagian field foto dengan JSON Merge Patch. Field yang tidak dikirim tidak diubah,
+// caption bernilai null dikosongkan, comment_policy bernilai null dikembalikan ke everyone, dan age_restricted bernilai null
+// dikembalikan ke false.
+func (pc *PhotoController) PatchPhoto(ctx *gin.Context) {
+	photoID := ctx.Param("photoId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	patch, ok := bindMergePatch(ctx)
+	if !ok {
+		return
+	}
+
+	updatedPhoto, ok := pc.findOwnPhoto(ctx, photoID, currentUser)
+	if !ok {
+		return
+	}
+
+	patch.String("title", &updatedPhoto.Title, false)
+	patch.String("caption", &updatedPhoto.Caption, true)
+	patch.String("photo_url", &updatedPhoto.PhotoURL, false)
+	patch.String("comment_policy", &updatedPhoto.CommentPolicy, true)
+	patch.Bool("age_restricted", &updatedPhoto.AgeRestricted, false)
+	if patch.Has("title") && updatedPhoto.Title == "" {
+		patch.Fail("title", "Title harus diisi.")
+	}
+	if patch.Has("photo_url") && !utils.IsValidURL(updatedPhoto.PhotoURL) {
+		patch.Fail("photo_url", "Format photo URL tidak valid.")
+	}
+	if updatedPhoto.CommentPolicy == "" {
+		updatedPhoto.CommentPolicy = models.CommentPolicyEveryone
+	} else if !isValidCommentPolicy(updatedPhoto.CommentPolicy) {
+		patch.Fail("comment_policy", "Comment policy harus salah satu dari everyone, followers, atau nobody.")
+	}
+	if respondPatchErrors(ctx, patch) {
+		return
+	}
+
+	pc.savePhoto(ctx, currentUser, updatedPhoto)
+}
+
+// findOwnPhoto mengambil foto dengan ID photoID milik currentUser yang versinya cocok dengan If-Match. Jika foto tidak ditemukan,
+// bukan milik currentUser, atau sudah diubah, respons error langsung dikirim dan fungsi mengembalikan false.
+func (pc *PhotoController) findOwnPhoto(ctx *gin.Context, photoID string, currentUser models.User) (models.Photo, bool) {
+	var photo models.Photo
+	if err := pc.DB.First(&photo, "id = ?", photoID).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada photo dengan ID tersebut."})
+		return photo, false
+	}
+
+	// Periksa apakah pengguna yang sedang masuk adalah pemilik foto yang akan diperbarui
+	if photo.UserID != currentUser.ID {
+		ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu tidak diizinkan untuk mengedit photo ini."})
+		return photo, false
+	}
+	return photo, checkIfMatch(ctx, photo.Version)
+}
 
-	pc.DB.Save(&updatedPhoto)
+// savePhoto menyimpan perubahan foto beserta mention dan event-nya, lalu mengirim foto yang diperbarui sebagai respons
+func (pc *PhotoController) savePhoto(ctx *gin.Context, currentUser models.User, updatedPhoto models.Photo) {
+	updatedPhoto.UpdatedAt = time.Now()
+	err := pc.DB.Transaction(func(tx *gorm.DB) error {
+		// like_count dikecualikan agar tidak menimpa perubahan like yang terjadi bersamaan
+		if err := saveVersioned(tx, &updatedPhoto, &updatedPhoto.Version, "LikeCount"); err != nil {
+			return err
+		}
+		if err := syncMentions(tx, models.MentionSourcePhoto, updatedPhoto.ID, currentUser, updatedPhoto.Caption); err != nil {
+			return err
+		}
+		return events.Record(tx, events.Event{
+			Type:          events.PhotoUpdated,
+			AggregateType: models.TargetPhoto,
+			AggregateID:   updatedPhoto.ID,
+			ActorID:       currentUser.ID,
+			Data:          photoEventData(updatedPhoto),
+		})
+	})
+	if errors.Is(err, errVersionConflict) {
+		respondVersionConflict(ctx, nil)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	setETag(ctx, updatedPhoto.Version)
+	mentions := mentionEntities(pc.DB, models.MentionSourcePhoto, []int64{updatedPhoto.ID})
 
 	// Mengonversi data yang diperbarui menjadi respons sesuai dengan spesifikasi OpenAPI
 	responseData := gin.H{
-		"id":        updatedPhoto.ID,
-		"caption":   updatedPhoto.Caption,
-		"title":     updatedPhoto.Title,
-		"photo_url": updatedPhoto.PhotoURL,
-		"user_id":   updatedPhoto.UserID,
+		"id":             updatedPhoto.ID,
+		"caption":        updatedPhoto.Caption,
+		"title":          updatedPhoto.Title,
+		"photo_url":      updatedPhoto.PhotoURL,
+		"user_id":        updatedPhoto.UserID,
+		"comment_policy": updatedPhoto.CommentPolicy,
+		"age_restricted": updatedPhoto.AgeRestricted,
+		"mentions":       mentionsOrEmpty(mentions[updatedPhoto.ID]),
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
 }
 
-// FindPhotoByID digunakan untuk menemukan foto berdasarkan ID
+// FindPhotoByID digunakan untuk menemukan foto berdasarkan ID. ETag respons dapat dipakai untuk If-None-Match dan If-Match.
 func (pc *PhotoController) FindPhotoByID(ctx *gin.Context) {
 	photoID := ctx.Param("photoId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
 
 	var photo models.Photo
 	result := pc.DB.First(&photo, "id = ?", photoID)
-	if result.Error != nil {
+	if result.Error != nil || isBlocked(pc.DB, currentUser.ID, photo.UserID) || !canViewPhoto(pc.DB, currentUser, photo) {
 		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada photo dengan ID tersebut."})
 		return
 	}
@@ -158,13 +283,21 @@ func (pc *PhotoController) FindPhotoByID(ctx *gin.Context) {
 	user := models.User{}
 	pc.DB.First(&user, photo.UserID)
 
+	liked := likedPhotoIDs(pc.DB, currentUser.ID, []int64{photo.ID})
+	mentions := mentionEntities(pc.DB, models.MentionSourcePhoto, []int64{photo.ID})
+
 	// Membuat objek JSON yang sesuai dengan spesifikasi OpenAPI
 	responseData := gin.H{
-		"id":        photo.ID,
-		"caption":   photo.Caption,
-		"title":     photo.Title,
-		"photo_url": photo.PhotoURL,
-		"user_id":   photo.UserID,
+		"id":             photo.ID,
+		"caption":        photo.Caption,
+		"title":          photo.Title,
+		"photo_url":      photo.PhotoURL,
+		"user_id":        photo.UserID,
+		"like_count":     photo.LikeCount,
+		"liked_by_me":    liked[photo.ID],
+		"comment_policy": photo.CommentPolicy,
+		"age_restricted": photo.AgeRestricted,
+		"mentions":       mentionsOrEmpty(mentions[photo.ID]),
 		"user": gin.H{
 			"id":       user.ID,
 			"email":    user.Email,
@@ -172,25 +305,35 @@ func (pc *PhotoController) FindPhotoByID(ctx *gin.Context) {
 		},
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+	respondWithETag(ctx, photo.Version, gin.H{"status": "success", "data": responseData})
 }
 
-// FindPhotos digunakan untuk menemukan daftar foto dengan opsi paging
+// FindPhotos digunakan untuk menemukan daftar foto dengan opsi paging.
+// Foto dari pengguna yang diblokir atau dibisukan oleh pengguna saat ini dan foto yang disembunyikan moderator tidak ditampilkan.
 func (pc *PhotoController) FindPhotos(ctx *gin.Context) {
-	var page = ctx.DefaultQuery("page", "1")
-	var limit = ctx.DefaultQuery("limit", "10")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	_, limit, offset := parsePagination(ctx)
 
-	intPage, _ := strconv.Atoi(page)
-	intLimit, _ := strconv.Atoi(limit)
-	offset := (intPage - 1) * intLimit
+	query := pc.DB.Scopes(visiblePhotos(currentUser))
+	if hidden := feedHiddenUserIDs(pc.DB, currentUser.ID); len(hidden) > 0 {
+		query = query.Where("user_id NOT IN ?", hidden)
+	}
 
 	var photos []models.Photo
-	results := pc.DB.Limit(intLimit).Offset(offset).Find(&photos)
+	results := query.Limit(limit).Offset(offset).Find(&photos)
 	if results.Error != nil {
 		ctx.JSON(http.StatusBadGateway, gin.H{"status": "error", "message": results.Error})
 		return
 	}
 
+	// Ambil status like pengguna saat ini untuk seluruh foto di halaman ini sekaligus
+	photoIDs := make([]int64, 0, len(photos))
+	for _, photo := range photos {
+		photoIDs = append(photoIDs, photo.ID)
+	}
+	liked := likedPhotoIDs(pc.DB, currentUser.ID, photoIDs)
+	mentions := mentionEntities(pc.DB, models.MentionSourcePhoto, photoIDs)
+
 	// Membuat slice untuk menyimpan hasil response yang sesuai dengan spesifikasi OpenAPI
 	var responseData []gin.H
 	for _, photo := range photos {
@@ -198,11 +341,16 @@ func (pc *PhotoController) FindPhotos(ctx *gin.Context) {
 		pc.DB.First(&user, photo.UserID) // Ambil informasi pengguna dari basis data berdasarkan ID yang terkait dengan foto
 
 		responseData = append(responseData, gin.H{
-			"id":        photo.ID,
-			"caption":   photo.Caption,
-			"title":     photo.Title,
-			"photo_url": photo.PhotoURL,
-			"user_id":   photo.UserID,
+			"id":             photo.ID,
+			"caption":        photo.Caption,
+			"title":          photo.Title,
+			"photo_url":      photo.PhotoURL,
+			"user_id":        photo.UserID,
+			"like_count":     photo.LikeCount,
+			"liked_by_me":    liked[photo.ID],
+			"comment_policy": photo.CommentPolicy,
+			"age_restricted": photo.AgeRestricted,
+			"mentions":       mentionsOrEmpty(mentions[photo.ID]),
 			"user": gin.H{
 				"id":       user.ID,
 				"email":    user.Email,
@@ -232,14 +380,84 @@ func (pc *PhotoController) DeletePhoto(ctx *gin.Context) {
 		ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu tidak diizinkan untuk menghapus photo ini."})
 		return
 	}
+	if !checkIfMatch(ctx, photo.Version) {
+		return
+	}
 
-	// Hapus foto dari basis data
-	result = pc.DB.Delete(&photo)
-
-	if result.RowsAffected == 0 {
+	// Hapus foto beserta mention pada caption-nya dari basis data
+	err := pc.DB.Transaction(func(tx *gorm.DB) error {
+		if ctx.GetHeader("If-Match") != "" {
+			if err := lockVersion(tx, &models.Photo{}, photo.ID, photo.Version); err != nil {
+				return err
+			}
+		}
+		if err := removePhoto(tx, photo, currentUser.ID); err != nil {
+			return err
+		}
+		entry := auditEntry(ctx, &currentUser.ID, models.AuditPhotoDelete, models.TargetPhoto, photo.ID)
+		entry.Before = photoAuditFields(photo)
+		return audit.Record(tx, entry)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada photo dengan ID tersebut."})
 		return
 	}
+	if errors.Is(err, errVersionConflict) {
+		respondVersionConflict(ctx, nil)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
 
 	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
 }
+
+// removePhoto memindahkan foto ke tempat sampah, menghapus mention pada caption-nya, dan mencatat domain event atas nama actorID
+func removePhoto(tx *gorm.DB, photo models.Photo, actorID int64) error {
+	if err := deleteMentions(tx, models.MentionSourcePhoto, photo.ID); err != nil {
+		return err
+	}
+	if err := tx.Model(&photo).UpdateColumn("deleted_by_id", actorID).Error; err != nil {
+		return err
+	}
+	result := tx.Delete(&photo)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return events.Record(tx, events.Event{
+		Type:          events.PhotoDeleted,
+		AggregateType: models.TargetPhoto,
+		AggregateID:   photo.ID,
+		ActorID:       actorID,
+		Data:          photoEventData(photo),
+	})
+}
+
+// photoEventData membuat data domain event untuk sebuah foto
+func photoEventData(photo models.Photo) map[string]interface{} {
+	return map[string]interface{}{
+		"id":             photo.ID,
+		"title":          photo.Title,
+		"caption":        photo.Caption,
+		"photo_url":      photo.PhotoURL,
+		"user_id":        photo.UserID,
+		"age_restricted": photo.AgeRestricted,
+		"created_at":     photo.CreatedAt,
+		"updated_at":     photo.UpdatedAt,
+	}
+}
+
+// isValidCommentPolicy menentukan apakah policy merupakan pengaturan komentar yang dikenal
+func isValidCommentPolicy(policy string) bool {
+	switch policy {
+	case models.CommentPolicyEveryone, models.CommentPolicyFollowers, models.CommentPolicyNobody:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"mygram-final-project/audit"
 	"mygram-final-project/models"
 	"mygram-final-project/utils"
 )
@@ -102,19 +104,19 @@ func (smc *SocialMediaController) GetSocialMedias(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
 }
 
-// GetSocialMediaByID mengambil informasi media sosial berdasarkan ID
+// GetSocialMediaByID mengambil informasi media sosial berdasarkan ID.
+// Media sosial milik akun privat hanya terlihat oleh pemiliknya, pengikutnya, dan moderator, dan email hanya terlihat oleh pemiliknya.
+// ETag respons dapat dipakai untuk If-None-Match dan If-Match.
 func (smc *SocialMediaController) GetSocialMediaByID(ctx *gin.Context) {
 	socialMediaID := ctx.Param("socialMediaId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
 
 	var socialMedia models.SocialMedia
 	result := smc.DB.Preload("User").First(&socialMedia, "id = ?", socialMediaID)
-	if result.Error != nil {
+	if result.Error != nil || isBlocked(smc.DB, currentUser.ID, socialMedia.UserID) || !canViewUserContent(smc.DB, currentUser, socialMedia.User) {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada social media dengan ID tersebut."})
 		return
 	}
-	user := models.User{}
-	smc.DB.First(&user, socialMedia.UserID)
-
 	// Membuat objek JSON yang sesuai dengan spesifikasi OpenAPI
 	responseData := gin.H{
 		"id":               socialMedia.ID,
@@ -123,12 +125,14 @@ func (smc *SocialMediaController) GetSocialMediaByID(ctx *gin.Context) {
 		"user_id":          socialMedia.UserID,
 		"user": gin.H{
 			"id":       socialMedia.User.ID,
-			"email":    socialMedia.User.Email,
 			"username": socialMedia.User.Username,
 		},
 	}
+	if socialMedia.UserID == currentUser.ID {
+		responseData["user"].(gin.H)["email"] = socialMedia.User.Email
+	}
 
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+	respondWithETag(ctx, socialMedia.Version, gin.H{"status": "success", "data": responseData})
 }
 
 // UpdateSocialMedia digunakan untuk memperbarui media sosial yang ada
@@ -160,22 +164,87 @@ func (smc *SocialMediaController) UpdateSocialMedia(ctx *gin.Context) {
 		return
 	}
 
+	updatedSocialMedia, ok := smc.findOwnSocialMedia(ctx, socialMediaID, currentUser)
+	if !ok {
+		return
+	}
+
+	before := socialMediaAuditFields(updatedSocialMedia)
+	updatedSocialMedia.Name = payload.Name
+	updatedSocialMedia.SocialMediaURL = payload.SocialMediaURL
+	smc.saveSocialMedia(ctx, currentUser, updatedSocialMedia, before)
+}
+
+// PatchSocialMedia digunakan untuk memperbarui sebagian field media sosial dengan JSON Merge Patch.
+// Field yang tidak dikirim tidak diubah.
+func (smc *SocialMediaController) PatchSocialMedia(ctx *gin.Context) {
+	socialMediaID := ctx.Param("socialMediaId")
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	patch, ok := bindMergePatch(ctx)
+	if !ok {
+		return
+	}
+
+	updatedSocialMedia, ok := smc.findOwnSocialMedia(ctx, socialMediaID, currentUser)
+	if !ok {
+		return
+	}
+
+	before := socialMediaAuditFields(updatedSocialMedia)
+	patch.String("name", &updatedSocialMedia.Name, false)
+	patch.String("social_media_url", &updatedSocialMedia.SocialMediaURL, false)
+	if patch.Has("name") && updatedSocialMedia.Name == "" {
+		patch.Fail("name", "Name harus diisi.")
+	}
+	if patch.Has("social_media_url") && !utils.IsValidURL(updatedSocialMedia.SocialMediaURL) {
+		patch.Fail("social_media_url", "Format social media URL tidak valid.")
+	}
+	if respondPatchErrors(ctx, patch) {
+		return
+	}
+
+	smc.saveSocialMedia(ctx, currentUser, updatedSocialMedia, before)
+}
+
+// findOwnSocialMedia mengambil media sosial dengan ID socialMediaID milik currentUser yang versinya cocok dengan If-Match.
+// Jika tidak ditemukan, bukan milik currentUser, atau sudah diubah, respons error langsung dikirim dan fungsi mengembalikan false.
+func (smc *SocialMediaController) findOwnSocialMedia(ctx *gin.Context, socialMediaID string, currentUser models.User) (models.SocialMedia, bool) {
 	var updatedSocialMedia models.SocialMedia
 	result := smc.DB.First(&updatedSocialMedia, "id = ?", socialMediaID)
 	if result.Error != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada social media dengan ID tersebut."})
-		return
+		return updatedSocialMedia, false
 	}
 
 	// Periksa apakah pengguna yang sedang masuk adalah pemilik dari entri media sosial yang akan diperbarui
 	if updatedSocialMedia.UserID != currentUser.ID {
 		ctx.JSON(http.StatusForbidden, gin.H{"message": "Kamu tidak diizinkan untuk mengedit social media ini."})
+		return updatedSocialMedia, false
+	}
+	return updatedSocialMedia, checkIfMatch(ctx, updatedSocialMedia.Version)
+}
+
+// saveSocialMedia menyimpan perubahan media sosial beserta catatan audit-nya, lalu mengirim data yang diperbarui sebagai respons
+func (smc *SocialMediaController) saveSocialMedia(ctx *gin.Context, currentUser models.User, updatedSocialMedia models.SocialMedia, before audit.Fields) {
+	err := smc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, &updatedSocialMedia, &updatedSocialMedia.Version); err != nil {
+			return err
+		}
+		entry := auditEntry(ctx, &currentUser.ID, models.AuditSocialMediaUpdate, models.TargetSocialMedia, updatedSocialMedia.ID)
+		entry.Before, entry.After = before, socialMediaAuditFields(updatedSocialMedia)
+		return audit.Record(tx, entry)
+	})
+	if errors.Is(err, errVersionConflict) {
+		respondVersionConflict(ctx, nil)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
 		return
 	}
 
-	updatedSocialMedia.Name = payload.Name
-	updatedSocialMedia.SocialMediaURL = payload.SocialMediaURL
-	smc.DB.Save(&updatedSocialMedia)
+	setETag(ctx, updatedSocialMedia.Version)
 
 	// Membuat objek JSON yang sesuai dengan spesifikasi OpenAPI
 	responseData := gin.H{
@@ -205,12 +274,39 @@ func (smc *SocialMediaController) DeleteSocialMedia(ctx *gin.Context) {
 		ctx.JSON(http.StatusForbidden, gin.H{"message": "Kamu tidak diizinkan untuk menghapus social media ini."})
 		return
 	}
+	if !checkIfMatch(ctx, socialMedia.Version) {
+		return
+	}
 
-	result = smc.DB.Delete(&socialMedia)
-	if result.RowsAffected == 0 {
+	err := smc.DB.Transaction(func(tx *gorm.DB) error {
+		if ctx.GetHeader("If-Match") != "" {
+			if err := lockVersion(tx, &models.SocialMedia{}, socialMedia.ID, socialMedia.Version); err != nil {
+				return err
+			}
+		}
+		result := tx.Delete(&socialMedia)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		entry := auditEntry(ctx, &currentUser.ID, models.AuditSocialMediaDelete, models.TargetSocialMedia, socialMedia.ID)
+		entry.Before = socialMediaAuditFields(socialMedia)
+		return audit.Record(tx, entry)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada social media dengan ID tersebut."})
 		return
 	}
+	if errors.Is(err, errVersionConflict) {
+		respondVersionConflict(ctx, nil)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
 
 	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
 }
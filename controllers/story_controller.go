@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/models"
+	"mygram-final-project/utils"
+)
+
+// defaultStoryTTL adalah masa berlaku story jika tidak diatur melalui konfigurasi
+const defaultStoryTTL = 24 * time.Hour
+
+// StoryController adalah kontroler untuk story yang otomatis kedaluwarsa
+type StoryController struct {
+	DB  *gorm.DB
+	TTL time.Duration
+}
+
+// NewStoryController digunakan untuk membuat instance baru dari StoryController dengan masa berlaku story ttl
+func NewStoryController(DB *gorm.DB, ttl time.Duration) StoryController {
+	if ttl <= 0 {
+		ttl = defaultStoryTTL
+	}
+	return StoryController{DB, ttl}
+}
+
+// CreateStory digunakan untuk membuat story baru yang kedaluwarsa setelah masa berlaku TTL
+func (sc *StoryController) CreateStory(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.CreateStoryRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if !utils.IsValidURL(payload.MediaURL) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Format media URL tidak valid"})
+		return
+	}
+
+	mediaType := strings.ToLower(payload.MediaType)
+	if mediaType == "" {
+		mediaType = models.StoryMediaImage
+	}
+	if mediaType != models.StoryMediaImage && mediaType != models.StoryMediaVideo {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Media type harus image atau video."})
+		return
+	}
+
+	now := time.Now()
+	story := models.Story{
+		UserID:    currentUser.ID,
+		MediaURL:  payload.MediaURL,
+		MediaType: mediaType,
+		Caption:   payload.Caption,
+		ExpiresAt: now.Add(sc.TTL),
+		CreatedAt: now,
+	}
+	if err := sc.DB.Create(&story).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": storyResponse(story, true)})
+}
+
+// GetStoryTray mengambil story aktif milik pengguna saat ini dan pengguna yang diikutinya, dikelompokkan per pengguna.
+// Story milik sendiri ditampilkan pertama, lalu pengguna dengan story yang belum dilihat, lalu yang terbaru.
+func (sc *StoryController) GetStoryTray(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	query := sc.DB.Preload("User").
+		Where("expires_at > ?", time.Now()).
+		Where("user_id = ? OR user_id IN (?)", currentUser.ID,
+			sc.DB.Model(&models.Follow{}).Select("following_id").Where("follower_id = ?", currentUser.ID))
+	if hidden := feedHiddenUserIDs(sc.DB, currentUser.ID); len(hidden) > 0 {
+		query = query.Where("user_id NOT IN ?", hidden)
+	}
+
+	var stories []models.Story
+	if err := query.Order("created_at ASC, id ASC").Find(&stories).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ids := make([]int64, 0, len(stories))
+	for _, story := range stories {
+		ids = append(ids, story.ID)
+	}
+	seen := seenStoryIDs(sc.DB, currentUser.ID, ids)
+
+	type trayItem struct {
+		user     models.User
+		stories  []gin.H
+		unseen   bool
+		latestAt time.Time
+	}
+	items := map[int64]*trayItem{}
+	var order []*trayItem
+	for _, story := range stories {
+		item, ok := items[story.UserID]
+		if !ok {
+			item = &trayItem{user: story.User}
+			items[story.UserID] = item
+			order = append(order, item)
+		}
+		isSeen := story.UserID == currentUser.ID || seen[story.ID]
+		item.stories = append(item.stories, storyResponse(story, isSeen))
+		item.unseen = item.unseen || !isSeen
+		item.latestAt = story.CreatedAt
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if (a.user.ID == currentUser.ID) != (b.user.ID == currentUser.ID) {
+			return a.user.ID == currentUser.ID
+		}
+		if a.unseen != b.unseen {
+			return a.unseen
+		}
+		return a.latestAt.After(b.latestAt)
+	})
+
+	responseData := []gin.H{}
+	for _, item := range order {
+		responseData = append(responseData, gin.H{
+			"user": gin.H{
+				"id":                item.user.ID,
+				"username":          item.user.Username,
+				"profile_image_url": item.user.ProfileImageURL,
+			},
+			"has_unseen": item.unseen,
+			"stories":    item.stories,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+}
+
+// GetStoryByID mengambil story yang masih aktif dan mencatat pengguna saat ini sebagai penonton
+func (sc *StoryController) GetStoryByID(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	story, ok := sc.findActiveStory(ctx, currentUser)
+	if !ok {
+		return
+	}
+
+	if story.UserID != currentUser.ID {
+		err := sc.DB.Transaction(func(tx *gorm.DB) error {
+			view := models.StoryView{StoryID: story.ID, ViewerID: currentUser.ID, ViewedAt: time.Now()}
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&view)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			story.ViewCount++
+			return tx.Model(&models.Story{}).Where("id = ?", story.ID).
+				UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": storyResponse(story, true)})
+}
+
+// GetStoryViewers mengambil daftar pengguna yang sudah melihat story. Hanya pemilik story yang dapat melihat daftar ini.
+func (sc *StoryController) GetStoryViewers(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	page, limit, offset := parsePagination(ctx)
+
+	story, ok := sc.findActiveStory(ctx, currentUser)
+	if !ok {
+		return
+	}
+	if story.UserID != currentUser.ID {
+		ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Hanya pemilik story yang dapat melihat daftar penonton."})
+		return
+	}
+
+	var total int64
+	sc.DB.Model(&models.StoryView{}).Where("story_id = ?", story.ID).Count(&total)
+
+	var views []models.StoryView
+	result := sc.DB.Preload("Viewer").Where("story_id = ?", story.ID).
+		Order("viewed_at DESC").Limit(limit).Offset(offset).Find(&views)
+	if result.Error != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, view := range views {
+		responseData = append(responseData, gin.H{
+			"id":                view.Viewer.ID,
+			"username":          view.Viewer.Username,
+			"profile_image_url": view.Viewer.ProfileImageURL,
+			"viewed_at":         view.ViewedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// DeleteStory digunakan untuk menghapus story milik pengguna saat ini sebelum kedaluwarsa
+func (sc *StoryController) DeleteStory(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	story, ok := sc.findActiveStory(ctx, currentUser)
+	if !ok {
+		return
+	}
+	if story.UserID != currentUser.ID {
+		ctx.JSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu tidak diizinkan untuk menghapus story ini."})
+		return
+	}
+
+	if err := sc.DB.Delete(&story).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "message": "Story berhasil dihapus."})
+}
+
+// findActiveStory mengambil story pada parameter storyId yang belum kedaluwarsa, tidak terhalang pemblokiran,
+// dan tidak berasal dari akun privat yang tidak diikuti pengguna saat ini
+func (sc *StoryController) findActiveStory(ctx *gin.Context, currentUser models.User) (models.Story, bool) {
+	var story models.Story
+	err := sc.DB.Preload("User").First(&story, "id = ? AND expires_at > ?", ctx.Param("storyId"), time.Now()).Error
+	if err != nil || isBlocked(sc.DB, currentUser.ID, story.UserID) || !canViewUserContent(sc.DB, currentUser, story.User) {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada story dengan ID tersebut."})
+		return story, false
+	}
+	return story, true
+}
+
+// seenStoryIDs mengembalikan story di antara storyIDs yang sudah dilihat oleh userID
+func seenStoryIDs(db *gorm.DB, userID int64, storyIDs []int64) map[int64]bool {
+	seen := map[int64]bool{}
+	if len(storyIDs) == 0 {
+		return seen
+	}
+
+	var ids []int64
+	db.Model(&models.StoryView{}).Where("viewer_id = ? AND story_id IN ?", userID, storyIDs).Pluck("story_id", &ids)
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return seen
+}
+
+// storyResponse membuat data respons story
+func storyResponse(story models.Story, seen bool) gin.H {
+	return gin.H{
+		"id":         story.ID,
+		"user_id":    story.UserID,
+		"media_url":  story.MediaURL,
+		"media_type": story.MediaType,
+		"caption":    story.Caption,
+		"view_count": story.ViewCount,
+		"seen":       seen,
+		"expires_at": story.ExpiresAt,
+		"created_at": story.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+	"mygram-final-project/realtime"
+)
+
+// streamHeartbeatInterval adalah jeda pengiriman heartbeat agar koneksi tidak diputus oleh proxy
+const streamHeartbeatInterval = 25 * time.Second
+
+// StreamController adalah kontroler untuk stream event real-time melalui Server-Sent Events
+type StreamController struct {
+	DB  *gorm.DB
+	Hub *realtime.Hub
+}
+
+// NewStreamController digunakan untuk membuat instance baru dari StreamController
+func NewStreamController(DB *gorm.DB, hub *realtime.Hub) StreamController {
+	return StreamController{DB, hub}
+}
+
+// Stream membuka koneksi Server-Sent Events untuk pengguna saat ini.
+// Query photos berisi daftar ID foto (dipisah koma) yang sedang dilihat untuk menerima komentar dan like baru pada foto tersebut.
+// Foto yang tidak boleh dilihat pengguna saat ini diabaikan. Notifikasi dan pesan pribadi untuk pengguna saat ini selalu dikirim.
+func (sc *StreamController) Stream(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var photoIDs []int64
+	if photos := ctx.Query("photos"); photos != "" {
+		for _, value := range strings.Split(photos, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"message": "Format photos tidak valid."})
+				return
+			}
+			photoIDs = append(photoIDs, id)
+		}
+	}
+	photoIDs, err := sc.viewablePhotoIDs(currentUser, photoIDs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	subscriber := sc.Hub.Subscribe(currentUser.ID, photoIDs)
+	defer sc.Hub.Unsubscribe(subscriber)
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.SSEvent("ready", gin.H{"user_id": currentUser.ID, "photos": photoIDs})
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-subscriber.Events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event.Data)
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent("heartbeat", gin.H{"time": time.Now()})
+			return true
+		}
+	})
+}
+
+// viewablePhotoIDs menyaring photoIDs menjadi foto yang ada dan boleh dilihat viewer dengan aturan yang sama seperti
+// FindPhotoByID, sehingga komentar dan like pada foto lain tidak pernah dikirim melalui stream
+func (sc *StreamController) viewablePhotoIDs(viewer models.User, photoIDs []int64) ([]int64, error) {
+	viewable := []int64{}
+	if len(photoIDs) == 0 {
+		return viewable, nil
+	}
+
+	query := sc.DB.Model(&models.Photo{}).Scopes(visiblePhotos(viewer)).Where("id IN ?", photoIDs)
+	if hidden := blockedUserIDs(sc.DB, viewer.ID); len(hidden) > 0 {
+		query = query.Where("user_id NOT IN ?", hidden)
+	}
+	if err := query.Order("id").Pluck("id", &viewable).Error; err != nil {
+		return nil, err
+	}
+	return viewable, nil
+}
@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/models"
+)
+
+// TrashController adalah kontroler untuk tempat sampah pengguna: daftar konten yang dihapus dan pemulihannya
+type TrashController struct {
+	DB        *gorm.DB
+	Retention time.Duration
+}
+
+// NewTrashController digunakan untuk membuat instance baru dari TrashController dengan masa retensi retention
+func NewTrashController(DB *gorm.DB, retention time.Duration) TrashController {
+	if retention <= 0 {
+		retention = models.DefaultTrashRetention
+	}
+	return TrashController{DB, retention}
+}
+
+// restoreConflictError adalah error karena konten tidak dapat dipulihkan dalam keadaan saat ini
+type restoreConflictError struct {
+	message string
+}
+
+func (e restoreConflictError) Error() string {
+	return e.message
+}
+
+// GetTrash mengambil foto, komentar, dan media sosial milik pengguna saat ini yang dihapus sendiri dan masih dapat dipulihkan
+func (tc *TrashController) GetTrash(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	cutoff := time.Now().Add(-tc.Retention)
+
+	var photos []models.Photo
+	err := tc.DB.Unscoped().
+		Where("user_id = ? AND deleted_by_id = ? AND deleted_at > ?", currentUser.ID, currentUser.ID, cutoff).
+		Order("deleted_at DESC").Find(&photos).Error
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	var comments []models.Comment
+	err = tc.DB.Unscoped().
+		Where("user_id = ? AND deleted_by_id = ?", currentUser.ID, currentUser.ID).
+		Where("deleted_at > ? OR (deleted_at IS NULL AND tombstoned = ? AND tombstoned_at > ?)", cutoff, true, cutoff).
+		Order("COALESCE(deleted_at, tombstoned_at) DESC").Find(&comments).Error
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	var socialMedias []models.SocialMedia
+	err = tc.DB.Unscoped().
+		Where("user_id = ? AND deleted_at > ?", currentUser.ID, cutoff).
+		Order("deleted_at DESC").Find(&socialMedias).Error
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	photoData := []gin.H{}
+	for _, photo := range photos {
+		photoData = append(photoData, gin.H{
+			"id":         photo.ID,
+			"title":      photo.Title,
+			"caption":    photo.Caption,
+			"photo_url":  photo.PhotoURL,
+			"deleted_at": photo.DeletedAt.Time,
+			"expires_at": photo.DeletedAt.Time.Add(tc.Retention),
+		})
+	}
+
+	commentData := []gin.H{}
+	for _, comment := range comments {
+		deletedAt := commentDeletedAt(comment)
+		commentData = append(commentData, gin.H{
+			"id":         comment.ID,
+			"message":    comment.Message,
+			"photo_id":   comment.PhotoID,
+			"parent_id":  comment.ParentID,
+			"deleted_at": deletedAt,
+			"expires_at": deletedAt.Add(tc.Retention),
+		})
+	}
+
+	socialMediaData := []gin.H{}
+	for _, socialMedia := range socialMedias {
+		socialMediaData = append(socialMediaData, gin.H{
+			"id":               socialMedia.ID,
+			"name":             socialMedia.Name,
+			"social_media_url": socialMedia.SocialMediaURL,
+			"deleted_at":       socialMedia.DeletedAt.Time,
+			"expires_at":       socialMedia.DeletedAt.Time.Add(tc.Retention),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"photos":        photoData,
+		"comments":      commentData,
+		"social_medias": socialMediaData,
+	}})
+}
+
+// RestorePhoto digunakan untuk memulihkan foto milik pengguna saat ini dari tempat sampah
+func (tc *TrashController) RestorePhoto(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var photo models.Photo
+	err := tc.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&photo, "id = ? AND user_id = ? AND deleted_by_id = ? AND deleted_at > ?",
+				ctx.Param("photoId"), currentUser.ID, currentUser.ID, time.Now().Add(-tc.Retention)).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&photo).UpdateColumns(map[string]interface{}{"deleted_at": nil, "deleted_by_id": nil}).Error
+		if err != nil {
+			return err
+		}
+		return syncMentions(tx, models.MentionSourcePhoto, photo.ID, currentUser, photo.Caption)
+	})
+	if err != nil {
+		tc.respondRestoreError(ctx, err, "Tidak ada photo dengan ID tersebut di tempat sampah.")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"id": photo.ID, "title": photo.Title, "restored": true}})
+}
+
+// RestoreComment digunakan untuk memulihkan komentar milik pengguna saat ini dari tempat sampah.
+// Foto komentar harus masih ada, dan induk yang sudah dibersihkan dipulihkan kembali sebagai tombstone.
+func (tc *TrashController) RestoreComment(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	cutoff := time.Now().Add(-tc.Retention)
+
+	var comment models.Comment
+	err := tc.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&comment, "id = ? AND user_id = ? AND deleted_by_id = ?", ctx.Param("commentId"), currentUser.ID, currentUser.ID).Error
+		if err != nil {
+			return err
+		}
+		deletedAt := commentDeletedAt(comment)
+		if deletedAt.IsZero() || deletedAt.Before(cutoff) {
+			return gorm.ErrRecordNotFound
+		}
+
+		var photo models.Photo
+		if err := tx.First(&photo, "id = ?", comment.PhotoID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return restoreConflictError{"Photo dari komentar ini sudah dihapus. Pulihkan photo terlebih dahulu."}
+			}
+			return err
+		}
+
+		if comment.DeletedAt.Valid && comment.ParentID != nil {
+			if err := restoreCommentParent(tx, *comment.ParentID); err != nil {
+				return err
+			}
+		}
+
+		comment.Tombstoned = false
+		err = tx.Unscoped().Model(&comment).UpdateColumns(map[string]interface{}{
+			"deleted_at":    nil,
+			"deleted_by_id": nil,
+			"tombstoned":    false,
+			"tombstoned_at": nil,
+		}).Error
+		if err != nil || comment.Status != models.CommentPublished {
+			return err
+		}
+		return syncMentions(tx, models.MentionSourceComment, comment.ID, currentUser, comment.Message)
+	})
+	if err != nil {
+		tc.respondRestoreError(ctx, err, "Tidak ada komentar dengan ID tersebut di tempat sampah.")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"id": comment.ID, "message": comment.Message, "restored": true}})
+}
+
+// RestoreSocialMedia digunakan untuk memulihkan entri media sosial milik pengguna saat ini dari tempat sampah
+func (tc *TrashController) RestoreSocialMedia(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var socialMedia models.SocialMedia
+	err := tc.DB.Unscoped().
+		First(&socialMedia, "id = ? AND user_id = ? AND deleted_at > ?", ctx.Param("socialMediaId"), currentUser.ID, time.Now().Add(-tc.Retention)).Error
+	if err == nil {
+		err = tc.DB.Unscoped().Model(&socialMedia).UpdateColumn("deleted_at", nil).Error
+	}
+	if err != nil {
+		tc.respondRestoreError(ctx, err, "Tidak ada social media dengan ID tersebut di tempat sampah.")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"id": socialMedia.ID, "name": socialMedia.Name, "restored": true}})
+}
+
+// respondRestoreError mengubah error pemulihan menjadi respons HTTP
+func (tc *TrashController) respondRestoreError(ctx *gin.Context, err error, notFoundMessage string) {
+	var conflictErr restoreConflictError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"message": notFoundMessage})
+	case errors.As(err, &conflictErr):
+		ctx.JSON(http.StatusConflict, gin.H{"message": conflictErr.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+	}
+}
+
+// restoreCommentParent mengembalikan komentar induk ke thread lalu menambah jumlah balasannya.
+// Induk yang sudah dipindahkan ke tempat sampah dipulihkan sebagai tombstone agar isinya tetap tersembunyi,
+// termasuk induk-induk di atasnya.
+func restoreCommentParent(tx *gorm.DB, parentID int64) error {
+	var parent models.Comment
+	if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, "id = ?", parentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return restoreConflictError{"Komentar induk sudah dihapus permanen."}
+		}
+		return err
+	}
+
+	if parent.DeletedAt.Valid {
+		if parent.ParentID != nil {
+			if err := restoreCommentParent(tx, *parent.ParentID); err != nil {
+				return err
+			}
+		}
+		updates := map[string]interface{}{"deleted_at": nil}
+		if !parent.Tombstoned {
+			updates["tombstoned"] = true
+			updates["tombstoned_at"] = parent.DeletedAt.Time
+		}
+		if err := tx.Unscoped().Model(&parent).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&parent).UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+}
+
+// commentDeletedAt mengembalikan waktu komentar dihapus, baik dipindahkan ke tempat sampah maupun diubah menjadi tombstone
+func commentDeletedAt(comment models.Comment) time.Time {
+	if comment.DeletedAt.Valid {
+		return comment.DeletedAt.Time
+	}
+	if comment.Tombstoned && comment.TombstonedAt != nil {
+		return *comment.TombstonedAt
+	}
+	return time.Time{}
+}
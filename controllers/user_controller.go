@@ -1,28 +1,67 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"mygram-final-project/accounts"
+	"mygram-final-project/audit"
+	"mygram-final-project/media"
+	"mygram-final-project/mergepatch"
 	"mygram-final-project/models"
+	"mygram-final-project/storage"
 	"mygram-final-project/utils"
 )
 
 // UserController mengelola operasi terkait pengguna
 type UserController struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	DeletionGrace time.Duration // Masa tenggang sebelum akun yang diminta untuk dihapus benar-benar dihapus
+	Store         storage.Store // Penyimpanan berkas avatar
 }
 
 // NewUserController membuat instance baru dari UserController
-func NewUserController(DB *gorm.DB) UserController {
-	return UserController{DB}
+func NewUserController(DB *gorm.DB, deletionGrace time.Duration, store storage.Store) UserController {
+	return UserController{DB, deletionGrace, store}
+}
+
+// GetMe mengambil profil pengguna saat ini beserta email dan pengaturan akunnya. ETag profil dikirim untuk If-Match saat
+// mengubah profil, tetapi If-None-Match tidak didukung karena respons juga memuat daftar foto yang dapat berubah.
+func (uc *UserController) GetMe(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	setETag(ctx, currentUser.Version)
+	uc.respondProfile(ctx, currentUser, currentUser)
+}
+
+// GetUserByUsername mengambil profil publik seorang pengguna. Pengguna yang saling memblokir dengan pengguna saat ini
+// tidak dapat dilihat, dan foto serta media sosial akun privat hanya ditampilkan kepada pengikutnya.
+func (uc *UserController) GetUserByUsername(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var user models.User
+	if err := uc.DB.First(&user, "username = ?", ctx.Param("username")).Error; err != nil ||
+		isBlocked(uc.DB, currentUser.ID, user.ID) || (user.IsDeletionScheduled() && !currentUser.IsModerator()) {
+		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": "Tidak ada user dengan username tersebut."})
+		return
+	}
+
+	uc.respondProfile(ctx, currentUser, user)
 }
 
 // UpdateMe mengupdate informasi pengguna saat ini
 func (uc *UserController) UpdateMe(ctx *gin.Context) {
 	currentUser := ctx.MustGet("currentUser").(models.User)
+	if !checkIfMatch(ctx, currentUser.Version) {
+		return
+	}
 	var payload models.UpdateCurrentUserRequest
 
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
@@ -42,64 +81,441 @@ func (uc *UserController) UpdateMe(ctx *gin.Context) {
 		return
 	}
 
-	// Validasi usia
-	if payload.Age < 8 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Age minimal 8 tahun."})
+	// Validasi tanggal lahir. Tanggal lahir hanya dapat diubah dalam batas yang dijelaskan pada setBirthdate.
+	before, previousAvatar := userAuditFields(currentUser), currentUser.AvatarKey
+	birthdate, message := parseBirthdate(payload.Birthdate)
+	if message == "" {
+		message = setBirthdate(&currentUser, birthdate)
+	}
+	if message != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": message})
 		return
 	}
 
-	// Validasi URL gambar profil
-	if payload.ProfileImageURL != "" && !utils.IsValidURL(payload.ProfileImageURL) {
+	// Validasi URL gambar profil. URL avatar yang diunggah dikirim kembali apa adanya.
+	if payload.ProfileImageURL != "" && payload.ProfileImageURL != currentUser.ProfileImageURL && !utils.IsValidURL(payload.ProfileImageURL) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Format profile image URL tidak valid."})
 		return
 	}
 
-	// Memperbarui informasi pengguna saat ini
+	// Memperbarui informasi pengguna saat ini. URL gambar profil yang baru menggantikan avatar yang diunggah.
 	currentUser.Username = payload.Username
 	currentUser.Email = payload.Email
-	currentUser.Age = payload.Age
-	currentUser.ProfileImageURL = payload.ProfileImageURL
+	if payload.ProfileImageURL != currentUser.ProfileImageURL {
+		currentUser.ProfileImageURL = payload.ProfileImageURL
+		currentUser.AvatarKey = ""
+	}
+	if payload.AllowMentions != nil {
+		currentUser.AllowMentions = *payload.AllowMentions
+	}
+	if payload.IsPrivate != nil {
+		currentUser.IsPrivate = *payload.IsPrivate
+	}
 
-	// Validasi Username yang Unik
-	existingUser := models.User{}
-	if err := uc.DB.Where("username = ?", payload.Username).First(&existingUser).Error; err == nil && existingUser.ID != currentUser.ID {
-		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Username sudah terdaftar."})
+	if message := uc.checkUniqueIdentity(currentUser); message != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": message})
+		return
+	}
+
+	err := uc.saveProfile(ctx, &currentUser, before, previousAvatar)
+	if errors.Is(err, errVersionConflict) {
+		respondVersionConflict(ctx, nil)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengupdate informasi user."})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": currentUserResponse(currentUser)})
+}
+
+// PatchMe memperbarui sebagian informasi dan profil pengguna saat ini dengan JSON Merge Patch. Field yang tidak dikirim tidak
+// diubah, field profil bernilai null dikosongkan, dan pengaturan bernilai null dikembalikan ke nilai bawaannya.
+func (uc *UserController) PatchMe(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	if !checkIfMatch(ctx, currentUser.Version) {
+		return
+	}
+
+	patch, ok := bindMergePatch(ctx)
+	if !ok {
 		return
 	}
 
-	// Validasi Email yang Unik
-	if err := uc.DB.Where("email = ?", payload.Email).First(&existingUser).Error; err == nil && existingUser.ID != currentUser.ID {
-		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Email sudah terdaftar."})
+	before, previousAvatar := userAuditFields(currentUser), currentUser.AvatarKey
+	applyUserPatch(&currentUser, patch)
+	if respondPatchErrors(ctx, patch) {
+		return
+	}
+	if message := uc.checkUniqueIdentity(currentUser); message != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": message})
 		return
 	}
 
-	// Simpan perubahan ke database
-	if err := uc.DB.Save(&currentUser).Error; err != nil {
+	err := uc.saveProfile(ctx, &currentUser, before, previousAvatar)
+	if errors.Is(err, errVersionConflict) {
+		respondVersionConflict(ctx, nil)
+		return
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengupdate informasi user."})
 		return
 	}
 
-	// Siapkan respons sesuai spesifikasi OpenAPI
-	responseData := gin.H{
-		"id":                currentUser.ID,
-		"email":             currentUser.Email,
-		"username":          currentUser.Username,
-		"age":               currentUser.Age,
-		"profile_image_url": currentUser.ProfileImageURL,
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": currentUserResponse(currentUser)})
+}
+
+// UploadAvatar mengunggah avatar pengguna saat ini dari field multipart "avatar". Gambar dipotong menjadi persegi dan
+// disimpan dalam beberapa ukuran, lalu varian terbesar dipakai sebagai profile_image_url.
+func (uc *UserController) UploadAvatar(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	if !checkIfMatch(ctx, currentUser.Version) {
+		return
+	}
+
+	// Batas body sedikit di atas batas berkas untuk menampung header multipart
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, media.MaxAvatarBytes+64<<10)
+	file, err := ctx.FormFile("avatar")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Berkas avatar harus diunggah dengan ukuran maksimal 5 MB."})
+		return
+	}
+	if file.Size > media.MaxAvatarBytes {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"status": "fail", "message": "Ukuran avatar maksimal 5 MB."})
+		return
+	}
+	src, err := file.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Berkas avatar tidak dapat dibaca."})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(src, media.MaxAvatarBytes))
+	src.Close()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": "Berkas avatar tidak dapat dibaca."})
+		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+	base, err := media.SaveAvatar(ctx.Request.Context(), uc.Store, currentUser.ID, data)
+	if errors.Is(err, media.ErrUnsupportedImage) || errors.Is(err, media.ErrImageTooLarge) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"status": "fail", "message": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan avatar."})
+		return
+	}
+
+	before, previousAvatar := userAuditFields(currentUser), currentUser.AvatarKey
+	currentUser.AvatarKey = base
+	currentUser.ProfileImageURL = media.AvatarURL(base, media.AvatarVariants[0].Name)
+	if err := uc.saveProfile(ctx, &currentUser, before, previousAvatar); err != nil {
+		media.DeleteAvatar(ctx.Request.Context(), uc.Store, base)
+		if errors.Is(err, errVersionConflict) {
+			respondVersionConflict(ctx, nil)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan avatar."})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": currentUserResponse(currentUser)})
 }
 
-// DeleteMe menghapus pengguna saat ini
+// DeleteAvatar menghapus avatar dan gambar profil pengguna saat ini
+func (uc *UserController) DeleteAvatar(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+	if !checkIfMatch(ctx, currentUser.Version) {
+		return
+	}
+
+	before, previousAvatar := userAuditFields(currentUser), currentUser.AvatarKey
+	currentUser.AvatarKey = ""
+	currentUser.ProfileImageURL = ""
+	err := uc.saveProfile(ctx, &currentUser, before, previousAvatar)
+	if errors.Is(err, errVersionConflict) {
+		respondVersionConflict(ctx, nil)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghapus avatar."})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": currentUserResponse(currentUser)})
+}
+
+// DeleteMe menjadwalkan penghapusan pengguna saat ini. Akun dihapus permanen beserta seluruh datanya oleh job penghapusan
+// setelah masa tenggang, kecuali pengguna login kembali sebelum masa tenggang berakhir.
 func (uc *UserController) DeleteMe(ctx *gin.Context) {
 	currentUser := ctx.MustGet("currentUser").(models.User)
+	if !checkIfMatch(ctx, currentUser.Version) {
+		return
+	}
 
-	// Hapus pengguna saat ini dari database
-	if err := uc.DB.Delete(&currentUser).Error; err != nil {
+	var deletion models.AccountDeletion
+	err := uc.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if deletion, err = accounts.Schedule(tx, &currentUser, uc.DeletionGrace); err != nil {
+			return err
+		}
+		entry := auditEntry(ctx, &currentUser.ID, models.AuditDeletionScheduled, models.TargetUser, currentUser.ID)
+		entry.Metadata = map[string]interface{}{"scheduled_for": deletion.ScheduledFor}
+		return audit.Record(tx, entry)
+	})
+	if errors.Is(err, accounts.ErrModerationHistory) {
+		ctx.JSON(http.StatusConflict, gin.H{"message": "Akun yang pernah mengambil tindakan moderasi tidak dapat dihapus."})
+		return
+	}
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghapus user."})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
+	// Akhiri sesi saat ini, akun tidak dapat digunakan sampai pengguna login kembali untuk membatalkan penghapusan
+	ctx.SetCookie("access_token", "", -1, "/", "localhost", false, true)
+	ctx.SetCookie("refresh_token", "", -1, "/", "localhost", false, true)
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"status":        "success",
+		"message":       "Akun kamu dijadwalkan untuk dihapus. Login kembali sebelum waktu penghapusan untuk membatalkannya.",
+		"scheduled_for": deletion.ScheduledFor,
+	})
+}
+
+// respondProfile mengirim profil user yang dilihat oleh viewer, lengkap dengan jumlah foto, pengikut, dan yang diikuti,
+// media sosial, serta grid foto dengan opsi paging. Email dan pengaturan akun hanya ditampilkan kepada pemiliknya.
+func (uc *UserController) respondProfile(ctx *gin.Context, viewer models.User, user models.User) {
+	page, limit, offset := parsePagination(ctx)
+	isSelf := viewer.ID == user.ID
+
+	// Jumlah foto tidak menghitung foto yang disembunyikan moderator dan foto berbatasan usia yang tidak boleh dilihat viewer,
+	// kecuali untuk pemiliknya dan moderator, sehingga sama dengan jumlah foto pada grid
+	var photoCount, followerCount, followingCount int64
+	photos := uc.DB.Model(&models.Photo{}).Where("user_id = ?", user.ID)
+	if !isSelf && !viewer.IsModerator() {
+		photos = photos.Where("hidden_at IS NULL")
+		if !canViewRestrictedContent(viewer) {
+			photos = photos.Where("age_restricted = ?", false)
+		}
+	}
+	if err := photos.Count(&photoCount).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+	uc.DB.Model(&models.Follow{}).Where("following_id = ?", user.ID).Count(&followerCount)
+	uc.DB.Model(&models.Follow{}).Where("follower_id = ?", user.ID).Count(&followingCount)
+
+	responseData := gin.H{
+		"id":                user.ID,
+		"username":          user.Username,
+		"profile_image_url": user.ProfileImageURL,
+		"avatar":            media.AvatarURLs(user.AvatarKey),
+		"display_name":      user.DisplayName,
+		"bio":               user.Bio,
+		"website":           user.Website,
+		"pronouns":          user.Pronouns,
+		"location":          user.Location,
+		"is_private":        user.IsPrivate,
+		"created_at":        user.CreatedAt,
+		"photo_count":       photoCount,
+		"follower_count":    followerCount,
+		"following_count":   followingCount,
+	}
+	if isSelf {
+		responseData["email"] = user.Email
+		responseData["birthdate"] = user.BirthdateText()
+		responseData["birthdate_estimated"] = user.BirthdateEstimated
+		responseData["age"] = ageOrNil(user)
+		responseData["allow_mentions"] = user.AllowMentions
+	} else {
+		responseData["followed_by_me"] = isFollowing(uc.DB, viewer.ID, user.ID)
+		responseData["follow_requested"] = hasFollowRequest(uc.DB, viewer.ID, user.ID)
+		responseData["follows_me"] = isFollowing(uc.DB, user.ID, viewer.ID)
+	}
+
+	// Akun privat hanya menampilkan data dasar dan jumlah kepada pengguna yang bukan pengikutnya
+	canView := canViewUserContent(uc.DB, viewer, user)
+	responseData["can_view_content"] = canView
+	socialMediaData := []gin.H{}
+	photoData := []gin.H{}
+	if canView {
+		var socialMedias []models.SocialMedia
+		if err := uc.DB.Where("user_id = ?", user.ID).Order("id").Find(&socialMedias).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+		for _, socialMedia := range socialMedias {
+			socialMediaData = append(socialMediaData, gin.H{
+				"id":               socialMedia.ID,
+				"name":             socialMedia.Name,
+				"social_media_url": socialMedia.SocialMediaURL,
+			})
+		}
+
+		var grid []models.Photo
+		if err := uc.DB.Scopes(visiblePhotos(viewer)).Where("user_id = ?", user.ID).
+			Order("id DESC").Limit(limit).Offset(offset).Find(&grid).Error; err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+			return
+		}
+		for _, photo := range grid {
+			photoData = append(photoData, gin.H{
+				"id":         photo.ID,
+				"title":      photo.Title,
+				"photo_url":  photo.PhotoURL,
+				"like_count": photo.LikeCount,
+				"created_at": photo.CreatedAt,
+			})
+		}
+	}
+	responseData["social_medias"] = socialMediaData
+	responseData["photos"] = photoData
+
+	total := photoCount
+	if !canView {
+		total = 0
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// checkUniqueIdentity memastikan username dan email user belum dipakai pengguna lain, lalu mengembalikan pesan error jika sudah
+func (uc *UserController) checkUniqueIdentity(user models.User) string {
+	existingUser := models.User{}
+	if err := uc.DB.Where("username = ?", user.Username).First(&existingUser).Error; err == nil && existingUser.ID != user.ID {
+		return "Username sudah terdaftar."
+	}
+	if err := uc.DB.Where("email = ?", user.Email).First(&existingUser).Error; err == nil && existingUser.ID != user.ID {
+		return "Email sudah terdaftar."
+	}
+	return ""
+}
+
+// saveProfile menyimpan perubahan user beserta catatan audit perubahan profil, lalu menambahkan ETag versi barunya pada respons.
+// errVersionConflict dikembalikan jika profil sudah diubah permintaan lain. Avatar sebelumnya dihapus dari penyimpanan
+// setelah perubahan tersimpan jika sudah tidak dipakai.
+func (uc *UserController) saveProfile(ctx *gin.Context, user *models.User, before audit.Fields, previousAvatar string) error {
+	err := uc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := saveVersioned(tx, user, &user.Version); err != nil {
+			return err
+		}
+		entry := auditEntry(ctx, &user.ID, models.AuditUserUpdate, models.TargetUser, user.ID)
+		entry.Before, entry.After = before, userAuditFields(*user)
+		return audit.Record(tx, entry)
+	})
+	if err != nil {
+		return err
+	}
+	setETag(ctx, user.Version)
+
+	if previousAvatar != "" && previousAvatar != user.AvatarKey {
+		media.DeleteAvatar(ctx.Request.Context(), uc.Store, previousAvatar)
+	}
+	return nil
+}
+
+// applyUserPatch menerapkan field yang dikirim pada patch ke user. Kesalahan validasi dicatat per field pada patch.
+func applyUserPatch(user *models.User, patch *mergepatch.Patch) {
+	patch.String("username", &user.Username, false)
+	if patch.Has("username") && strings.TrimSpace(user.Username) == "" {
+		patch.Fail("username", "Username harus diisi.")
+	}
+	patch.String("email", &user.Email, false)
+	if patch.Has("email") && !utils.IsValidEmail(user.Email) {
+		patch.Fail("email", "Format email tidak valid.")
+	}
+	var birthdate string
+	patch.String("birthdate", &birthdate, false)
+	if patch.Has("birthdate") && !patch.IsNull("birthdate") {
+		value, message := parseBirthdate(birthdate)
+		if message == "" {
+			message = setBirthdate(user, value)
+		}
+		if message != "" {
+			patch.Fail("birthdate", message)
+		}
+	}
+	patch.Bool("allow_mentions", &user.AllowMentions, true)
+	patch.Bool("is_private", &user.IsPrivate, false)
+
+	// URL gambar profil yang baru menggantikan avatar yang diunggah
+	profileImageURL := user.ProfileImageURL
+	patch.String("profile_image_url", &profileImageURL, true)
+	if profileImageURL != user.ProfileImageURL {
+		if profileImageURL != "" && !utils.IsValidURL(profileImageURL) {
+			patch.Fail("profile_image_url", "Format profile image URL tidak valid.")
+		}
+		user.ProfileImageURL = profileImageURL
+		user.AvatarKey = ""
+	}
+
+	texts := []struct {
+		field string
+		dest  *string
+		name  string
+		max   int
+	}{
+		{"display_name", &user.DisplayName, "Display name", models.MaxDisplayNameLength},
+		{"bio", &user.Bio, "Bio", models.MaxBioLength},
+		{"pronouns", &user.Pronouns, "Pronouns", models.MaxPronounsLength},
+		{"location", &user.Location, "Location", models.MaxLocationLength},
+	}
+	for _, text := range texts {
+		if !patch.Has(text.field) {
+			continue
+		}
+		patch.String(text.field, text.dest, true)
+		*text.dest = strings.TrimSpace(*text.dest)
+		if utf8.RuneCountInString(*text.dest) > text.max {
+			patch.Fail(text.field, fmt.Sprintf("%s maksimal %d karakter.", text.name, text.max))
+		}
+	}
+	if patch.Has("website") {
+		patch.String("website", &user.Website, true)
+		user.Website = strings.TrimSpace(user.Website)
+		if user.Website != "" && !utils.IsValidURL(user.Website) {
+			patch.Fail("website", "Format website tidak valid.")
+		}
+	}
+}
+
+// setBirthdate mengganti tanggal lahir user, atau mengembalikan pesan error jika perubahannya tidak diizinkan.
+// Tanggal lahir menentukan akses ke konten berbatasan usia, sehingga tidak dapat diubah setelah mendaftar. Tanggal lahir
+// yang diperkirakan dari usia lama hanya dapat diganti sekali dengan tanggal yang sesuai usia tersebut, yaitu paling lama
+// satu tahun sebelum perkiraannya. Tanggal yang sama tidak mengubah apa pun.
+func setBirthdate(user *models.User, birthdate time.Time) string {
+	if user.Birthdate != nil && user.Birthdate.Equal(birthdate) {
+		return ""
+	}
+	if user.Birthdate != nil && !user.BirthdateEstimated {
+		return "Birthdate tidak dapat diubah setelah mendaftar."
+	}
+	if user.Birthdate != nil && (birthdate.After(*user.Birthdate) || !birthdate.After(user.Birthdate.AddDate(-1, 0, 0))) {
+		return "Birthdate harus sesuai dengan umur yang diisi saat mendaftar."
+	}
+	user.Birthdate = &birthdate
+	user.BirthdateEstimated = false
+	return ""
+}
+
+// currentUserResponse membuat data respons informasi pengguna saat ini setelah diperbarui
+func currentUserResponse(user models.User) gin.H {
+	return gin.H{
+		"id":                  user.ID,
+		"email":               user.Email,
+		"username":            user.Username,
+		"birthdate":           user.BirthdateText(),
+		"birthdate_estimated": user.BirthdateEstimated,
+		"age":                 ageOrNil(user),
+		"profile_image_url":   user.ProfileImageURL,
+		"avatar":              media.AvatarURLs(user.AvatarKey),
+		"display_name":        user.DisplayName,
+		"bio":                 user.Bio,
+		"website":             user.Website,
+		"pronouns":            user.Pronouns,
+		"location":            user.Location,
+		"allow_mentions":      user.AllowMentions,
+		"is_private":          user.IsPrivate,
+	}
 }
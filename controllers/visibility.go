@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// visiblePhotos membatasi query foto pada foto yang boleh dilihat viewer.
+// Foto yang disembunyikan moderator hanya terlihat oleh pemiliknya dan moderator.
+// Foto milik akun privat hanya terlihat oleh pemiliknya, pengikutnya, dan moderator.
+// Foto berbatasan usia tidak terlihat oleh viewer yang belum cukup umur, kecuali fotonya sendiri.
+func visiblePhotos(viewer models.User) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewer.IsModerator() {
+			return db
+		}
+		if !canViewRestrictedContent(viewer) {
+			db = db.Where("age_restricted = ? OR user_id = ?", false, viewer.ID)
+		}
+		newDB := db.Session(&gorm.Session{NewDB: true})
+		return db.Where("hidden_at IS NULL OR user_id = ?", viewer.ID).
+			Where("user_id = ? OR user_id NOT IN (?) OR user_id IN (?)", viewer.ID,
+				newDB.Model(&models.User{}).Select("id").Where("is_private = ?", true),
+				newDB.Model(&models.Follow{}).Select("following_id").Where("follower_id = ?", viewer.ID))
+	}
+}
+
+// visibleComments membatasi query komentar pada komentar yang boleh dilihat viewer.
+// Komentar yang disembunyikan moderator atau ditahan filter konten hanya terlihat oleh penulisnya dan moderator.
+// Komentar yang disembunyikan pemilik foto juga tetap terlihat oleh pemilik foto tersebut.
+func visibleComments(viewer models.User) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewer.IsModerator() {
+			return db
+		}
+		return db.Where("(hidden_at IS NULL AND status = ? AND (owner_hidden_at IS NULL OR photo_id IN (?))) OR user_id = ?",
+			models.CommentPublished, db.Session(&gorm.Session{NewDB: true}).Model(&models.Photo{}).Select("id").Where("user_id = ?", viewer.ID), viewer.ID)
+	}
+}
+
+// canViewPhoto menentukan apakah viewer boleh melihat sebuah foto dengan aturan yang sama seperti visiblePhotos.
+// Foto kosong (misalnya hasil preload foto yang sudah dihapus) tidak dapat dilihat.
+func canViewPhoto(db *gorm.DB, viewer models.User, photo models.Photo) bool {
+	if photo.ID == 0 {
+		return false
+	}
+	if photo.UserID == viewer.ID || viewer.IsModerator() {
+		return true
+	}
+	if photo.HiddenAt != nil || (photo.AgeRestricted && !canViewRestrictedContent(viewer)) {
+		return false
+	}
+
+	var owner models.User
+	if err := db.Select("id", "is_private").First(&owner, photo.UserID).Error; err != nil {
+		return false
+	}
+	return canViewUserContent(db, viewer, owner)
+}
+
+// canViewComment menentukan apakah viewer boleh melihat sebuah komentar pada foto milik photoOwnerID
+func canViewComment(viewer models.User, comment models.Comment, photoOwnerID int64) bool {
+	if comment.UserID == viewer.ID || viewer.IsModerator() {
+		return true
+	}
+	if comment.HiddenAt != nil || comment.Status == models.CommentPending {
+		return false
+	}
+	return comment.OwnerHiddenAt == nil || photoOwnerID == viewer.ID
+}
+
+// canViewUserContent menentukan apakah viewer boleh melihat foto dan media sosial milik owner.
+// Konten akun privat hanya terlihat oleh pemiliknya, pengikutnya, dan moderator.
+func canViewUserContent(db *gorm.DB, viewer models.User, owner models.User) bool {
+	return !owner.IsPrivate || owner.ID == viewer.ID || viewer.IsModerator() || isFollowing(db, viewer.ID, owner.ID)
+}
+
+// hasFollowRequest memeriksa apakah requesterID sedang menunggu persetujuan untuk mengikuti targetID
+func hasFollowRequest(db *gorm.DB, requesterID int64, targetID int64) bool {
+	var count int64
+	db.Model(&models.FollowRequest{}).Where("requester_id = ? AND target_id = ?", requesterID, targetID).Count(&count)
+	return count > 0
+}
+
+// isFollowing memeriksa apakah followerID mengikuti followingID
+func isFollowing(db *gorm.DB, followerID int64, followingID int64) bool {
+	var count int64
+	db.Model(&models.Follow{}).Where("follower_id = ? AND following_id = ?", followerID, followingID).Count(&count)
+	return count > 0
+}
@@ -0,0 +1,308 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+	"mygram-final-project/utils"
+	"mygram-final-project/webhooks"
+)
+
+// WebhookController adalah kontroler untuk pengelolaan langganan webhook
+type WebhookController struct {
+	DB         *gorm.DB
+	Dispatcher *webhooks.Dispatcher
+}
+
+// NewWebhookController digunakan untuk membuat instance baru dari WebhookController
+func NewWebhookController(DB *gorm.DB, dispatcher *webhooks.Dispatcher) WebhookController {
+	return WebhookController{DB, dispatcher}
+}
+
+// CreateWebhook mendaftarkan langganan webhook baru. Secret hanya ditampilkan sekali pada respons ini.
+func (wc *WebhookController) CreateWebhook(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var payload models.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if message := wc.validateWebhook(ctx.Request.Context(), payload.URL, payload.Events); message != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": message})
+		return
+	}
+
+	// Validasi cakupan langganan
+	if payload.Scope == "" {
+		payload.Scope = models.WebhookScopeUser
+	}
+	if payload.Scope != models.WebhookScopeUser && payload.Scope != models.WebhookScopeApp {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": "Scope harus user atau app."})
+		return
+	}
+	if payload.Scope == models.WebhookScopeApp && currentUser.Role != models.RoleAdmin {
+		ctx.JSON(http.StatusForbidden, gin.H{"message": "Hanya admin yang dapat membuat webhook dengan scope app."})
+		return
+	}
+
+	secret, err := webhooks.GenerateSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	now := time.Now()
+	subscription := models.WebhookSubscription{
+		UserID:    currentUser.ID,
+		URL:       payload.URL,
+		Secret:    secret,
+		Events:    strings.Join(payload.Events, ","),
+		Scope:     payload.Scope,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := wc.DB.Create(&subscription).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := webhookResponse(subscription)
+	responseData["secret"] = subscription.Secret
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": responseData})
+}
+
+// GetWebhooks mengambil daftar langganan webhook milik pengguna saat ini
+func (wc *WebhookController) GetWebhooks(ctx *gin.Context) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var subscriptions []models.WebhookSubscription
+	if err := wc.DB.Where("user_id = ?", currentUser.ID).Order("id").Find(&subscriptions).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	responseData := []gin.H{}
+	for _, subscription := range subscriptions {
+		responseData = append(responseData, webhookResponse(subscription))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+}
+
+// GetWebhookByID mengambil langganan webhook berdasarkan ID
+func (wc *WebhookController) GetWebhookByID(ctx *gin.Context) {
+	subscription, ok := wc.findOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": webhookResponse(subscription)})
+}
+
+// UpdateWebhook memperbarui URL, daftar event, dan status aktif langganan webhook
+func (wc *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	subscription, ok := wc.findOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+
+	var payload models.UpdateWebhookRequest
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	if message := wc.validateWebhook(ctx.Request.Context(), payload.URL, payload.Events); message != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"message": message})
+		return
+	}
+
+	subscription.URL = payload.URL
+	subscription.Events = strings.Join(payload.Events, ",")
+	if payload.Active != nil {
+		subscription.Active = *payload.Active
+	}
+	subscription.UpdatedAt = time.Now()
+
+	if err := wc.DB.Save(&subscription).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": webhookResponse(subscription)})
+}
+
+// DeleteWebhook menghapus langganan webhook beserta riwayat pengirimannya
+func (wc *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	subscription, ok := wc.findOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+
+	if err := wc.DB.Delete(&subscription).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// GetDeliveries mengambil log pengiriman sebuah langganan webhook dengan opsi paging
+func (wc *WebhookController) GetDeliveries(ctx *gin.Context) {
+	subscription, ok := wc.findOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+	page, limit, offset := parsePagination(ctx)
+
+	query := wc.DB.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscription.ID)
+	if status := ctx.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	deliveryIDs := make([]int64, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryIDs = append(deliveryIDs, delivery.ID)
+	}
+	var attempts []models.WebhookDeliveryAttempt
+	if len(deliveryIDs) > 0 {
+		wc.DB.Where("delivery_id IN ?", deliveryIDs).Order("id").Find(&attempts)
+	}
+	attemptsByDelivery := map[int64][]gin.H{}
+	for _, attempt := range attempts {
+		attemptsByDelivery[attempt.DeliveryID] = append(attemptsByDelivery[attempt.DeliveryID], webhookAttemptResponse(attempt))
+	}
+
+	responseData := []gin.H{}
+	for _, delivery := range deliveries {
+		deliveryAttempts := attemptsByDelivery[delivery.ID]
+		if deliveryAttempts == nil {
+			deliveryAttempts = []gin.H{}
+		}
+		responseData = append(responseData, gin.H{
+			"id":              delivery.ID,
+			"event":           delivery.Event,
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+			"created_at":      delivery.CreatedAt,
+			"payload":         delivery.Payload,
+			"attempt_logs":    deliveryAttempts,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData, "pagination": paginationMeta(page, limit, total)})
+}
+
+// PingWebhook mengirim event webhook.ping secara langsung ke penerima dan mengembalikan hasil percobaannya.
+// Isi respons penerima tidak dikembalikan.
+func (wc *WebhookController) PingWebhook(ctx *gin.Context) {
+	subscription, ok := wc.findOwnedWebhook(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := webhooks.CreatePing(wc.DB, subscription)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"message": "Unexpected error"})
+		return
+	}
+
+	// Ping tidak dicoba ulang: hasil percobaan pertama langsung dikembalikan ke pemanggil
+	attempt, err := wc.Dispatcher.Deliver(ctx.Request.Context(), delivery)
+	wc.DB.Model(&models.WebhookDelivery{}).Where("id = ? AND status = ?", delivery.ID, models.WebhookDeliveryPending).
+		UpdateColumn("status", models.WebhookDeliveryFailed)
+
+	responseData := webhookAttemptResponse(attempt)
+	responseData["delivery_id"] = delivery.ID
+	responseData["success"] = err == nil
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": responseData})
+}
+
+// findOwnedWebhook mencari langganan webhook dari parameter webhookId milik pengguna saat ini dan menulis respons error jika tidak ditemukan
+func (wc *WebhookController) findOwnedWebhook(ctx *gin.Context) (models.WebhookSubscription, bool) {
+	currentUser := ctx.MustGet("currentUser").(models.User)
+
+	var subscription models.WebhookSubscription
+	if err := wc.DB.First(&subscription, "id = ? AND user_id = ?", ctx.Param("webhookId"), currentUser.ID).Error; err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "Tidak ada webhook dengan ID tersebut."})
+		return subscription, false
+	}
+	return subscription, true
+}
+
+// validateWebhook memeriksa URL dan daftar event, lalu mengembalikan pesan error atau string kosong jika valid.
+// Host URL harus dapat ditemukan dan hanya mengarah ke alamat publik atau alamat yang diizinkan dispatcher.
+func (wc *WebhookController) validateWebhook(ctx context.Context, url string, events []string) string {
+	if !utils.IsValidURL(url) || !(strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")) {
+		return "Format URL webhook tidak valid."
+	}
+	if err := wc.Dispatcher.Addresses.ValidateURL(ctx, url); err != nil {
+		if errors.Is(err, webhooks.ErrPrivateAddress) {
+			return "URL webhook harus mengarah ke alamat publik."
+		}
+		if errors.Is(err, webhooks.ErrInvalidURL) {
+			return "Format URL webhook tidak valid."
+		}
+		return "Host URL webhook tidak dapat ditemukan."
+	}
+	if len(events) == 0 {
+		return "Events harus diisi."
+	}
+
+	valid := map[string]bool{}
+	for _, event := range models.WebhookEvents {
+		valid[event] = true
+	}
+	for _, event := range events {
+		if !valid[event] {
+			return fmt.Sprintf("Event %q tidak dikenal.", event)
+		}
+	}
+	return ""
+}
+
+// webhookResponse membuat objek JSON untuk langganan webhook tanpa secret
+func webhookResponse(subscription models.WebhookSubscription) gin.H {
+	return gin.H{
+		"id":         subscription.ID,
+		"url":        subscription.URL,
+		"events":     strings.Split(subscription.Events, ","),
+		"scope":      subscription.Scope,
+		"active":     subscription.Active,
+		"created_at": subscription.CreatedAt,
+		"updated_at": subscription.UpdatedAt,
+	}
+}
+
+// webhookAttemptResponse membuat objek JSON untuk satu percobaan pengiriman webhook
+func webhookAttemptResponse(attempt models.WebhookDeliveryAttempt) gin.H {
+	return gin.H{
+		"response_status": attempt.ResponseStatus,
+		"error":           attempt.Error,
+		"duration_ms":     attempt.DurationMs,
+		"attempted_at":    attempt.CreatedAt,
+	}
+}
@@ -0,0 +1,213 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// Nilai default untuk Dispatcher.
+const (
+	DefaultPollInterval = time.Second      // Jeda pemeriksaan outbox
+	DefaultBatchSize    = 50               // Jumlah event yang diproses per pemeriksaan
+	DefaultMaxAttempts  = 20               // Jumlah percobaan sebelum event dianggap dead, sekitar enam jam
+	DefaultBaseBackoff  = 5 * time.Second  // Jeda percobaan ulang pertama, berlipat dua setiap percobaan
+	DefaultMaxBackoff   = 30 * time.Minute // Jeda percobaan ulang maksimal
+	claimLease          = 2 * time.Minute  // Lama event dikunci oleh satu dispatcher
+)
+
+// HandlerFunc memproses satu event. Karena pengiriman bersifat at-least-once, handler harus aman dipanggil ulang untuk event yang sama.
+type HandlerFunc func(ctx context.Context, event models.OutboxEvent) error
+
+// handler adalah handler yang terdaftar beserta event yang ditanganinya
+type handler struct {
+	name   string
+	types  map[string]bool
+	handle HandlerFunc
+}
+
+// Dispatcher membaca event dari outbox dan mengirimnya ke handler yang terdaftar di dalam proses.
+// Handler yang sudah berhasil dicatat per event sehingga percobaan ulang hanya menjalankan handler yang gagal.
+type Dispatcher struct {
+	DB           *gorm.DB
+	Now          func() time.Time
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+
+	mu       sync.RWMutex
+	handlers []handler
+}
+
+// NewDispatcher membuat instance baru dari Dispatcher dengan nilai default.
+func NewDispatcher(DB *gorm.DB) *Dispatcher {
+	return &Dispatcher{
+		DB:           DB,
+		Now:          time.Now,
+		PollInterval: DefaultPollInterval,
+		BatchSize:    DefaultBatchSize,
+		MaxAttempts:  DefaultMaxAttempts,
+		BaseBackoff:  DefaultBaseBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+	}
+}
+
+// Register mendaftarkan handler bernama name untuk event types. Nama handler harus unik dan tidak boleh berubah
+// karena dipakai untuk mencatat handler yang sudah berhasil.
+func (d *Dispatcher) Register(name string, handle HandlerFunc, types ...string) {
+	if strings.Contains(name, ",") {
+		panic(fmt.Sprintf("events: nama handler %q tidak boleh mengandung koma", name))
+	}
+
+	typeSet := map[string]bool{}
+	for _, eventType := range types {
+		typeSet[eventType] = true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, existing := range d.handlers {
+		if existing.name == name {
+			panic(fmt.Sprintf("events: handler %q sudah terdaftar", name))
+		}
+	}
+	d.handlers = append(d.handlers, handler{name: name, types: typeSet, handle: handle})
+}
+
+// Run memproses outbox secara berkala sampai ctx dibatalkan.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.ProcessPending(ctx); err != nil {
+			log.Printf("events: gagal memproses outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPending mengklaim satu batch event yang belum selesai, menjalankan handler-nya, lalu mengembalikan jumlah event yang diproses.
+func (d *Dispatcher) ProcessPending(ctx context.Context) (int, error) {
+	now := d.Now()
+
+	var pending []models.OutboxEvent
+	err := d.DB.Raw(`
+		UPDATE outbox_events SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE processed_at IS NULL AND dead_at IS NULL AND next_attempt_at <= ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(claimLease), now, d.BatchSize).Scan(&pending).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := d.process(ctx, event); err != nil {
+			log.Printf("events: event %d (%s) gagal: %v", event.ID, event.Type, err)
+		}
+	}
+	return len(pending), nil
+}
+
+// process menjalankan handler yang belum berhasil untuk satu event dan menyimpan hasilnya. Event yang masih gagal setelah
+// MaxAttempts percobaan ditandai dead dan tidak dicoba lagi sampai dikembalikan dengan Retry.
+func (d *Dispatcher) process(ctx context.Context, event models.OutboxEvent) error {
+	completed := map[string]bool{}
+	for _, name := range strings.Split(event.CompletedHandlers, ",") {
+		if name != "" {
+			completed[name] = true
+		}
+	}
+
+	d.mu.RLock()
+	handlers := append([]handler(nil), d.handlers...)
+	d.mu.RUnlock()
+
+	var failures []string
+	for _, h := range handlers {
+		if !h.types[event.Type] || completed[h.name] {
+			continue
+		}
+		if err := h.handle(ctx, event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", h.name, err))
+			continue
+		}
+		completed[h.name] = true
+	}
+
+	names := make([]string, 0, len(completed))
+	for name := range completed {
+		names = append(names, name)
+	}
+
+	attempts := event.Attempts + 1
+	updates := map[string]interface{}{
+		"attempts":           attempts,
+		"completed_handlers": strings.Join(names, ","),
+		"last_error":         strings.Join(failures, "; "),
+	}
+	switch {
+	case len(failures) == 0:
+		updates["processed_at"] = d.Now()
+	case attempts >= d.MaxAttempts:
+		updates["dead_at"] = d.Now()
+		log.Printf("events: event %d (%s) berhenti dicoba setelah %d percobaan", event.ID, event.Type, attempts)
+	default:
+		updates["next_attempt_at"] = d.Now().Add(d.backoff(attempts))
+	}
+
+	if err := d.DB.Model(&models.OutboxEvent{}).Where("id = ?", event.ID).UpdateColumns(updates).Error; err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Retry mengembalikan event yang sudah dead ke outbox untuk diproses kembali dengan jatah percobaan baru.
+// Handler yang sudah berhasil tetap tidak dijalankan ulang.
+func Retry(tx *gorm.DB, eventID int64) (bool, error) {
+	result := tx.Model(&models.OutboxEvent{}).
+		Where("id = ? AND dead_at IS NOT NULL", eventID).
+		UpdateColumns(map[string]interface{}{
+			"dead_at":         nil,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// backoff menghitung jeda sebelum percobaan ke-(attempts+1)
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= d.MaxBackoff {
+			return d.MaxBackoff
+		}
+	}
+	return wait
+}
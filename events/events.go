@@ -0,0 +1,60 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// Nama domain event yang ditulis ke outbox.
+const (
+	PhotoCreated    = "photo.created"
+	PhotoUpdated    = "photo.updated"
+	PhotoDeleted    = "photo.deleted"
+	CommentCreated  = "comment.created"
+	CommentDeleted  = "comment.deleted"
+	PhotoLiked      = "photo.liked"
+	CommentLiked    = "comment.liked"
+	UserFollowed    = "user.followed"
+	FollowRequested = "user.follow_requested"
+	FollowApproved  = "user.follow_approved"
+	UserMentioned   = "user.mentioned"
+	UserDeleted     = "user.deleted"
+)
+
+// Event adalah domain event yang akan ditulis ke outbox.
+type Event struct {
+	Type          string                 // Nama event
+	AggregateType string                 // Jenis objek yang berubah (photo, comment, user)
+	AggregateID   int64                  // ID objek yang berubah
+	ActorID       int64                  // ID pengguna yang melakukan perubahan
+	Data          map[string]interface{} // Data event
+}
+
+// Record menulis event ke tabel outbox. Fungsi ini harus dipanggil dengan transaksi yang sama
+// dengan perubahan data sehingga event hanya tersimpan jika perubahan berhasil di-commit.
+func Record(tx *gorm.DB, event Event) error {
+	payload, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return tx.Create(&models.OutboxEvent{
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		ActorID:       event.ActorID,
+		Payload:       string(payload),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}).Error
+}
+
+// Decode membaca payload event ke dalam target.
+func Decode(event models.OutboxEvent, target interface{}) error {
+	return json.Unmarshal([]byte(event.Payload), target)
+}
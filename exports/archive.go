@@ -0,0 +1,285 @@
+package exports
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// archiveReadme menjelaskan isi arsip kepada pengguna
+const archiveReadme = `Arsip ini berisi data pribadi kamu di MyGram dalam format JSON.
+
+profile.json        Data akun
+photos.json         Foto yang kamu unggah, termasuk yang ada di tempat sampah
+comments.json       Komentar yang kamu tulis, termasuk yang ada di tempat sampah
+social_medias.json  Tautan media sosial
+activity.json       Like, pengikut, blokir, bisukan, story, pesan terkirim, laporan, dan preferensi notifikasi
+
+Foto disimpan sebagai URL, sehingga berkas gambarnya tidak disertakan di arsip ini.
+`
+
+// profileData adalah isi profile.json
+type profileData struct {
+	ID              int64     `json:"id"`
+	Username        string    `json:"username"`
+	Email           string    `json:"email"`
+	Birthdate       *string   `json:"birthdate"`
+	Age             *int      `json:"age"`
+	ProfileImageURL string    `json:"profile_image_url"`
+	DisplayName     string    `json:"display_name"`
+	Bio             string    `json:"bio"`
+	Website         string    `json:"website"`
+	Pronouns        string    `json:"pronouns"`
+	Location        string    `json:"location"`
+	Role            string    `json:"role"`
+	AllowMentions   bool      `json:"allow_mentions"`
+	IsPrivate       bool      `json:"is_private"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// photoData adalah satu entri photos.json
+type photoData struct {
+	ID            int64      `json:"id"`
+	Title         string     `json:"title"`
+	Caption       string     `json:"caption"`
+	PhotoURL      string     `json:"photo_url"`
+	CommentPolicy string     `json:"comment_policy"`
+	AgeRestricted bool       `json:"age_restricted"`
+	LikeCount     int64      `json:"like_count"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+}
+
+// commentData adalah satu entri comments.json
+type commentData struct {
+	ID         int64      `json:"id"`
+	PhotoID    int64      `json:"photo_id"`
+	ParentID   *int64     `json:"parent_id"`
+	Message    string     `json:"message"`
+	Status     string     `json:"status"`
+	LikeCount  int64      `json:"like_count"`
+	Tombstoned bool       `json:"tombstoned"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+}
+
+// socialMediaData adalah satu entri social_medias.json
+type socialMediaData struct {
+	ID             int64      `json:"id"`
+	Name           string     `json:"name"`
+	SocialMediaURL string     `json:"social_media_url"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// relationData adalah pengguna lain yang terkait dengan pengguna, misalnya pengikut atau pengguna yang diblokir
+type relationData struct {
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// photoLikeData adalah like pengguna pada foto
+type photoLikeData struct {
+	PhotoID   int64     `json:"photo_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// commentLikeData adalah like pengguna pada komentar
+type commentLikeData struct {
+	CommentID int64     `json:"comment_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// storyData adalah story milik pengguna yang belum dihapus
+type storyData struct {
+	ID        int64     `json:"id"`
+	MediaURL  string    `json:"media_url"`
+	MediaType string    `json:"media_type"`
+	Caption   string    `json:"caption"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// messageData adalah pesan yang dikirim pengguna
+type messageData struct {
+	ID             int64     `json:"id"`
+	ConversationID int64     `json:"conversation_id"`
+	Body           string    `json:"body"`
+	PhotoID        *int64    `json:"photo_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// reportData adalah laporan yang dibuat pengguna
+type reportData struct {
+	ID         int64     `json:"id"`
+	TargetType string    `json:"target_type"`
+	TargetID   int64     `json:"target_id"`
+	Reason     string    `json:"reason"`
+	Details    string    `json:"details"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// notificationPreferenceData adalah preferensi notifikasi pengguna
+type notificationPreferenceData struct {
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+// activityData adalah isi activity.json
+type activityData struct {
+	PhotoLikes              []photoLikeData              `json:"photo_likes"`
+	CommentLikes            []commentLikeData            `json:"comment_likes"`
+	Following               []relationData               `json:"following"`
+	Followers               []relationData               `json:"followers"`
+	FollowRequestsSent      []relationData               `json:"follow_requests_sent"`
+	FollowRequestsReceived  []relationData               `json:"follow_requests_received"`
+	Blocked                 []relationData               `json:"blocked"`
+	Muted                   []relationData               `json:"muted"`
+	Stories                 []storyData                  `json:"stories"`
+	MessagesSent            []messageData                `json:"messages_sent"`
+	Reports                 []reportData                 `json:"reports"`
+	NotificationPreferences []notificationPreferenceData `json:"notification_preferences"`
+}
+
+// writeArchive menulis arsip ZIP berisi data user ke w
+func writeArchive(tx *gorm.DB, w io.Writer, user models.User) error {
+	archive := zip.NewWriter(w)
+
+	readme, err := archive.Create("README.txt")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(readme, archiveReadme); err != nil {
+		return err
+	}
+
+	profile := profileData{
+		ID:              user.ID,
+		Username:        user.Username,
+		Email:           user.Email,
+		Birthdate:       user.BirthdateText(),
+		ProfileImageURL: user.ProfileImageURL,
+		DisplayName:     user.DisplayName,
+		Bio:             user.Bio,
+		Website:         user.Website,
+		Pronouns:        user.Pronouns,
+		Location:        user.Location,
+		Role:            user.Role,
+		AllowMentions:   user.AllowMentions,
+		IsPrivate:       user.IsPrivate,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+	if age, ok := user.AgeAt(time.Now()); ok {
+		profile.Age = &age
+	}
+	if err := writeJSON(archive, "profile.json", profile); err != nil {
+		return err
+	}
+
+	photos := []photoData{}
+	if err := tx.Unscoped().Model(&models.Photo{}).Where("user_id = ?", user.ID).Order("id").Find(&photos).Error; err != nil {
+		return err
+	}
+	if err := writeJSON(archive, "photos.json", photos); err != nil {
+		return err
+	}
+
+	comments := []commentData{}
+	if err := tx.Unscoped().Model(&models.Comment{}).Where("user_id = ?", user.ID).Order("id").Find(&comments).Error; err != nil {
+		return err
+	}
+	if err := writeJSON(archive, "comments.json", comments); err != nil {
+		return err
+	}
+
+	socialMedias := []socialMediaData{}
+	if err := tx.Unscoped().Model(&models.SocialMedia{}).Where("user_id = ?", user.ID).Order("id").Find(&socialMedias).Error; err != nil {
+		return err
+	}
+	if err := writeJSON(archive, "social_medias.json", socialMedias); err != nil {
+		return err
+	}
+
+	activity, err := collectActivity(tx, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(archive, "activity.json", activity); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// collectActivity mengumpulkan aktivitas userID untuk activity.json
+func collectActivity(tx *gorm.DB, userID int64) (activityData, error) {
+	activity := activityData{
+		PhotoLikes:              []photoLikeData{},
+		CommentLikes:            []commentLikeData{},
+		Following:               []relationData{},
+		Followers:               []relationData{},
+		FollowRequestsSent:      []relationData{},
+		FollowRequestsReceived:  []relationData{},
+		Blocked:                 []relationData{},
+		Muted:                   []relationData{},
+		Stories:                 []storyData{},
+		MessagesSent:            []messageData{},
+		Reports:                 []reportData{},
+		NotificationPreferences: []notificationPreferenceData{},
+	}
+	queries := []struct {
+		dest  interface{}
+		query *gorm.DB
+	}{
+		{&activity.PhotoLikes, tx.Model(&models.PhotoLike{}).Where("user_id = ?", userID).Order("id")},
+		{&activity.CommentLikes, tx.Model(&models.CommentLike{}).Where("user_id = ?", userID).Order("id")},
+		{&activity.Following, relations(tx, "follows", "follower_id", "following_id", userID)},
+		{&activity.Followers, relations(tx, "follows", "following_id", "follower_id", userID)},
+		{&activity.FollowRequestsSent, relations(tx, "follow_requests", "requester_id", "target_id", userID)},
+		{&activity.FollowRequestsReceived, relations(tx, "follow_requests", "target_id", "requester_id", userID)},
+		{&activity.Blocked, relations(tx, "blocks", "blocker_id", "blocked_id", userID)},
+		{&activity.Muted, relations(tx, "mutes", "muter_id", "muted_id", userID)},
+		{&activity.Stories, tx.Model(&models.Story{}).Where("user_id = ?", userID).Order("id")},
+		{&activity.MessagesSent, tx.Model(&models.Message{}).Where("sender_id = ?", userID).Order("id")},
+		{&activity.Reports, tx.Model(&models.Report{}).Where("reporter_id = ?", userID).Order("id")},
+		{&activity.NotificationPreferences, tx.Model(&models.NotificationPreference{}).Where("user_id = ?", userID).Order("type")},
+	}
+	for _, q := range queries {
+		if err := q.query.Find(q.dest).Error; err != nil {
+			return activity, err
+		}
+	}
+	return activity, nil
+}
+
+// relations membuat query pengguna lain pada table relasi, dengan ownerColumn berisi userID dan otherColumn berisi pengguna lain
+func relations(tx *gorm.DB, table string, ownerColumn string, otherColumn string, userID int64) *gorm.DB {
+	return tx.Table(table).
+		Select("users.id AS user_id, users.username, "+table+".created_at").
+		Joins("JOIN users ON users.id = "+table+"."+otherColumn).
+		Where(table+"."+ownerColumn+" = ?", userID).
+		Order(table + ".created_at")
+}
+
+// writeJSON menulis v sebagai berkas JSON bernama name di dalam archive
+func writeJSON(archive *zip.Writer, name string, v interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,208 @@
+package exports
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/jobs"
+	"mygram-final-project/models"
+	"mygram-final-project/notifications"
+	"mygram-final-project/storage"
+)
+
+// Jenis job ekspor data pengguna.
+const (
+	JobBuild   = "export.build"    // Membuat arsip ZIP untuk satu permintaan ekspor
+	JobCleanup = "cleanup.exports" // Menghapus arsip yang sudah melewati masa berlaku
+)
+
+// LinkTTL adalah masa berlaku tautan unduhan bertanda tangan. Tautan baru dibuat setiap kali daftar ekspor diminta,
+// tetapi tidak pernah berlaku melewati masa berlaku arsipnya.
+const LinkTTL = time.Hour
+
+// BuildPayload adalah payload job JobBuild.
+type BuildPayload struct {
+	ExportID int64 `json:"export_id"` // ID permintaan ekspor yang diproses
+}
+
+// MinSigningKeyLength adalah panjang minimal kunci penandatanganan tautan unduhan dalam byte.
+const MinSigningKeyLength = 32
+
+// placeholderSigningKey adalah contoh kunci yang pernah ada di app.env dan tidak boleh dipakai.
+const placeholderSigningKey = "ganti-dengan-kunci-rahasia-yang-panjang"
+
+// Error yang dikembalikan NewSigner.
+var (
+	ErrMissingSigningKey = errors.New("kunci penandatanganan tautan ekspor belum diatur")
+	ErrWeakSigningKey    = fmt.Errorf("kunci penandatanganan tautan ekspor harus berupa nilai acak minimal %d byte", MinSigningKeyLength)
+)
+
+// Signer membuat dan memeriksa tanda tangan HMAC-SHA256 untuk tautan unduhan arsip ekspor.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner membuat Signer dengan kunci rahasia key. Kunci contoh dan kunci yang lebih pendek dari MinSigningKeyLength
+// ditolak karena tautan unduhan dapat dipalsukan oleh siapa pun yang mengetahui atau menebak kuncinya.
+func NewSigner(key string) (Signer, error) {
+	if key == "" {
+		return Signer{}, ErrMissingSigningKey
+	}
+	if key == placeholderSigningKey || len(key) < MinSigningKeyLength {
+		return Signer{}, ErrWeakSigningKey
+	}
+	return Signer{key: []byte(key)}, nil
+}
+
+// NewRandomSigner membuat Signer dengan kunci acak yang hanya berlaku selama proses berjalan. Fungsi ini dipakai untuk
+// pengembangan saat EXPORT_SIGNING_KEY kosong: tautan unduhan tidak berlaku lagi setelah server dijalankan ulang
+// dan tidak dapat diperiksa oleh instance server lain.
+func NewRandomSigner() (Signer, error) {
+	key := make([]byte, MinSigningKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return Signer{}, err
+	}
+	return Signer{key: key}, nil
+}
+
+// Sign mengembalikan tanda tangan untuk arsip exportID yang berlaku sampai expires.
+func (s Signer) Sign(exportID int64, expires time.Time) string {
+	return s.sign(exportID, expires.Unix())
+}
+
+// Verify memeriksa tanda tangan arsip exportID dengan batas waktu expires berupa detik Unix.
+func (s Signer) Verify(exportID int64, expires string, signature string) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.sign(exportID, unix)))
+}
+
+// DownloadURL membuat tautan unduhan relatif bertanda tangan untuk arsip exportID yang berlaku sampai expires.
+func (s Signer) DownloadURL(exportID int64, expires time.Time) string {
+	return fmt.Sprintf("/exports/%d/download?expires=%d&signature=%s", exportID, expires.Unix(), s.Sign(exportID, expires))
+}
+
+// sign menghitung HMAC dari ID arsip dan batas waktu tautan
+func (s Signer) sign(exportID int64, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%d:%d", exportID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// FileKey mengembalikan key berkas arsip ekspor pada penyimpanan.
+func FileKey(export models.DataExport) string {
+	return fmt.Sprintf("exports/%d/%d.zip", export.UserID, export.ID)
+}
+
+// Register mendaftarkan job pembuatan arsip ekspor dan jadwal pembersihan arsip kedaluwarsa.
+// Arsip disimpan di store dan berlaku selama ttl setelah selesai dibuat.
+func Register(worker *jobs.Worker, db *gorm.DB, store storage.Store, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = models.DefaultDataExportTTL
+	}
+
+	worker.Handle(JobBuild, func(ctx context.Context, job models.Job) error {
+		var payload BuildPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("payload tidak valid: %w", err)
+		}
+		err := build(ctx, db.WithContext(ctx), store, payload.ExportID, ttl)
+		if err != nil && job.Attempts >= job.MaxAttempts {
+			// Percobaan terakhir gagal, tandai ekspor agar pengguna dapat memintanya kembali
+			db.Model(&models.DataExport{}).Where("id = ? AND status = ?", payload.ExportID, models.DataExportPending).
+				Updates(map[string]interface{}{"status": models.DataExportFailed, "error": err.Error()})
+		}
+		return err
+	})
+	jobs.Register(worker, JobCleanup, func(ctx context.Context, _ struct{}) error {
+		return cleanup(ctx, db.WithContext(ctx), store, time.Now())
+	})
+
+	if err := worker.Schedule("cleanup-exports", "5 * * * *", JobCleanup, struct{}{}); err != nil {
+		panic(err)
+	}
+}
+
+// build membuat arsip untuk permintaan ekspor exportID, menyimpannya, lalu memberi tahu pemiliknya.
+// Permintaan yang sudah diproses atau sudah dihapus diabaikan.
+func build(ctx context.Context, db *gorm.DB, store storage.Store, exportID int64, ttl time.Duration) error {
+	var export models.DataExport
+	err := db.Preload("User").First(&export, exportID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if export.Status != models.DataExportPending {
+		return nil
+	}
+
+	// Arsip ditulis langsung ke penyimpanan sambil dibaca dari snapshot database yang konsisten
+	key := FileKey(export)
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(db.Transaction(func(tx *gorm.DB) error {
+			return writeArchive(tx, writer, export.User)
+		}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}))
+	}()
+	size, err := store.Put(ctx, key, reader)
+	reader.CloseWithError(err)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&export).Where("status = ?", models.DataExportPending).Updates(map[string]interface{}{
+			"status":       models.DataExportReady,
+			"file_key":     key,
+			"size":         size,
+			"error":        "",
+			"completed_at": now,
+			"expires_at":   expiresAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return notifications.NotifySelf(tx, export.User, models.NotificationTypeDataExport, models.TargetDataExport, export.ID)
+	})
+}
+
+// cleanup menghapus berkas arsip yang sudah melewati masa berlaku dan menandai ekspornya sebagai expired
+func cleanup(ctx context.Context, db *gorm.DB, store storage.Store, now time.Time) error {
+	var expired []models.DataExport
+	if err := db.Where("status = ? AND expires_at < ?", models.DataExportReady, now).Find(&expired).Error; err != nil {
+		return err
+	}
+
+	for _, export := range expired {
+		if err := store.Delete(ctx, export.FileKey); err != nil {
+			return err
+		}
+		if err := db.Model(&export).Updates(map[string]interface{}{"status": models.DataExportExpired, "file_key": ""}).Error; err != nil {
+			return err
+		}
+	}
+	log.Printf("exports: %d arsip ekspor kedaluwarsa dihapus", len(expired))
+	return nil
+}
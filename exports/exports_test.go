@@ -0,0 +1,49 @@
+package exports
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSigner(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{"kunci cukup panjang", strings.Repeat("a", MinSigningKeyLength), nil},
+		{"kosong", "", ErrMissingSigningKey},
+		{"terlalu pendek", strings.Repeat("a", MinSigningKeyLength-1), ErrWeakSigningKey},
+		{"kunci contoh lama", placeholderSigningKey, ErrWeakSigningKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSigner(tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewSigner = %v, ingin %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRandomSigner(t *testing.T) {
+	first, err := NewRandomSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewRandomSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expires := time.Unix(1700000000, 0)
+	signature := first.Sign(7, expires)
+	if !first.Verify(7, strconv.FormatInt(expires.Unix(), 10), signature) {
+		t.Error("tanda tangan dari signer yang sama ditolak")
+	}
+	if second.Verify(7, strconv.FormatInt(expires.Unix(), 10), signature) {
+		t.Error("tanda tangan dari kunci acak lain diterima")
+	}
+}
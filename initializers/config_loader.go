@@ -14,7 +14,8 @@ type Config struct {
 	DBPort         string `mapstructure:"POSTGRES_PORT"`
 	ServerPort     string `mapstructure:"PORT"`
 
-	ClientOrigin string `mapstructure:"CLIENT_ORIGIN"`
+	ClientOrigin   string   `mapstructure:"CLIENT_ORIGIN"`
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
 
 	AccessTokenPrivateKey  string        `mapstructure:"ACCESS_TOKEN_PRIVATE_KEY"`
 	AccessTokenPublicKey   string        `mapstructure:"ACCESS_TOKEN_PUBLIC_KEY"`
@@ -24,6 +25,39 @@ type Config struct {
 	RefreshTokenExpiresIn  time.Duration `mapstructure:"REFRESH_TOKEN_EXPIRED_IN"`
 	AccessTokenMaxAge      int           `mapstructure:"ACCESS_TOKEN_MAXAGE"`
 	RefreshTokenMaxAge     int           `mapstructure:"REFRESH_TOKEN_MAXAGE"`
+
+	RunWorker         bool `mapstructure:"RUN_WORKER"`
+	WorkerConcurrency int  `mapstructure:"WORKER_CONCURRENCY"`
+
+	StoryTTL       time.Duration `mapstructure:"STORY_TTL"`
+	TrashRetention time.Duration `mapstructure:"TRASH_RETENTION"`
+
+	AccountDeletionGrace time.Duration `mapstructure:"ACCOUNT_DELETION_GRACE"`
+
+	MinimumAge           int `mapstructure:"MINIMUM_AGE"`
+	RestrictedContentAge int `mapstructure:"RESTRICTED_CONTENT_AGE"`
+
+	ImpersonationTTL time.Duration `mapstructure:"IMPERSONATION_TTL"`
+	PasswordResetTTL time.Duration `mapstructure:"PASSWORD_RESET_TTL"`
+
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+
+	StorageDir       string        `mapstructure:"STORAGE_DIR"`
+	ExportTTL        time.Duration `mapstructure:"EXPORT_TTL"`
+	ExportSigningKey string        `mapstructure:"EXPORT_SIGNING_KEY"`
+
+	ContentFilterBlockedWords   []string      `mapstructure:"CONTENT_FILTER_BLOCKED_WORDS"`
+	ContentFilterReviewWords    []string      `mapstructure:"CONTENT_FILTER_REVIEW_WORDS"`
+	ContentFilterBlockedDomains []string      `mapstructure:"CONTENT_FILTER_BLOCKED_DOMAINS"`
+	ContentFilterMaxLinks       int           `mapstructure:"CONTENT_FILTER_MAX_LINKS"`
+	ContentFilterRepeatWindow   time.Duration `mapstructure:"CONTENT_FILTER_REPEAT_WINDOW"`
+	ContentFilterRepeatLimit    int           `mapstructure:"CONTENT_FILTER_REPEAT_LIMIT"`
+
+	WebhookAllowedHosts []string `mapstructure:"WEBHOOK_ALLOWED_HOSTS"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -10,9 +10,14 @@ import (
 
 var DB *gorm.DB
 
+// BuildDSN menyusun connection string PostgreSQL dari konfigurasi
+func BuildDSN(config *Config) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta", config.DBHost, config.DBUserName, config.DBUserPassword, config.DBName, config.DBPort)
+}
+
 func ConnectDB(config *Config) {
 	var err error
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta", config.DBHost, config.DBUserName, config.DBUserPassword, config.DBName, config.DBPort)
+	dsn := BuildDSN(config)
 
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
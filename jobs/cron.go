@@ -0,0 +1,136 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSpec adalah jadwal cron lima kolom (menit jam tanggal bulan hari) yang sudah diurai
+type cronSpec struct {
+	minute, hour, dom, month, dow []bool
+	domAny, dowAny                bool
+}
+
+// cronAliases berisi singkatan jadwal yang umum dipakai
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// parseCron mengurai jadwal cron lima kolom. Setiap kolom mendukung *, angka, daftar (1,2), rentang (1-5), dan langkah
+// (*/15, 1-10/3, atau 5/15 yang berarti mulai dari 5 sampai nilai maksimal kolom).
+func parseCron(spec string) (*cronSpec, error) {
+	if alias, ok := cronAliases[spec]; ok {
+		spec = alias
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("jadwal cron %q harus terdiri dari 5 kolom", spec)
+	}
+
+	var parsed cronSpec
+	var err error
+	if parsed.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if parsed.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if parsed.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if parsed.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	if parsed.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	// Minggu dapat ditulis sebagai 0 atau 7
+	parsed.dow[0] = parsed.dow[0] || parsed.dow[7]
+	parsed.domAny = fields[2] == "*"
+	parsed.dowAny = fields[4] == "*"
+	return &parsed, nil
+}
+
+// parseCronField mengurai satu kolom cron menjadi himpunan nilai yang diizinkan
+func parseCronField(field string, min int, max int) ([]bool, error) {
+	allowed := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		step, stepped := 1, false
+		if i := strings.Index(part, "/"); i >= 0 {
+			stepped = true
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("langkah cron %q tidak valid", part)
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("nilai cron %q tidak valid", part)
+			}
+			end = start
+			// Seperti cron standar, langkah setelah satu angka berlaku sampai nilai maksimal kolom
+			if stepped {
+				end = max
+			}
+			if len(bounds) == 2 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("nilai cron %q tidak valid", part)
+				}
+			}
+		}
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("nilai cron %q di luar rentang %d-%d", part, min, max)
+		}
+
+		for value := start; value <= end; value += step {
+			allowed[value] = true
+		}
+	}
+	return allowed, nil
+}
+
+// next mengembalikan waktu terjadwal pertama setelah t (dengan presisi menit)
+func (c *cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Batas pencarian lima tahun cukup untuk jadwal valid apa pun, termasuk 29 Februari
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.matches(t) {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+	return limit
+}
+
+// matches memeriksa apakah t sesuai dengan jadwal
+func (c *cronSpec) matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+
+	domMatch := c.dom[t.Day()]
+	dowMatch := c.dow[int(t.Weekday())]
+	// Seperti cron standar: jika tanggal dan hari sama-sama dibatasi, cukup salah satu yang cocok
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowMatch
+	case c.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// values mengembalikan nilai yang diizinkan pada satu kolom cron yang sudah diurai
+func values(allowed []bool) []int {
+	var result []int
+	for value, ok := range allowed {
+		if ok {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func TestParseCronField(t *testing.T) {
+	tests := []struct {
+		field    string
+		min, max int
+		want     []int
+		wantErr  bool
+	}{
+		{"*", 0, 5, []int{0, 1, 2, 3, 4, 5}, false},
+		{"7", 0, 59, []int{7}, false},
+		{"1,3,5", 0, 59, []int{1, 3, 5}, false},
+		{"2-4", 0, 59, []int{2, 3, 4}, false},
+		{"1-3,10", 0, 59, []int{1, 2, 3, 10}, false},
+		{"*/15", 0, 59, []int{0, 15, 30, 45}, false},
+		{"5/15", 0, 59, []int{5, 20, 35, 50}, false},
+		{"1-10/3", 0, 59, []int{1, 4, 7, 10}, false},
+		{"2/10", 1, 31, []int{2, 12, 22}, false},
+		{"0/6", 0, 23, []int{0, 6, 12, 18}, false},
+		{"60/5", 0, 59, nil, true},
+		{"0", 1, 31, nil, true},
+		{"60", 0, 59, nil, true},
+		{"5-2", 0, 59, nil, true},
+		{"a", 0, 59, nil, true},
+		{"1-b", 0, 59, nil, true},
+		{"*/0", 0, 59, nil, true},
+		{"*/x", 0, 59, nil, true},
+		{"", 0, 59, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			allowed, err := parseCronField(tt.field, tt.min, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCronField(%q) error = %v, ingin error %v", tt.field, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := values(allowed); !slices.Equal(got, tt.want) {
+				t.Errorf("parseCronField(%q) = %v, ingin %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"@daily", false},
+		{"@hourly", false},
+		{"30 4 1,15 * 1-5", false},
+		{"5/15 */2 1-10/3 * 7", false},
+		{"* * * *", true},
+		{"* * * * * *", true},
+		{"@yearly", true},
+		{"* 24 * * *", true},
+		{"* * * 13 *", true},
+		{"* * * * 8", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			if _, err := parseCron(tt.spec); (err != nil) != tt.wantErr {
+				t.Errorf("parseCron(%q) error = %v, ingin error %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// 2024-01-01 adalah hari Senin
+	from := time.Date(2024, time.January, 1, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 1, 10, 31, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.January, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, time.January, 7, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2024, time.January, 2, 10, 30, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 1, 10, 45, 0, 0, time.UTC)},
+		{"5/15 * * * *", time.Date(2024, time.January, 1, 10, 35, 0, 0, time.UTC)},
+		{"1-10/3 11 * * *", time.Date(2024, time.January, 1, 11, 1, 0, 0, time.UTC)},
+		{"0 9 * * 5", time.Date(2024, time.January, 5, 9, 0, 0, 0, time.UTC)},
+		// Minggu dapat ditulis sebagai 0 atau 7
+		{"0 0 * * 7", time.Date(2024, time.January, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 6-7", time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC)},
+		// Tanggal dan hari sama-sama dibatasi: cukup salah satu yang cocok
+		{"0 0 15 * 3", time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := parseCron(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := spec.next(from); !got.Equal(tt.want) {
+				t.Errorf("next = %v, ingin %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/models"
+)
+
+// DefaultMaxAttempts adalah batas percobaan job jika tidak ditentukan saat dimasukkan ke antrean.
+const DefaultMaxAttempts = 5
+
+// EnqueueOptions adalah pengaturan opsional saat memasukkan job ke antrean.
+type EnqueueOptions struct {
+	RunAt       time.Time // Waktu paling awal job dijalankan, kosong berarti segera
+	MaxAttempts int       // Batas percobaan, 0 berarti DefaultMaxAttempts
+	UniqueKey   string    // Job dengan kunci yang sama hanya dimasukkan sekali
+}
+
+// Enqueue memasukkan job ke antrean. Jika dipanggil di dalam transaksi, job baru terlihat oleh worker setelah transaksi di-commit.
+// Job dengan UniqueKey yang sudah ada diabaikan tanpa error.
+func Enqueue(tx *gorm.DB, jobType string, payload interface{}, options EnqueueOptions) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	job := models.Job{
+		Type:        jobType,
+		Payload:     string(data),
+		Status:      models.JobPending,
+		MaxAttempts: options.MaxAttempts,
+		RunAt:       options.RunAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+	if options.UniqueKey != "" {
+		job.UniqueKey = &options.UniqueKey
+	}
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&job).Error
+}
+
+// Retry mengembalikan job berstatus dead ke antrean untuk dijalankan kembali dengan jatah percobaan baru.
+func Retry(tx *gorm.DB, jobID int64) (bool, error) {
+	result := tx.Model(&models.Job{}).
+		Where("id = ? AND status = ?", jobID, models.JobDead).
+		UpdateColumns(map[string]interface{}{
+			"status":     models.JobPending,
+			"attempts":   0,
+			"run_at":     time.Now(),
+			"updated_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,265 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// Nilai default untuk Worker.
+const (
+	DefaultConcurrency  = 4                // Jumlah job yang dijalankan bersamaan
+	DefaultPollInterval = time.Second      // Jeda pemeriksaan antrean saat kosong
+	DefaultBaseBackoff  = 10 * time.Second // Jeda percobaan ulang pertama, berlipat dua setiap percobaan
+	DefaultMaxBackoff   = time.Hour        // Jeda percobaan ulang maksimal
+	DefaultLockTimeout  = 15 * time.Minute // Job running yang lebih lama dari ini dianggap ditinggalkan worker yang mati
+	scheduleInterval    = 30 * time.Second // Jeda pemeriksaan jadwal cron
+)
+
+// Handler menjalankan satu job. Job dapat dijalankan lebih dari sekali (misalnya setelah worker mati), sehingga handler harus aman dipanggil ulang.
+type Handler func(ctx context.Context, job models.Job) error
+
+// schedule adalah job berulang yang dimasukkan ke antrean sesuai jadwal cron
+type schedule struct {
+	name    string
+	spec    *cronSpec
+	jobType string
+	payload interface{}
+	next    time.Time
+}
+
+// Worker mengambil job dari antrean dan menjalankannya dengan handler yang terdaftar.
+// Beberapa worker dapat berjalan bersamaan di proses atau mesin berbeda karena job diklaim dengan FOR UPDATE SKIP LOCKED.
+type Worker struct {
+	DB           *gorm.DB
+	ID           string
+	Now          func() time.Time
+	Concurrency  int
+	PollInterval time.Duration
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	LockTimeout  time.Duration
+
+	mu        sync.RWMutex
+	handlers  map[string]Handler
+	schedules []*schedule
+}
+
+// NewWorker membuat instance baru dari Worker dengan nilai default.
+func NewWorker(DB *gorm.DB) *Worker {
+	hostname, _ := os.Hostname()
+	return &Worker{
+		DB:           DB,
+		ID:           fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		Now:          time.Now,
+		Concurrency:  DefaultConcurrency,
+		PollInterval: DefaultPollInterval,
+		BaseBackoff:  DefaultBaseBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		LockTimeout:  DefaultLockTimeout,
+		handlers:     map[string]Handler{},
+	}
+}
+
+// Handle mendaftarkan handler untuk jenis job jobType.
+func (w *Worker) Handle(jobType string, handle Handler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, exists := w.handlers[jobType]; exists {
+		panic(fmt.Sprintf("jobs: handler untuk %q sudah terdaftar", jobType))
+	}
+	w.handlers[jobType] = handle
+}
+
+// Register mendaftarkan handler bertipe untuk jobType. Payload job diurai ke T sebelum handler dipanggil.
+func Register[T any](w *Worker, jobType string, handle func(ctx context.Context, payload T) error) {
+	w.Handle(jobType, func(ctx context.Context, job models.Job) error {
+		var payload T
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("payload tidak valid: %w", err)
+		}
+		return handle(ctx, payload)
+	})
+}
+
+// Schedule mendaftarkan job berulang bernama name yang dimasukkan ke antrean sesuai jadwal cron spec
+// (misalnya "*/5 * * * *" atau "@daily", dalam zona waktu lokal). Setiap jadwal hanya dimasukkan sekali
+// walaupun ada beberapa worker yang berjalan.
+func (w *Worker) Schedule(name string, spec string, jobType string, payload interface{}) error {
+	parsed, err := parseCron(spec)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.schedules = append(w.schedules, &schedule{
+		name:    name,
+		spec:    parsed,
+		jobType: jobType,
+		payload: payload,
+		next:    parsed.next(w.Now()),
+	})
+	return nil
+}
+
+// Run menjalankan worker sampai ctx dibatalkan, lalu menunggu job yang sedang berjalan selesai.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work(ctx)
+		}()
+	}
+
+	w.runScheduler(ctx)
+	wg.Wait()
+}
+
+// work mengambil dan menjalankan job satu per satu sampai ctx dibatalkan
+func (w *Worker) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.ProcessNext(ctx)
+		if err != nil {
+			log.Printf("jobs: gagal memproses antrean: %v", err)
+		}
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+// runScheduler memasukkan job berulang yang sudah jatuh tempo ke antrean sampai ctx dibatalkan
+func (w *Worker) runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		w.enqueueScheduled()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enqueueScheduled memasukkan setiap jadwal yang sudah jatuh tempo. UniqueKey mencegah job ganda dari worker lain.
+func (w *Worker) enqueueScheduled() {
+	now := w.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, s := range w.schedules {
+		if s.next.After(now) {
+			continue
+		}
+		options := EnqueueOptions{RunAt: s.next, UniqueKey: fmt.Sprintf("cron:%s:%d", s.name, s.next.Unix())}
+		if err := Enqueue(w.DB, s.jobType, s.payload, options); err != nil {
+			log.Printf("jobs: gagal menjadwalkan %s: %v", s.name, err)
+			continue
+		}
+		s.next = s.spec.next(now)
+	}
+}
+
+// ProcessNext mengklaim dan menjalankan satu job yang siap dijalankan. Nilai kembalian false berarti antrean kosong.
+func (w *Worker) ProcessNext(ctx context.Context) (bool, error) {
+	now := w.Now()
+
+	var claimed []models.Job
+	err := w.DB.Raw(`
+		UPDATE jobs SET status = ?, attempts = attempts + 1, locked_at = ?, locked_by = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE (status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?)
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		models.JobRunning, now, w.ID, now,
+		models.JobPending, now, models.JobRunning, now.Add(-w.LockTimeout)).Scan(&claimed).Error
+	if err != nil || len(claimed) == 0 {
+		return false, err
+	}
+
+	job := claimed[0]
+	return true, w.finish(job, w.run(ctx, job))
+}
+
+// run menjalankan handler job dan mengubah panic menjadi error
+func (w *Worker) run(ctx context.Context, job models.Job) (err error) {
+	w.mu.RLock()
+	handle, ok := w.handlers[job.Type]
+	w.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("tidak ada handler untuk job %q", job.Type)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handle(ctx, job)
+}
+
+// finish menyimpan hasil job: sukses, dijadwalkan ulang dengan backoff, atau dead jika percobaan habis
+func (w *Worker) finish(job models.Job, runErr error) error {
+	now := w.Now()
+	updates := map[string]interface{}{
+		"locked_at":  nil,
+		"locked_by":  "",
+		"updated_at": now,
+	}
+
+	switch {
+	case runErr == nil:
+		updates["status"] = models.JobSucceeded
+		updates["last_error"] = ""
+		updates["completed_at"] = now
+	case job.Attempts >= job.MaxAttempts:
+		log.Printf("jobs: job %d (%s) dead setelah %d percobaan: %v", job.ID, job.Type, job.Attempts, runErr)
+		updates["status"] = models.JobDead
+		updates["last_error"] = runErr.Error()
+		updates["completed_at"] = now
+	default:
+		log.Printf("jobs: job %d (%s) gagal, dicoba ulang: %v", job.ID, job.Type, runErr)
+		updates["status"] = models.JobPending
+		updates["last_error"] = runErr.Error()
+		updates["run_at"] = now.Add(w.backoff(job.Attempts))
+	}
+
+	return w.DB.Model(&models.Job{}).
+		Where("id = ? AND locked_by = ?", job.ID, w.ID).
+		UpdateColumns(updates).Error
+}
+
+// backoff menghitung jeda sebelum percobaan ke-(attempts+1)
+func (w *Worker) backoff(attempts int) time.Duration {
+	wait := w.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= w.MaxBackoff {
+			return w.MaxBackoff
+		}
+	}
+	return wait
+}
@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkerBackoff(t *testing.T) {
+	w := NewWorker(nil)
+	w.BaseBackoff = 10 * time.Second
+	w.MaxBackoff = time.Minute
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, time.Minute},
+		{50, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := w.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, ingin %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message adalah email teks biasa yang dikirim ke satu penerima.
+type Message struct {
+	To      string // Alamat email penerima
+	Subject string // Subjek email
+	Body    string // Isi email
+}
+
+// Sender mengirim email ke pengguna.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// SMTP mengirim email melalui server SMTP. Autentikasi PLAIN dipakai jika Username diisi.
+type SMTP struct {
+	Host     string // Host server SMTP
+	Port     int    // Port server SMTP
+	Username string // Username autentikasi SMTP, kosong berarti tanpa autentikasi
+	Password string // Password autentikasi SMTP
+	From     string // Alamat pengirim
+}
+
+// Log menulis email ke log server alih-alih mengirimnya. Hanya untuk pengembangan saat SMTP belum diatur.
+type Log struct{}
+
+// New membuat Sender dari konfigurasi. Jika host kosong, email hanya ditulis ke log.
+func New(host string, port int, username string, password string, from string) Sender {
+	if host == "" {
+		log.Println("mail: SMTP_HOST belum diatur, email hanya ditulis ke log")
+		return Log{}
+	}
+	if port <= 0 {
+		port = 587
+	}
+	return SMTP{Host: host, Port: port, Username: username, Password: password, From: from}
+}
+
+// Send mengirim message melalui server SMTP
+func (s SMTP) Send(ctx context.Context, message Message) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(net.JoinHostPort(s.Host, strconv.Itoa(s.Port)), auth, s.From, []string{message.To}, s.format(message))
+}
+
+// format menyusun header dan isi email. Baris baru pada header dibuang agar header tambahan tidak dapat disisipkan.
+func (s SMTP) format(message Message) []byte {
+	header := strings.NewReplacer("\r", "", "\n", "")
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(s.From))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(message.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", header.Replace(message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// Send menulis message ke log
+func (Log) Send(_ context.Context, message Message) error {
+	log.Printf("mail: email untuk %s\nSubject: %s\n\n%s", message.To, message.Subject, message.Body)
+	return nil
+}
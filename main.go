@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
+	"mygram-final-project/background"
+	"mygram-final-project/contentfilter"
 	"mygram-final-project/controllers"
+	"mygram-final-project/exports"
 	"mygram-final-project/initializers"
+	"mygram-final-project/mail"
+	"mygram-final-project/middleware"
+	"mygram-final-project/models"
+	"mygram-final-project/realtime"
 	"mygram-final-project/routes"
+	"mygram-final-project/storage"
 )
 
 var (
@@ -27,6 +37,51 @@ var (
 
 	SocialMediaController      controllers.SocialMediaController
 	SocialMediaRouteController routes.SocialMediaRouteController
+
+	LikeController      controllers.LikeController
+	LikeRouteController routes.LikeRouteController
+
+	FollowController      controllers.FollowController
+	FollowRouteController routes.FollowRouteController
+
+	BlockController      controllers.BlockController
+	BlockRouteController routes.BlockRouteController
+
+	ModerationController      controllers.ModerationController
+	ModerationRouteController routes.ModerationRouteController
+
+	NotificationController      controllers.NotificationController
+	NotificationRouteController routes.NotificationRouteController
+
+	ConversationController      controllers.ConversationController
+	ConversationRouteController routes.ConversationRouteController
+
+	StoryController      controllers.StoryController
+	StoryRouteController routes.StoryRouteController
+
+	TrashController      controllers.TrashController
+	TrashRouteController routes.TrashRouteController
+
+	Hub                   *realtime.Hub
+	StreamController      controllers.StreamController
+	StreamRouteController routes.StreamRouteController
+
+	ExportController      controllers.ExportController
+	ExportRouteController routes.ExportRouteController
+
+	MediaController      controllers.MediaController
+	MediaRouteController routes.MediaRouteController
+
+	AuditController      controllers.AuditController
+	AuditRouteController routes.AuditRouteController
+
+	AdminController      controllers.AdminController
+	AdminRouteController routes.AdminRouteController
+
+	Background *background.Processes
+
+	WebhookController      controllers.WebhookController
+	WebhookRouteController routes.WebhookRouteController
 )
 
 func init() {
@@ -37,22 +92,108 @@ func init() {
 
 	initializers.ConnectDB(&config)
 
+	// Penyimpanan berkas untuk avatar dan arsip ekspor data
+	store, err := storage.NewLocal(config.StorageDir)
+	if err != nil {
+		log.Fatal("Could not initialize storage!", err)
+	}
+
+	// Batas usia pendaftaran dan batas usia foto yang ditandai berbatasan usia
+	controllers.SetAgePolicy(models.AgePolicy{MinimumAge: config.MinimumAge, RestrictedContentAge: config.RestrictedContentAge})
+
 	AuthController = controllers.NewAuthController(initializers.DB)
 	AuthRouteController = routes.NewAuthRouteController(AuthController, UserController)
 
-	UserController = controllers.NewUserController(initializers.DB)
+	UserController = controllers.NewUserController(initializers.DB, config.AccountDeletionGrace, store)
 	UserRouteController = routes.NewRouteUserController(UserController)
 
 	PhotoController = controllers.NewPhotoController(initializers.DB)
 	PhotoRouteController = routes.NewRoutePhotoController(PhotoController)
 
-	CommentController = controllers.NewCommentController(initializers.DB)
+	// Pipeline filter konten untuk komentar baru dan komentar yang diedit
+	commentFilter := contentfilter.NewPipeline(
+		contentfilter.WordListFilter{Blocked: config.ContentFilterBlockedWords, Review: config.ContentFilterReviewWords},
+		contentfilter.SpamFilter{MaxLinks: config.ContentFilterMaxLinks, BlockedDomains: config.ContentFilterBlockedDomains},
+		contentfilter.RepeatedMessageFilter{DB: initializers.DB, Window: config.ContentFilterRepeatWindow, Limit: config.ContentFilterRepeatLimit},
+	)
+	CommentController = controllers.NewCommentController(initializers.DB, commentFilter)
 	CommentRouteController = routes.NewRouteCommentController(CommentController)
 
 	SocialMediaController = controllers.NewSocialMediaController(initializers.DB)
 	SocialMediaRouteController = routes.NewRouteSocialMediaController(SocialMediaController)
 
+	LikeController = controllers.NewLikeController(initializers.DB)
+	LikeRouteController = routes.NewRouteLikeController(LikeController)
+
+	FollowController = controllers.NewFollowController(initializers.DB)
+	FollowRouteController = routes.NewRouteFollowController(FollowController)
+
+	BlockController = controllers.NewBlockController(initializers.DB)
+	BlockRouteController = routes.NewRouteBlockController(BlockController)
+
+	ModerationController = controllers.NewModerationController(initializers.DB)
+	ModerationRouteController = routes.NewRouteModerationController(ModerationController)
+
+	NotificationController = controllers.NewNotificationController(initializers.DB)
+	NotificationRouteController = routes.NewRouteNotificationController(NotificationController)
+
+	ConversationController = controllers.NewConversationController(initializers.DB)
+	ConversationRouteController = routes.NewRouteConversationController(ConversationController)
+
+	TrashController = controllers.NewTrashController(initializers.DB, config.TrashRetention)
+	TrashRouteController = routes.NewRouteTrashController(TrashController)
+
+	StoryController = controllers.NewStoryController(initializers.DB, config.StoryTTL)
+	StoryRouteController = routes.NewRouteStoryController(StoryController)
+
+	Hub = realtime.NewHub()
+	StreamController = controllers.NewStreamController(initializers.DB, Hub)
+	StreamRouteController = routes.NewRouteStreamController(StreamController)
+
+	// Penanda tangan tautan unduhan arsip ekspor data. Tanpa EXPORT_SIGNING_KEY dipakai kunci acak untuk pengembangan.
+	exportSigner, err := exports.NewSigner(config.ExportSigningKey)
+	if errors.Is(err, exports.ErrMissingSigningKey) {
+		log.Println("WARNING: EXPORT_SIGNING_KEY is empty, using a random key for this process only. " +
+			"Export download links stop working after a restart and on other instances. Set EXPORT_SIGNING_KEY in production.")
+		exportSigner, err = exports.NewRandomSigner()
+	}
+	if err != nil {
+		log.Fatal("Could not initialize export signer!", err)
+	}
+	ExportController = controllers.NewExportController(initializers.DB, store, exportSigner)
+	ExportRouteController = routes.NewRouteExportController(ExportController)
+
+	MediaController = controllers.NewMediaController(store)
+	MediaRouteController = routes.NewRouteMediaController(MediaController)
+
+	AuditController = controllers.NewAuditController(initializers.DB)
+	AuditRouteController = routes.NewRouteAuditController(AuditController)
+
+	AdminController = controllers.NewAdminController(initializers.DB, config.ImpersonationTTL, config.PasswordResetTTL)
+	AdminRouteController = routes.NewRouteAdminController(AdminController)
+
+	// Dispatcher domain event, pengiriman webhook, dan antrean job beserta handler-nya
+	Background = background.New(initializers.DB, background.Options{
+		Concurrency:    config.WorkerConcurrency,
+		TrashRetention: config.TrashRetention,
+		Storage:        store,
+		ExportTTL:      config.ExportTTL,
+		Mailer:         mail.New(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.MailFrom),
+	})
+
+	// Alamat non-publik yang boleh menerima webhook, hanya untuk pengembangan
+	if err := Background.Webhooks.AllowHosts(config.WebhookAllowedHosts); err != nil {
+		log.Fatal("Invalid WEBHOOK_ALLOWED_HOSTS", err)
+	}
+	WebhookController = controllers.NewWebhookController(initializers.DB, Background.Webhooks)
+	WebhookRouteController = routes.NewRouteWebhookController(WebhookController)
+
 	server = gin.Default()
+
+	// IP klien dicatat di audit log, sehingga header X-Forwarded-For hanya dipercaya dari proxy yang terdaftar
+	if err := server.SetTrustedProxies(config.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES", err)
+	}
 }
 
 func main() {
@@ -65,6 +206,7 @@ func main() {
 	corsConfig.AllowOrigins = []string{"http://localhost:8080", config.ClientOrigin}
 	corsConfig.AllowCredentials = true
 
+	server.Use(middleware.RequestID())
 	server.Use(cors.New(corsConfig))
 
 	AuthRouteController.AuthRoute(&server.RouterGroup)
@@ -72,5 +214,28 @@ func main() {
 	PhotoRouteController.PhotoRoute(&server.RouterGroup)
 	CommentRouteController.CommentRoute(&server.RouterGroup)
 	SocialMediaRouteController.SocialMediaRoute(&server.RouterGroup)
+	LikeRouteController.LikeRoute(&server.RouterGroup)
+	FollowRouteController.FollowRoute(&server.RouterGroup)
+	BlockRouteController.BlockRoute(&server.RouterGroup)
+	ModerationRouteController.ModerationRoute(&server.RouterGroup)
+	NotificationRouteController.NotificationRoute(&server.RouterGroup)
+	ConversationRouteController.ConversationRoute(&server.RouterGroup)
+	StoryRouteController.StoryRoute(&server.RouterGroup)
+	TrashRouteController.TrashRoute(&server.RouterGroup)
+	ExportRouteController.ExportRoute(&server.RouterGroup)
+	MediaRouteController.MediaRoute(&server.RouterGroup)
+	AuditRouteController.AuditRoute(&server.RouterGroup)
+	AdminRouteController.AdminRoute(&server.RouterGroup)
+	StreamRouteController.StreamRoute(&server.RouterGroup)
+	WebhookRouteController.WebhookRoute(&server.RouterGroup)
+
+	// Terima event real-time dari semua instance server melalui PostgreSQL LISTEN/NOTIFY
+	go realtime.Listen(context.Background(), initializers.BuildDSN(&config), Hub)
+
+	// Jalankan proses latar belakang di dalam server, kecuali dijalankan terpisah dengan perintah worker
+	if config.RunWorker {
+		go Background.Run(context.Background())
+	}
+
 	log.Fatal(server.Run(":" + config.ServerPort))
 }
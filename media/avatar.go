@@ -0,0 +1,111 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"mygram-final-project/storage"
+)
+
+// MaxAvatarBytes adalah ukuran maksimal berkas avatar yang diunggah
+const MaxAvatarBytes = 5 << 20
+
+// avatarPrefix adalah awalan key avatar di penyimpanan. Hanya berkas dengan awalan ini yang dapat diakses publik.
+const avatarPrefix = "avatars/"
+
+// Variant adalah ukuran gambar turunan yang dibuat dari setiap unggahan
+type Variant struct {
+	Name string // Nama varian pada key dan respons
+	Size int    // Panjang sisi dalam piksel
+}
+
+// AvatarVariants adalah varian avatar yang dibuat dari setiap unggahan, dari yang terbesar
+var AvatarVariants = []Variant{
+	{Name: "large", Size: 512},
+	{Name: "small", Size: 128},
+}
+
+// SaveAvatar membuat seluruh varian avatar dari data lalu menyimpannya dengan key dasar baru milik userID.
+// Gambar dipotong sekali, lalu setiap varian dibuat dari potongan tersebut.
+// Key dasar dikembalikan untuk disimpan pada pengguna. Jika salah satu varian gagal disimpan, varian yang sudah tersimpan dihapus.
+func SaveAvatar(ctx context.Context, store storage.Store, userID int64, data []byte) (string, error) {
+	img, err := Decode(data)
+	if err != nil {
+		return "", err
+	}
+	square := CropSquare(img)
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	base := fmt.Sprintf("%s%d/%s", avatarPrefix, userID, hex.EncodeToString(buf))
+
+	for _, variant := range AvatarVariants {
+		var encoded bytes.Buffer
+		if err := EncodeJPEG(&encoded, Resize(square, variant.Size)); err != nil {
+			DeleteAvatar(ctx, store, base)
+			return "", err
+		}
+		if _, err := store.Put(ctx, AvatarKey(base, variant.Name), &encoded); err != nil {
+			DeleteAvatar(ctx, store, base)
+			return "", err
+		}
+	}
+	return base, nil
+}
+
+// DeleteAvatar menghapus seluruh varian avatar dengan key dasar base. Kegagalan hanya dicatat karena berkas yatim tidak
+// memengaruhi pengguna.
+func DeleteAvatar(ctx context.Context, store storage.Store, base string) {
+	for _, key := range AvatarKeys(base) {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("media: gagal menghapus avatar %s: %v", key, err)
+		}
+	}
+}
+
+// AvatarKey mengembalikan key berkas varian avatar
+func AvatarKey(base string, variant string) string {
+	return base + "_" + variant + ".jpg"
+}
+
+// AvatarKeys mengembalikan key seluruh varian avatar dengan key dasar base, atau nil jika base kosong
+func AvatarKeys(base string) []string {
+	if base == "" {
+		return nil
+	}
+	keys := make([]string, 0, len(AvatarVariants))
+	for _, variant := range AvatarVariants {
+		keys = append(keys, AvatarKey(base, variant.Name))
+	}
+	return keys
+}
+
+// AvatarURL mengembalikan URL publik varian avatar
+func AvatarURL(base string, variant string) string {
+	return "/media/" + AvatarKey(base, variant)
+}
+
+// AvatarURLs mengembalikan URL publik seluruh varian avatar per nama varian, atau nil jika base kosong
+func AvatarURLs(base string) map[string]string {
+	if base == "" {
+		return nil
+	}
+	urls := map[string]string{}
+	for _, variant := range AvatarVariants {
+		urls[variant.Name] = AvatarURL(base, variant.Name)
+	}
+	return urls
+}
+
+// IsPublicKey menentukan apakah berkas key boleh diakses tanpa login. Berkas lain, misalnya arsip ekspor data,
+// hanya dapat diakses melalui endpoint masing-masing.
+func IsPublicKey(key string) bool {
+	return strings.HasPrefix(key, avatarPrefix) && !strings.Contains(key, "..")
+}
@@ -0,0 +1,115 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // Mendaftarkan decoder GIF
+	"image/jpeg"
+	_ "image/png" // Mendaftarkan decoder PNG
+	"io"
+	"net/http"
+)
+
+// MaxPixels adalah jumlah piksel maksimal gambar yang diterima, untuk mencegah gambar kecil yang membengkak saat didekode.
+// Avatar terbesar hanya 512 piksel, sehingga batasnya cukup untuk foto kamera ponsel biasa (sekitar 12 megapiksel)
+// dengan memori dekode paling banyak sekitar 64 MB.
+const MaxPixels = 4096 * 4096
+
+// ErrUnsupportedImage dikembalikan saat berkas bukan gambar JPEG, PNG, atau GIF.
+var ErrUnsupportedImage = errors.New("format gambar harus JPEG, PNG, atau GIF")
+
+// ErrImageTooLarge dikembalikan saat resolusi gambar melebihi MaxPixels.
+var ErrImageTooLarge = errors.New("resolusi gambar terlalu besar")
+
+// supportedTypes adalah jenis konten gambar yang dapat didekode
+var supportedTypes = map[string]bool{"image/jpeg": true, "image/png": true, "image/gif": true}
+
+// Decode membaca gambar dari data setelah memeriksa jenis konten dan resolusinya
+func Decode(data []byte) (image.Image, error) {
+	if !supportedTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedImage
+	}
+
+	// Resolusi diperiksa dari header sebelum seluruh gambar didekode
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	return img, nil
+}
+
+// CropSquare memotong bagian tengah img menjadi persegi. Piksel transparan diberi latar putih karena hasilnya disimpan
+// sebagai JPEG. Hasilnya dapat diubah ukurannya berkali-kali dengan Resize tanpa memotong ulang.
+func CropSquare(img image.Image) *image.RGBA {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	crop := image.Rect(0, 0, side, side)
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	src := image.NewRGBA(crop)
+	draw.Draw(src, crop, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, crop, img, origin, draw.Over)
+	return src
+}
+
+// EncodeJPEG menulis img ke w sebagai JPEG
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+}
+
+// Resize mengubah ukuran gambar persegi src menjadi size x size. Setiap piksel tujuan adalah rata-rata piksel sumber
+// yang tercakup, sehingga hasil pengecilan tetap halus tanpa aliasing.
+func Resize(src *image.RGBA, size int) *image.RGBA {
+	side := src.Bounds().Dx()
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for dy := 0; dy < size; dy++ {
+		y0, y1 := span(dy, side, size)
+		for dx := 0; dx < size; dx++ {
+			x0, x1 := span(dx, side, size)
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+			offset := dy*dst.Stride + dx*4
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// span mengembalikan rentang piksel sumber [start, end) untuk piksel tujuan ke-i. Rentang selalu berisi minimal satu piksel,
+// sehingga gambar yang lebih kecil dari ukuran tujuan diperbesar dengan mengulang piksel.
+func span(i int, side int, size int) (int, int) {
+	start := i * side / size
+	end := (i + 1) * side / size
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
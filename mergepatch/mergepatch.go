@@ -0,0 +1,136 @@
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+)
+
+// ContentType adalah jenis konten dokumen JSON Merge Patch (RFC 7396).
+const ContentType = "application/merge-patch+json"
+
+// ErrNotObject dikembalikan saat dokumen patch bukan objek JSON. Resource yang didukung hanya memiliki field datar,
+// sehingga patch yang mengganti seluruh resource tidak diterima.
+var ErrNotObject = errors.New("patch harus berupa objek JSON")
+
+// Patch adalah dokumen JSON Merge Patch yang sedang diterapkan ke sebuah resource. Field yang tidak ada di dokumen
+// tidak diubah, sedangkan field bernilai null dihapus atau dikembalikan ke nilai bawaannya.
+// Kesalahan per field dikumpulkan dan dapat dibaca dengan Errors setelah seluruh field diterapkan.
+type Patch struct {
+	fields map[string]json.RawMessage
+	used   map[string]bool
+	errors map[string]string
+}
+
+// IsSupportedContentType menentukan apakah header Content-Type dapat dibaca sebagai merge patch.
+// application/json juga diterima karena isinya sama.
+func IsSupportedContentType(header string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	return err == nil && (mediaType == ContentType || mediaType == "application/json")
+}
+
+// Parse membaca dokumen patch dari r
+func Parse(r io.Reader) (*Patch, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return nil, ErrNotObject
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return &Patch{fields: fields, used: map[string]bool{}, errors: map[string]string{}}, nil
+}
+
+// Has menentukan apakah field ada di dokumen, termasuk yang bernilai null
+func (p *Patch) Has(field string) bool {
+	p.used[field] = true
+	_, ok := p.fields[field]
+	return ok
+}
+
+// IsNull menentukan apakah field dikirim dengan nilai null
+func (p *Patch) IsNull(field string) bool {
+	p.used[field] = true
+	value, ok := p.fields[field]
+	return ok && isNull(value)
+}
+
+// String menerapkan field string ke dest. Null mengosongkan dest jika nullable, selain itu dicatat sebagai kesalahan.
+func (p *Patch) String(field string, dest *string, nullable bool) {
+	var value string
+	if p.decode(field, &value, "string", nullable) {
+		*dest = value
+	}
+}
+
+// Bool menerapkan field boolean ke dest. Null mengembalikan dest ke nilai bawaan def.
+func (p *Patch) Bool(field string, dest *bool, def bool) {
+	value := def
+	if p.decode(field, &value, "boolean", true) {
+		*dest = value
+	}
+}
+
+// Int menerapkan field bilangan bulat ke dest. Null dicatat sebagai kesalahan.
+func (p *Patch) Int(field string, dest *int) {
+	var value int
+	if p.decode(field, &value, "bilangan bulat", false) {
+		*dest = value
+	}
+}
+
+// Fail mencatat kesalahan validasi pada field. Hanya kesalahan pertama tiap field yang disimpan.
+func (p *Patch) Fail(field string, message string) {
+	if _, ok := p.errors[field]; !ok {
+		p.errors[field] = message
+	}
+}
+
+// Errors mengembalikan kesalahan per field, termasuk field yang tidak dikenal resource, atau nil jika patch valid.
+// Fungsi ini dipanggil setelah seluruh field diterapkan.
+func (p *Patch) Errors() map[string]string {
+	for field := range p.fields {
+		if !p.used[field] {
+			p.Fail(field, "Field tidak dapat diubah.")
+		}
+	}
+	if len(p.errors) == 0 {
+		return nil
+	}
+	return p.errors
+}
+
+// decode membaca nilai field ke dest dan mengembalikan true jika dest harus diterapkan.
+// Field yang tidak dikirim atau tidak valid mengembalikan false. Null membiarkan dest apa adanya jika nullable.
+func (p *Patch) decode(field string, dest interface{}, typeName string, nullable bool) bool {
+	p.used[field] = true
+	value, ok := p.fields[field]
+	if !ok {
+		return false
+	}
+	if isNull(value) {
+		if !nullable {
+			p.Fail(field, "Field tidak boleh null.")
+			return false
+		}
+		return true
+	}
+	if err := json.Unmarshal(value, dest); err != nil {
+		p.Fail(field, fmt.Sprintf("Field harus berupa %s.", typeName))
+		return false
+	}
+	return true
+}
+
+func isNull(value json.RawMessage) bool {
+	return string(bytes.TrimSpace(value)) == "null"
+}
@@ -0,0 +1,178 @@
+package mergepatch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+		invalid bool
+	}{
+		{"objek", `{"caption": "halo"}`, nil, false},
+		{"objek dengan spasi", " \n{}\n", nil, false},
+		{"kosong", "", ErrNotObject, false},
+		{"null", "null", ErrNotObject, false},
+		{"array", `[{"op": "replace"}]`, ErrNotObject, false},
+		{"string", `"halo"`, ErrNotObject, false},
+		{"JSON rusak", `{"caption": }`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := Parse(strings.NewReader(tt.body))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse = %v, ingin %v", err, tt.wantErr)
+				}
+			case tt.invalid:
+				if err == nil {
+					t.Fatal("Parse seharusnya gagal")
+				}
+			default:
+				if err != nil || patch == nil {
+					t.Fatalf("Parse = %v, %v", patch, err)
+				}
+			}
+		})
+	}
+}
+
+func TestIsSupportedContentType(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"application/merge-patch+json", true},
+		{"application/merge-patch+json; charset=utf-8", true},
+		{"application/json", true},
+		{"application/json-patch+json", false},
+		{"text/plain", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsSupportedContentType(tt.header); got != tt.want {
+			t.Errorf("IsSupportedContentType(%q) = %v, ingin %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		nullable bool
+		want     string
+		wantErr  bool
+	}{
+		{"tidak dikirim", `{}`, true, "lama", false},
+		{"diisi", `{"bio": "baru"}`, true, "baru", false},
+		{"string kosong", `{"bio": ""}`, false, "", false},
+		{"null dan nullable", `{"bio": null}`, true, "", false},
+		{"null dan tidak nullable", `{"bio": null}`, false, "lama", true},
+		{"bukan string", `{"bio": 12}`, true, "lama", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := Parse(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			value := "lama"
+			patch.String("bio", &value, tt.nullable)
+			if value != tt.want {
+				t.Errorf("nilai = %q, ingin %q", value, tt.want)
+			}
+			if _, failed := patch.Errors()["bio"]; failed != tt.wantErr {
+				t.Errorf("error = %v, ingin %v", patch.Errors(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBoolAndInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantBool bool
+		wantInt  int
+		wantErrs []string
+	}{
+		{"tidak dikirim", `{}`, false, 7, nil},
+		{"diisi", `{"private": true, "limit": 3}`, true, 3, nil},
+		{"null mengembalikan nilai bawaan", `{"private": null}`, true, 7, nil},
+		{"null pada bilangan bulat", `{"limit": null}`, false, 7, []string{"limit"}},
+		{"tipe salah", `{"private": "ya", "limit": 1.5}`, false, 7, []string{"private", "limit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := Parse(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			private, limit := false, 7
+			patch.Bool("private", &private, true)
+			patch.Int("limit", &limit)
+			if private != tt.wantBool || limit != tt.wantInt {
+				t.Errorf("nilai = %v, %d, ingin %v, %d", private, limit, tt.wantBool, tt.wantInt)
+			}
+			errs := patch.Errors()
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("error = %v, ingin pada %v", errs, tt.wantErrs)
+			}
+			for _, field := range tt.wantErrs {
+				if _, ok := errs[field]; !ok {
+					t.Errorf("tidak ada error untuk %s", field)
+				}
+			}
+		})
+	}
+}
+
+func TestHasAndIsNull(t *testing.T) {
+	patch, err := Parse(strings.NewReader(`{"website": null, "bio": "halo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		field    string
+		wantHas  bool
+		wantNull bool
+	}{
+		{"website", true, true},
+		{"bio", true, false},
+		{"location", false, false},
+	}
+	for _, tt := range tests {
+		if got := patch.Has(tt.field); got != tt.wantHas {
+			t.Errorf("Has(%q) = %v, ingin %v", tt.field, got, tt.wantHas)
+		}
+		if got := patch.IsNull(tt.field); got != tt.wantNull {
+			t.Errorf("IsNull(%q) = %v, ingin %v", tt.field, got, tt.wantNull)
+		}
+	}
+}
+
+func TestErrorsRejectsUnknownFields(t *testing.T) {
+	patch, err := Parse(strings.NewReader(`{"bio": "halo", "id": 5}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bio := ""
+	patch.String("bio", &bio, true)
+	patch.Fail("bio", "Bio terlalu panjang.")
+	patch.Fail("bio", "pesan kedua tidak dipakai")
+
+	errs := patch.Errors()
+	if errs["bio"] != "Bio terlalu panjang." {
+		t.Errorf("error bio = %q", errs["bio"])
+	}
+	if errs["id"] != "Field tidak dapat diubah." {
+		t.Errorf("error id = %q", errs["id"])
+	}
+}
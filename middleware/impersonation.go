@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"mygram-final-project/audit"
+	"mygram-final-project/initializers"
+	"mygram-final-project/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DenyImpersonation adalah middleware yang menolak permintaan dari sesi impersonasi. Dipasang pada endpoint keamanan akun
+// seperti mengganti email, menghapus akun, ekspor data, dan pesan pribadi. Middleware ini harus dipasang setelah UserExtractor.
+func DenyImpersonation() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := ctx.Get("impersonatorId"); ok {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Tindakan ini tidak dapat dilakukan selama impersonasi."})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// validImpersonator memastikan admin impersonatorID masih aktif sebagai admin dan sesinya belum dicabut sejak token
+// impersonasi diterbitkan pada issuedAt, sehingga token dari admin yang sudah diturunkan perannya tidak berlaku lagi
+func validImpersonator(impersonatorID int64, issuedAt time.Time) bool {
+	var admin models.User
+	if err := initializers.DB.First(&admin, "id = ?", impersonatorID).Error; err != nil {
+		return false
+	}
+	if admin.Role != models.RoleAdmin || admin.IsSuspended(time.Now()) || admin.IsDeletionScheduled() {
+		return false
+	}
+	return admin.SessionsRevokedAt == nil || !issuedAt.Before(admin.SessionsRevokedAt.Truncate(time.Second))
+}
+
+// recordImpersonatedRequest mencatat permintaan yang mengubah data selama sesi impersonasi ke audit log, termasuk
+// permintaan yang ditolak, sehingga setiap tindakan admin atas nama pengguna dapat ditelusuri
+func recordImpersonatedRequest(ctx *gin.Context, userID int64, impersonatorID int64) {
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+
+	err := audit.Record(initializers.DB, audit.Entry{
+		ActorID:        &userID,
+		ImpersonatorID: &impersonatorID,
+		Action:         models.AuditImpersonatedRequest,
+		TargetType:     models.TargetUser,
+		TargetID:       &userID,
+		Metadata: map[string]interface{}{
+			"method": ctx.Request.Method,
+			"route":  ctx.FullPath(),
+			"path":   ctx.Request.URL.Path,
+			"status": ctx.Writer.Status(),
+		},
+		IP:        ctx.ClientIP(),
+		RequestID: ctx.GetString("requestId"),
+	})
+	if err != nil {
+		log.Printf("middleware: gagal mencatat permintaan impersonasi %s: %v", ctx.GetString("requestId"), err)
+	}
+}
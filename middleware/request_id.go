@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestIDPattern membatasi ID permintaan dari klien agar aman ditulis ke log dan header
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID adalah middleware yang memberi setiap permintaan ID unik. ID dari header X-Request-ID dipakai jika valid,
+// sehingga permintaan dapat ditelusuri lintas layanan. ID disimpan di context dengan key "requestId" dan dikirim kembali di header respons.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader("X-Request-ID")
+		if !requestIDPattern.MatchString(requestID) {
+			buf := make([]byte, 16)
+			rand.Read(buf)
+			requestID = hex.EncodeToString(buf)
+		}
+
+		ctx.Set("requestId", requestID)
+		ctx.Header("X-Request-ID", requestID)
+		ctx.Next()
+	}
+}
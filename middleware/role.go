@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"mygram-final-project/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole adalah middleware yang hanya meneruskan permintaan dari pengguna dengan salah satu peran yang diberikan.
+// Middleware ini harus dipasang setelah UserExtractor.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		currentUser := ctx.MustGet("currentUser").(models.User)
+
+		for _, role := range roles {
+			if currentUser.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Kamu tidak memiliki akses ke resource ini."})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"mygram-final-project/initializers"
 	"mygram-final-project/models"
@@ -33,19 +34,51 @@ func UserExtractor() gin.HandlerFunc {
 		}
 
 		config, _ := initializers.LoadConfig(".")
-		sub, err := utils.ValidateToken(access_token, config.AccessTokenPublicKey)
+		claims, err := utils.ParseToken(access_token, config.AccessTokenPublicKey)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": err.Error()})
 			return
 		}
 
 		var user models.User
-		result := initializers.DB.First(&user, "id = ?", fmt.Sprint(sub))
+		result := initializers.DB.First(&user, "id = ?", fmt.Sprint(claims["sub"]))
 		if result.Error != nil {
 			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Tidak ada user dengan token ini."})
 			return
 		}
 
+		if user.IsSuspended(time.Now()) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Akun kamu sedang ditangguhkan."})
+			return
+		}
+
+		// Token yang diterbitkan sebelum penghapusan dijadwalkan tidak berlaku, pengguna harus login kembali untuk membatalkannya
+		if user.IsDeletionScheduled() {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"status": "fail", "message": "Akun kamu dijadwalkan untuk dihapus. Login kembali untuk membatalkan penghapusan."})
+			return
+		}
+
+		// Token yang diterbitkan sebelum sesi pengguna dicabut, misalnya saat admin mewajibkan reset password, tidak berlaku
+		if issuedAt, ok := claims["iat"].(float64); ok && user.SessionsRevokedAt != nil && int64(issuedAt) < user.SessionsRevokedAt.Unix() {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": "Sesi kamu sudah berakhir, silakan login kembali."})
+			return
+		}
+
+		// Token impersonasi menyimpan ID admin yang memakainya agar setiap tindakan tercatat atas nama admin tersebut.
+		// Admin diperiksa ulang pada setiap permintaan, dan setiap permintaan yang mengubah data dicatat di audit log.
+		if impersonator, ok := claims["imp"].(float64); ok {
+			issuedAt, _ := claims["iat"].(float64)
+			if !validImpersonator(int64(impersonator), time.Unix(int64(issuedAt), 0)) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "fail", "message": "Sesi impersonasi sudah tidak berlaku."})
+				return
+			}
+			ctx.Set("impersonatorId", int64(impersonator))
+			ctx.Set("currentUser", user)
+			ctx.Next()
+			recordImpersonatedRequest(ctx, user.ID, int64(impersonator))
+			return
+		}
+
 		ctx.Set("currentUser", user)
 		ctx.Next()
 	}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 
+	"gorm.io/gorm"
+
+	"mygram-final-project/audit"
 	"mygram-final-project/initializers"
 	"mygram-final-project/models"
 )
@@ -19,6 +22,80 @@ func init() {
 
 func main() {
 	initializers.DB.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"")
-	initializers.DB.AutoMigrate(&models.User{}, &models.Photo{}, &models.Comment{}, &models.SocialMedia{})
+
+	// Hanya satu notifikasi belum dibaca yang boleh ada per group_key. Sebelum index unik dibuat, notifikasi ganda yang
+	// belum dibaca ditandai sudah dibaca kecuali yang paling baru.
+	if initializers.DB.Migrator().HasTable(&models.Notification{}) {
+		err := initializers.DB.Exec(`
+			UPDATE notifications n SET read_at = n.updated_at
+			WHERE n.read_at IS NULL AND EXISTS (
+				SELECT 1 FROM notifications m
+				WHERE m.user_id = n.user_id AND m.group_key = n.group_key AND m.read_at IS NULL
+				  AND (m.updated_at, m.id) > (n.updated_at, n.id)
+			)`).Error
+		if err != nil {
+			log.Fatal("Could not merge duplicate unread notifications", err)
+		}
+	}
+
+	initializers.DB.AutoMigrate(
+		&models.User{},
+		&models.Photo{},
+		&models.Comment{},
+		&models.SocialMedia{},
+		&models.PhotoLike{},
+		&models.CommentLike{},
+		&models.Mention{},
+		&models.Notification{},
+		&models.NotificationActor{},
+		&models.NotificationPreference{},
+		&models.Follow{},
+		&models.FollowRequest{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{},
+		&models.OutboxEvent{},
+		&models.Job{},
+		&models.Block{},
+		&models.Mute{},
+		&models.Conversation{},
+		&models.ConversationMember{},
+		&models.Message{},
+		&models.Story{},
+		&models.StoryView{},
+		&models.Report{},
+		&models.ModerationAction{},
+		&models.AccountDeletion{},
+		&models.DataExport{},
+		&models.AuditLog{},
+		&models.PasswordReset{},
+	)
+
+	// Usia statis diganti tanggal lahir. Tanggal lahir pengguna lama diperkirakan dari usia yang diisi saat mendaftar,
+	// dengan perkiraan termuda agar batas usia tidak terlewati, lalu kolom age dihapus.
+	migrator := initializers.DB.Migrator()
+	if migrator.HasColumn(&models.User{}, "age") {
+		err := initializers.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("UPDATE users SET birthdate = (created_at - make_interval(years => age))::date, birthdate_estimated = true WHERE birthdate IS NULL").Error; err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.User{}, "age")
+		})
+		if err != nil {
+			log.Fatal("Could not migrate user age to birthdate", err)
+		}
+	}
+
+	// Isi respons penerima webhook tidak lagi disimpan, termasuk yang tersimpan sebelumnya
+	if migrator.HasColumn(&models.WebhookDeliveryAttempt{}, "response_body") {
+		if err := migrator.DropColumn(&models.WebhookDeliveryAttempt{}, "response_body"); err != nil {
+			log.Fatal("Could not drop webhook response bodies", err)
+		}
+	}
+
+	// Data pribadi pada audit log lama disamarkan seperti catatan baru
+	if _, err := audit.RedactExisting(initializers.DB); err != nil {
+		log.Fatal("Could not redact personal data in audit logs", err)
+	}
 	fmt.Println("Migration complete!")
 }
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Status permintaan penghapusan akun.
+const (
+	AccountDeletionScheduled = "scheduled" // Menunggu masa tenggang berakhir
+	AccountDeletionCancelled = "cancelled" // Dibatalkan karena pengguna login kembali
+	AccountDeletionCompleted = "completed" // Seluruh data pengguna sudah dihapus permanen
+)
+
+// DefaultAccountDeletionGrace adalah masa tenggang sebelum akun yang diminta untuk dihapus benar-benar dihapus,
+// jika tidak diatur melalui konfigurasi.
+const DefaultAccountDeletionGrace = 14 * 24 * time.Hour
+
+// AccountDeletion merupakan catatan permintaan penghapusan akun. Catatan ini tetap disimpan setelah akun dihapus
+// sebagai bukti penghapusan, sehingga tidak memiliki foreign key ke users dan tidak menyimpan email dalam bentuk asli.
+type AccountDeletion struct {
+	ID           int64      `gorm:"primaryKey"`
+	UserID       int64      `gorm:"not null;index"`         // ID pengguna yang dihapus
+	EmailHash    string     `gorm:"size:64;not null;index"` // SHA-256 dari email pengguna, untuk pencocokan permintaan verifikasi
+	Status       string     `gorm:"size:20;not null;index"` // Status permintaan
+	RequestedAt  time.Time  `gorm:"not null"`               // Waktu penghapusan diminta
+	ScheduledFor time.Time  `gorm:"not null"`               // Waktu paling awal data dihapus permanen
+	CancelledAt  *time.Time // Waktu permintaan dibatalkan
+	CompletedAt  *time.Time // Waktu seluruh data selesai dihapus
+	Summary      string     `gorm:"type:text"` // Jumlah baris yang dihapus per jenis data dalam format JSON
+	Checksum     string     `gorm:"size:64"`   // SHA-256 atas isi catatan yang sudah selesai, lihat ComputeChecksum
+	CreatedAt    time.Time  // Waktu catatan dibuat
+	UpdatedAt    time.Time  // Waktu pembaruan terakhir catatan
+}
+
+// HashEmail mengembalikan SHA-256 dari email yang sudah dinormalisasi.
+func HashEmail(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeChecksum menghitung checksum catatan penghapusan yang sudah selesai dari ID, UserID, EmailHash,
+// waktu permintaan, waktu selesai, dan Summary. Catatan dapat diverifikasi dengan membandingkan hasilnya dengan Checksum.
+func (d AccountDeletion) ComputeChecksum() string {
+	var completedAt int64
+	if d.CompletedAt != nil {
+		completedAt = d.CompletedAt.UTC().UnixMicro()
+	}
+	data := fmt.Sprintf("%d|%d|%s|%d|%d|%s", d.ID, d.UserID, d.EmailHash, d.RequestedAt.UTC().UnixMicro(), completedAt, d.Summary)
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify menentukan apakah catatan sudah selesai dan isinya sesuai dengan Checksum.
+func (d AccountDeletion) Verify() bool {
+	return d.Status == AccountDeletionCompleted && d.Checksum != "" && d.Checksum == d.ComputeChecksum()
+}
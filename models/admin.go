@@ -0,0 +1,26 @@
+package models
+
+// AdminSuspendRequest adalah struktur data yang digunakan admin untuk menangguhkan atau memulihkan akun pengguna.
+type AdminSuspendRequest struct {
+	SuspendDays int    `json:"suspend_days,omitempty"` // Lama penangguhan dalam hari, 0 berarti tanpa batas waktu
+	Note        string `json:"note,omitempty"`         // Catatan admin
+}
+
+// ImpersonateRequest adalah struktur data yang digunakan admin untuk memulai sesi sebagai pengguna lain.
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required"` // Alasan impersonasi, misalnya nomor tiket dukungan (wajib diisi)
+}
+
+// ContentTarget adalah konten yang dikenai tindakan admin.
+type ContentTarget struct {
+	TargetType string `json:"target_type" binding:"required"` // Jenis konten: photo atau comment
+	TargetID   int64  `json:"target_id" binding:"required"`   // ID konten
+}
+
+// BulkRemoveContentRequest adalah struktur data yang digunakan admin untuk menghapus banyak konten sekaligus.
+// Konten dapat dipilih satu per satu melalui Targets, atau seluruh foto dan komentar seorang pengguna melalui Username.
+type BulkRemoveContentRequest struct {
+	Targets  []ContentTarget `json:"targets,omitempty"`  // Daftar konten yang dihapus
+	Username string          `json:"username,omitempty"` // Hapus seluruh foto dan komentar milik pengguna ini
+	Note     string          `json:"note,omitempty"`     // Catatan admin
+}
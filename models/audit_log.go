@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Jenis tindakan yang dicatat di audit log. Tindakan moderator dicatat dengan awalan "moderation." diikuti nama tindakannya.
+const (
+	AuditRegister            = "auth.register"              // Pengguna mendaftar
+	AuditLogin               = "auth.login"                 // Pengguna berhasil login
+	AuditLoginFailed         = "auth.login_failed"          // Percobaan login gagal
+	AuditUserUpdate          = "user.update"                // Profil pengguna diubah
+	AuditDeletionScheduled   = "user.deletion_scheduled"    // Penghapusan akun dijadwalkan
+	AuditDeletionCancelled   = "user.deletion_cancelled"    // Penghapusan akun dibatalkan karena pengguna login kembali
+	AuditUserPurged          = "user.purged"                // Akun dan seluruh datanya dihapus permanen
+	AuditPhotoDelete         = "photo.delete"               // Foto dipindahkan ke tempat sampah oleh pemiliknya
+	AuditCommentDelete       = "comment.delete"             // Komentar dihapus oleh penulis atau pemilik foto
+	AuditSocialMediaUpdate   = "social_media.update"        // Media sosial diubah
+	AuditSocialMediaDelete   = "social_media.delete"        // Media sosial dipindahkan ke tempat sampah
+	AuditPasswordReset       = "auth.password_reset"        // Pengguna mengganti password dengan token reset
+	AuditForcePasswordReset  = "admin.force_password_reset" // Admin mewajibkan pengguna mengganti password
+	AuditImpersonate         = "admin.impersonate"          // Admin memulai sesi sebagai pengguna lain
+	AuditImpersonatedRequest = "admin.impersonated_request" // Permintaan yang mengubah data selama sesi impersonasi
+	AuditModerationPrefix    = "moderation."                // Awalan tindakan moderator dan admin pada konten atau pengguna
+)
+
+// ErrAuditLogImmutable dikembalikan saat ada upaya mengubah atau menghapus catatan audit log.
+var ErrAuditLogImmutable = errors.New("catatan audit log tidak dapat diubah atau dihapus")
+
+// AuditLog merupakan catatan permanen atas tindakan yang berkaitan dengan keamanan dan administrasi.
+// Catatan hanya dapat ditambahkan dan tidak memiliki foreign key agar tetap utuh setelah pengguna atau objeknya dihapus.
+type AuditLog struct {
+	ID             int64     `gorm:"primaryKey"`
+	ActorID        *int64    `gorm:"index"`                               // ID pengguna yang melakukan tindakan, kosong untuk proses sistem atau pengguna yang tidak dikenal
+	ImpersonatorID *int64    `gorm:"index"`                               // ID admin yang melakukan tindakan atas nama ActorID melalui sesi impersonasi
+	Action         string    `gorm:"size:50;not null;index"`              // Jenis tindakan
+	TargetType     string    `gorm:"size:30;index:idx_audit_logs_target"` // Jenis objek yang dikenai tindakan
+	TargetID       *int64    `gorm:"index:idx_audit_logs_target"`         // ID objek yang dikenai tindakan
+	Changes        string    `gorm:"type:text"`                           // Perubahan per field dalam format JSON {"field": {"before": ..., "after": ...}}
+	Metadata       string    `gorm:"type:text"`                           // Keterangan tambahan dalam format JSON
+	IP             string    `gorm:"size:45"`                             // Alamat IP asal permintaan
+	RequestID      string    `gorm:"size:64;index"`                       // ID permintaan HTTP, sama dengan header X-Request-ID
+	CreatedAt      time.Time `gorm:"not null;index"`                      // Waktu tindakan
+}
+
+// BeforeUpdate mencegah perubahan catatan audit log.
+func (AuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+// BeforeDelete mencegah penghapusan catatan audit log.
+func (AuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// Block merupakan model untuk pemblokiran pengguna. Pemblokiran berlaku dua arah: kedua pengguna tidak dapat saling berinteraksi.
+type Block struct {
+	BlockerID int64     `gorm:"primaryKey"`                                       // ID pengguna yang memblokir
+	Blocker   User      `gorm:"foreignKey:BlockerID;constraint:OnDelete:CASCADE"` // Pengguna yang memblokir
+	BlockedID int64     `gorm:"primaryKey;index"`                                 // ID pengguna yang diblokir
+	Blocked   User      `gorm:"foreignKey:BlockedID;constraint:OnDelete:CASCADE"` // Pengguna yang diblokir
+	CreatedAt time.Time // Waktu pemblokiran
+}
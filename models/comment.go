@@ -2,24 +2,47 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status komentar.
+const (
+	CommentPublished = "published" // Komentar terlihat oleh semua pengguna
+	CommentPending   = "pending"   // Komentar ditahan filter konten dan hanya terlihat oleh penulis dan moderator
 )
 
 // Comment merupakan model untuk komentar pada foto.
 type Comment struct {
-	ID        int64     `gorm:"primaryKey"`
-	UserID    int64     `gorm:"not null"`
-	User      User      `gorm:"foreignKey:UserID"`
-	PhotoID   int64     `gorm:"not null"`
-	Photo     Photo     `gorm:"foreignKey:PhotoID"`
-	Message   string    `gorm:"size:200;not null"`
-	CreatedAt time.Time // Waktu pembuatan komentar
-	UpdatedAt time.Time // Waktu pembaruan terakhir komentar
+	ID            int64          `gorm:"primaryKey"`
+	UserID        int64          `gorm:"not null"`
+	User          User           `gorm:"foreignKey:UserID"`
+	PhotoID       int64          `gorm:"not null;index"`
+	Photo         Photo          `gorm:"foreignKey:PhotoID"`
+	Message       string         `gorm:"size:200;not null"`
+	LikeCount     int64          `gorm:"not null;default:0"`     // Jumlah like pada komentar
+	ParentID      *int64         `gorm:"index"`                  // ID komentar induk jika komentar ini merupakan balasan
+	Depth         int            `gorm:"not null;default:0"`     // Kedalaman balasan, 0 untuk komentar utama
+	ReplyCount    int64          `gorm:"not null;default:0"`     // Jumlah balasan langsung pada komentar
+	Tombstoned    bool           `gorm:"not null;default:false"` // Komentar sudah dihapus tetapi dipertahankan karena masih memiliki balasan
+	TombstonedAt  *time.Time     // Waktu komentar diubah menjadi tombstone
+	HiddenAt      *time.Time     // Waktu komentar disembunyikan oleh moderator
+	OwnerHiddenAt *time.Time     // Waktu komentar disembunyikan oleh pemilik foto
+	PinnedAt      *time.Time     // Waktu komentar disematkan oleh pemilik foto
+	Status        string         `gorm:"size:20;not null;default:published;index"` // Status publikasi komentar
+	FilterNote    string         `gorm:"type:text"`                                // Alasan filter konten menahan komentar
+	Version       int64          `gorm:"not null;default:1"`                       // Versi isi komentar untuk ETag, naik setiap kali komentar diedit
+	CreatedAt     time.Time      // Waktu pembuatan komentar
+	UpdatedAt     time.Time      // Waktu pembaruan terakhir komentar
+	DeletedAt     gorm.DeletedAt `gorm:"index"` // Waktu komentar dipindahkan ke tempat sampah
+	DeletedByID   *int64         // ID pengguna yang menghapus komentar
 }
 
 // CreateCommentRequest adalah struktur data yang digunakan untuk membuat komentar baru.
 type CreateCommentRequest struct {
-	PhotoID int64  `json:"photo_id" validate:"required"` // ID foto yang akan dikomentari
-	Message string `json:"message" validate:"required"`  // Isi pesan komentar
+	PhotoID  int64  `json:"photo_id" validate:"required"`             // ID foto yang akan dikomentari
+	Message  string `json:"message" validate:"required"`              // Isi pesan komentar
+	ParentID *int64 `json:"parent_id,omitempty" validate:"omitempty"` // ID komentar yang dibalas (opsional)
 }
 
 // UpdateCommentRequest adalah struktur data yang digunakan untuk memperbarui komentar yang sudah ada.
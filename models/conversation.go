@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// Conversation merupakan model untuk percakapan pribadi antara dua pengguna atau grup kecil.
+type Conversation struct {
+	ID            int64                `gorm:"primaryKey"`
+	IsGroup       bool                 `gorm:"not null;default:false"`      // Percakapan grup atau 1:1
+	Title         string               `gorm:"size:100"`                    // Judul percakapan grup (opsional)
+	DirectKey     *string              `gorm:"size:50;uniqueIndex"`         // Kunci pasangan pengguna untuk percakapan 1:1 agar tidak terbentuk ganda
+	CreatorID     int64                `gorm:"not null"`                    // ID pengguna yang membuat percakapan
+	LastMessageAt *time.Time           `gorm:"index"`                       // Waktu pesan terakhir
+	Members       []ConversationMember `gorm:"constraint:OnDelete:CASCADE"` // Anggota percakapan
+	CreatedAt     time.Time            // Waktu pembuatan percakapan
+	UpdatedAt     time.Time            // Waktu pembaruan terakhir percakapan
+}
+
+// ConversationMember merupakan model untuk keanggotaan pengguna dalam percakapan beserta posisi baca terakhirnya.
+type ConversationMember struct {
+	ConversationID    int64      `gorm:"primaryKey"`
+	UserID            int64      `gorm:"primaryKey;index"`
+	User              User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	LastReadMessageID int64      `gorm:"not null;default:0"` // ID pesan terakhir yang sudah dibaca
+	LastReadAt        *time.Time // Waktu terakhir membaca percakapan
+	JoinedAt          time.Time  // Waktu bergabung ke percakapan
+}
+
+// Message merupakan model untuk pesan di dalam percakapan. Pesan dapat berisi teks, foto yang dibagikan, atau keduanya.
+type Message struct {
+	ID             int64        `gorm:"primaryKey;index:idx_messages_conversation_id_id,priority:2"`
+	ConversationID int64        `gorm:"not null;index:idx_messages_conversation_id_id,priority:1"`
+	Conversation   Conversation `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE"`
+	SenderID       int64        `gorm:"not null"`
+	Sender         User         `gorm:"foreignKey:SenderID;constraint:OnDelete:CASCADE"`
+	Body           string       `gorm:"type:text"`                                       // Isi pesan
+	PhotoID        *int64       `gorm:"index"`                                           // ID foto yang dibagikan (opsional)
+	Photo          *Photo       `gorm:"foreignKey:PhotoID;constraint:OnDelete:SET NULL"` // Foto yang dibagikan
+	CreatedAt      time.Time    // Waktu pengiriman pesan
+}
+
+// CreateConversationRequest adalah struktur data yang digunakan untuk membuat percakapan baru.
+type CreateConversationRequest struct {
+	Usernames []string `json:"usernames" binding:"required,min=1"` // Username pengguna lain yang diajak bercakap
+	Title     string   `json:"title"`                              // Judul percakapan grup (opsional)
+}
+
+// SendMessageRequest adalah struktur data yang digunakan untuk mengirim pesan.
+type SendMessageRequest struct {
+	Body    string `json:"body"`               // Isi pesan
+	PhotoID *int64 `json:"photo_id,omitempty"` // ID foto yang dibagikan (opsional)
+}
+
+// MarkConversationReadRequest adalah struktur data yang digunakan untuk menandai percakapan sudah dibaca.
+type MarkConversationReadRequest struct {
+	MessageID int64 `json:"message_id"` // ID pesan terakhir yang dibaca, 0 berarti pesan terbaru
+}
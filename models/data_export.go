@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Status arsip ekspor data pengguna.
+const (
+	DataExportPending = "pending" // Menunggu dibuat oleh worker
+	DataExportReady   = "ready"   // Arsip siap diunduh sampai ExpiresAt
+	DataExportFailed  = "failed"  // Pembuatan arsip gagal setelah batas percobaan habis
+	DataExportExpired = "expired" // Arsip sudah dihapus karena melewati masa berlaku
+)
+
+// DefaultDataExportTTL adalah masa berlaku arsip ekspor dan tautan unduhannya, jika tidak diatur melalui konfigurasi.
+const DefaultDataExportTTL = 7 * 24 * time.Hour
+
+// DataExport merupakan model untuk permintaan ekspor data pribadi pengguna dalam bentuk arsip ZIP.
+type DataExport struct {
+	ID          int64      `gorm:"primaryKey"`
+	UserID      int64      `gorm:"not null;index"`                                // ID pengguna pemilik data
+	User        User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Pengguna pemilik data
+	Status      string     `gorm:"size:20;not null;index"`                        // Status arsip
+	FileKey     string     `gorm:"size:200"`                                      // Key berkas arsip pada penyimpanan
+	Size        int64      `gorm:"not null;default:0"`                            // Ukuran arsip dalam byte
+	Error       string     `gorm:"type:text"`                                     // Pesan error jika pembuatan arsip gagal
+	CompletedAt *time.Time // Waktu arsip selesai dibuat
+	ExpiresAt   *time.Time // Waktu arsip dan tautan unduhannya tidak lagi berlaku
+	CreatedAt   time.Time  // Waktu ekspor diminta
+	UpdatedAt   time.Time  // Waktu pembaruan terakhir status
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// Follow merupakan model untuk hubungan mengikuti antar pengguna.
+type Follow struct {
+	FollowerID  int64     `gorm:"primaryKey"`                                         // ID pengguna yang mengikuti
+	Follower    User      `gorm:"foreignKey:FollowerID;constraint:OnDelete:CASCADE"`  // Pengguna yang mengikuti
+	FollowingID int64     `gorm:"primaryKey;index"`                                   // ID pengguna yang diikuti
+	Following   User      `gorm:"foreignKey:FollowingID;constraint:OnDelete:CASCADE"` // Pengguna yang diikuti
+	CreatedAt   time.Time // Waktu mulai mengikuti
+}
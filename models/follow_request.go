@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// FollowRequest merupakan model untuk permintaan mengikuti akun privat yang menunggu persetujuan pemilik akun.
+type FollowRequest struct {
+	RequesterID int64     `gorm:"primaryKey"`                                         // ID pengguna yang meminta mengikuti
+	Requester   User      `gorm:"foreignKey:RequesterID;constraint:OnDelete:CASCADE"` // Pengguna yang meminta mengikuti
+	TargetID    int64     `gorm:"primaryKey;index"`                                   // ID pemilik akun privat
+	Target      User      `gorm:"foreignKey:TargetID;constraint:OnDelete:CASCADE"`    // Pemilik akun privat
+	CreatedAt   time.Time // Waktu permintaan dikirim
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Status job pada antrean.
+const (
+	JobPending   = "pending"   // Menunggu dijalankan atau dijalankan ulang
+	JobRunning   = "running"   // Sedang dijalankan oleh worker
+	JobSucceeded = "succeeded" // Selesai dengan sukses
+	JobDead      = "dead"      // Gagal setelah batas percobaan habis (dead-letter)
+)
+
+// Job merupakan model untuk pekerjaan latar belakang pada antrean berbasis PostgreSQL.
+type Job struct {
+	ID          int64      `gorm:"primaryKey"`
+	Type        string     `gorm:"size:100;not null"`                     // Jenis job, menentukan handler yang menjalankannya
+	Payload     string     `gorm:"type:text;not null"`                    // Data job dalam format JSON
+	Status      string     `gorm:"size:20;not null;index:idx_jobs_claim"` // Status job
+	Attempts    int        `gorm:"not null;default:0"`                    // Jumlah percobaan yang sudah dimulai
+	MaxAttempts int        `gorm:"not null"`                              // Batas percobaan sebelum job menjadi dead
+	RunAt       time.Time  `gorm:"not null;index:idx_jobs_claim"`         // Waktu paling awal job boleh dijalankan
+	UniqueKey   *string    `gorm:"size:200;uniqueIndex"`                  // Kunci unik opsional untuk mencegah job ganda
+	LockedAt    *time.Time // Waktu job diklaim oleh worker
+	LockedBy    string     `gorm:"size:100"`  // Identitas worker yang mengklaim job
+	LastError   string     `gorm:"type:text"` // Pesan error percobaan terakhir
+	CompletedAt *time.Time // Waktu job selesai
+	CreatedAt   time.Time  // Waktu job dibuat
+	UpdatedAt   time.Time  // Waktu pembaruan terakhir status
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// PhotoLike merupakan model untuk like yang diberikan pengguna pada foto.
+type PhotoLike struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"not null;uniqueIndex:idx_photo_likes_user_photo"`       // ID pengguna yang memberi like
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`         // Pengguna yang memberi like
+	PhotoID   int64     `gorm:"not null;uniqueIndex:idx_photo_likes_user_photo;index"` // ID foto yang di-like
+	Photo     Photo     `gorm:"foreignKey:PhotoID;constraint:OnDelete:CASCADE"`        // Foto yang di-like
+	CreatedAt time.Time // Waktu pemberian like
+}
+
+// CommentLike merupakan model untuk like yang diberikan pengguna pada komentar.
+type CommentLike struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"not null;uniqueIndex:idx_comment_likes_user_comment"`       // ID pengguna yang memberi like
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`             // Pengguna yang memberi like
+	CommentID int64     `gorm:"not null;uniqueIndex:idx_comment_likes_user_comment;index"` // ID komentar yang di-like
+	Comment   Comment   `gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`          // Komentar yang di-like
+	CreatedAt time.Time // Waktu pemberian like
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Jenis sumber yang dapat memuat mention.
+const (
+	MentionSourcePhoto   = "photo"   // Mention pada caption foto
+	MentionSourceComment = "comment" // Mention pada pesan komentar
+)
+
+// Mention merupakan model untuk @username yang disebut di dalam caption foto atau pesan komentar.
+type Mention struct {
+	ID              int64     `gorm:"primaryKey"`
+	SourceType      string    `gorm:"size:20;not null;index:idx_mentions_source"` // Jenis sumber mention (photo atau comment)
+	SourceID        int64     `gorm:"not null;index:idx_mentions_source"`         // ID foto atau komentar yang memuat mention
+	AuthorID        int64     `gorm:"not null"`                                   // ID pengguna yang menulis mention
+	MentionedUserID int64     `gorm:"not null;index"`                             // ID pengguna yang disebut
+	MentionedUser   User      `gorm:"foreignKey:MentionedUserID;constraint:OnDelete:CASCADE"`
+	Offset          int       `gorm:"column:mention_offset;not null"` // Posisi karakter @ di dalam teks
+	Length          int       `gorm:"column:mention_length;not null"` // Panjang mention dalam karakter, termasuk @
+	CreatedAt       time.Time // Waktu pembuatan mention
+}
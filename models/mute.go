@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// Mute merupakan model untuk pengguna yang dibisukan. Konten dan notifikasi dari pengguna yang dibisukan
+// disembunyikan dari pembisu, tanpa diketahui oleh pengguna yang dibisukan.
+type Mute struct {
+	MuterID   int64     `gorm:"primaryKey"`                                     // ID pengguna yang membisukan
+	Muter     User      `gorm:"foreignKey:MuterID;constraint:OnDelete:CASCADE"` // Pengguna yang membisukan
+	MutedID   int64     `gorm:"primaryKey;index"`                               // ID pengguna yang dibisukan
+	Muted     User      `gorm:"foreignKey:MutedID;constraint:OnDelete:CASCADE"` // Pengguna yang dibisukan
+	CreatedAt time.Time // Waktu pembisuan
+}
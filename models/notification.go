@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+)
+
+// Jenis notifikasi yang dikirim ke pengguna.
+const (
+	NotificationTypeMention        = "mention"         // Pengguna disebut di caption atau komentar
+	NotificationTypeComment        = "comment"         // Foto pengguna dikomentari
+	NotificationTypeReply          = "reply"           // Komentar pengguna dibalas
+	NotificationTypeLikePhoto      = "like_photo"      // Foto pengguna di-like
+	NotificationTypeLikeComment    = "like_comment"    // Komentar pengguna di-like
+	NotificationTypeFollow         = "follow"          // Pengguna diikuti oleh pengguna lain
+	NotificationTypeFollowRequest  = "follow_request"  // Pengguna lain meminta mengikuti akun privat pengguna
+	NotificationTypeFollowAccepted = "follow_accepted" // Permintaan mengikuti pengguna disetujui pemilik akun privat
+	NotificationTypeDataExport     = "data_export"     // Arsip ekspor data pengguna siap diunduh
+)
+
+// Jenis objek yang dapat menjadi target notifikasi.
+const (
+	TargetPhoto       = "photo"        // Target berupa foto
+	TargetComment     = "comment"      // Target berupa komentar
+	TargetUser        = "user"         // Target berupa pengguna
+	TargetDataExport  = "data_export"  // Target berupa arsip ekspor data
+	TargetSocialMedia = "social_media" // Target berupa media sosial
+)
+
+// NotificationTypes berisi semua jenis notifikasi yang dapat diatur preferensinya.
+// Notifikasi sistem seperti NotificationTypeDataExport selalu dikirim.
+var NotificationTypes = []string{
+	NotificationTypeMention,
+	NotificationTypeComment,
+	NotificationTypeReply,
+	NotificationTypeLikePhoto,
+	NotificationTypeLikeComment,
+	NotificationTypeFollow,
+	NotificationTypeFollowRequest,
+	NotificationTypeFollowAccepted,
+}
+
+// Notification merupakan model untuk notifikasi yang diterima pengguna.
+// Beberapa kejadian dengan GroupKey yang sama digabung dalam satu notifikasi selama notifikasi tersebut belum dibaca.
+// Index unik idx_notifications_unread_group menjamin hanya ada satu notifikasi belum dibaca per GroupKey.
+type Notification struct {
+	ID         int64      `gorm:"primaryKey"`
+	UserID     int64      `gorm:"not null;index:idx_notifications_user_group;uniqueIndex:idx_notifications_unread_group,where:read_at IS NULL"` // ID pengguna penerima notifikasi
+	User       User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`                                                                // Pengguna penerima notifikasi
+	ActorID    int64      `gorm:"not null"`                                                                                                     // ID pengguna terakhir yang memicu notifikasi
+	Actor      User       `gorm:"foreignKey:ActorID;constraint:OnDelete:CASCADE"`
+	ActorCount int        `gorm:"not null;default:1"`                                                                                                    // Jumlah pengguna berbeda yang tergabung dalam notifikasi
+	Type       string     `gorm:"size:30;not null"`                                                                                                      // Jenis notifikasi
+	TargetType string     `gorm:"size:20;not null"`                                                                                                      // Jenis objek yang terkait dengan notifikasi
+	TargetID   int64      `gorm:"not null"`                                                                                                              // ID objek yang terkait dengan notifikasi
+	GroupKey   string     `gorm:"size:100;not null;index:idx_notifications_user_group;uniqueIndex:idx_notifications_unread_group,where:read_at IS NULL"` // Kunci penggabungan notifikasi sejenis
+	ReadAt     *time.Time // Waktu notifikasi dibaca, kosong jika belum dibaca
+	CreatedAt  time.Time  // Waktu pembuatan notifikasi
+	UpdatedAt  time.Time  // Waktu kejadian terakhir yang digabung ke notifikasi
+}
+
+// NotificationActor mencatat pengguna yang sudah tergabung dalam sebuah notifikasi agar tidak terhitung dua kali.
+type NotificationActor struct {
+	NotificationID int64        `gorm:"primaryKey"`
+	Notification   Notification `gorm:"foreignKey:NotificationID;constraint:OnDelete:CASCADE"`
+	ActorID        int64        `gorm:"primaryKey"`
+	CreatedAt      time.Time
+}
+
+// NotificationPreference menyimpan pilihan pengguna untuk menerima atau tidak menerima jenis notifikasi tertentu.
+// Jenis notifikasi tanpa baris preferensi dianggap aktif.
+type NotificationPreference struct {
+	UserID  int64  `gorm:"primaryKey"`
+	User    User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Type    string `gorm:"primaryKey;size:30"`
+	Enabled bool   `gorm:"not null"`
+}
+
+// UpdateNotificationPreferencesRequest adalah struktur data yang digunakan untuk memperbarui preferensi notifikasi.
+type UpdateNotificationPreferencesRequest struct {
+	Preferences map[string]bool `json:"preferences" binding:"required"` // Jenis notifikasi dan status aktifnya
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// OutboxEvent merupakan model untuk domain event yang ditulis dalam transaksi yang sama dengan perubahan data.
+// Event dikirim ke handler yang terdaftar oleh dispatcher secara at-least-once.
+type OutboxEvent struct {
+	ID                int64      `gorm:"primaryKey"`
+	Type              string     `gorm:"size:50;not null"`                         // Nama event, misalnya comment.created
+	AggregateType     string     `gorm:"size:30;not null"`                         // Jenis objek yang berubah
+	AggregateID       int64      `gorm:"not null"`                                 // ID objek yang berubah
+	ActorID           int64      `gorm:"not null"`                                 // ID pengguna yang melakukan perubahan
+	Payload           string     `gorm:"type:text;not null"`                       // Data event dalam format JSON
+	Attempts          int        `gorm:"not null;default:0"`                       // Jumlah percobaan pemrosesan
+	CompletedHandlers string     `gorm:"type:text;not null;default:''"`            // Handler yang sudah berhasil, dipisah koma
+	LastError         string     `gorm:"type:text"`                                // Pesan error percobaan terakhir
+	NextAttemptAt     time.Time  `gorm:"not null;index:idx_outbox_events_pending"` // Waktu percobaan berikutnya
+	ProcessedAt       *time.Time `gorm:"index:idx_outbox_events_pending"`          // Waktu semua handler selesai
+	DeadAt            *time.Time // Waktu event berhenti dicoba karena batas percobaan habis (dead-letter)
+	CreatedAt         time.Time  // Waktu event terjadi
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// DefaultPasswordResetTTL adalah masa berlaku default token reset password yang dibuat admin
+const DefaultPasswordResetTTL = 24 * time.Hour
+
+// PasswordReset merupakan token sekali pakai untuk mengganti password setelah admin mewajibkan reset password.
+// Token hanya disimpan dalam bentuk hash.
+type PasswordReset struct {
+	ID          int64      `gorm:"primaryKey"`
+	UserID      int64      `gorm:"not null;index"` // ID pengguna yang harus mengganti password
+	User        User       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	TokenHash   string     `gorm:"size:64;not null;uniqueIndex"` // SHA-256 dari token
+	CreatedByID *int64     // ID admin yang membuat token
+	ExpiresAt   time.Time  `gorm:"not null"` // Batas waktu token dapat dipakai
+	UsedAt      *time.Time // Waktu token dipakai, kosong jika belum dipakai
+	CreatedAt   time.Time  // Waktu token dibuat
+}
+
+// ResetPasswordRequest adalah struktur data untuk mengganti password dengan token reset.
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`    // Token reset dari admin
+	Password string `json:"password" binding:"required"` // Password baru
+}
+
+// HashResetToken mengembalikan hash token reset password yang disimpan di database
+func HashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
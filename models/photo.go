@@ -2,31 +2,51 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pengaturan siapa saja yang boleh mengomentari foto.
+const (
+	CommentPolicyEveryone  = "everyone"  // Semua pengguna boleh berkomentar
+	CommentPolicyFollowers = "followers" // Hanya pengikut pemilik foto yang boleh berkomentar
+	CommentPolicyNobody    = "nobody"    // Komentar baru dinonaktifkan
 )
 
 // Photo merupakan model untuk foto yang diunggah oleh pengguna.
 type Photo struct {
-	ID        int64     `gorm:"primaryKey"`
-	Title     string    `gorm:"size:100;not null"`  // Judul foto
-	Caption   string    `gorm:"size:200"`           // Keterangan foto (opsional)
-	PhotoURL  string    `gorm:"type:text;not null"` // URL gambar foto
-	UserID    int64     `gorm:"not null"`           // ID pengguna yang mengunggah foto
-	User      User      `gorm:"foreignKey:UserID"`  // Pengguna yang mengunggah foto
-	CreatedAt time.Time // Waktu pembuatan foto
-	UpdatedAt time.Time // Waktu pembaruan terakhir foto
-	Comments  []Comment // Komentar yang terkait dengan foto
+	ID            int64          `gorm:"primaryKey"`
+	Title         string         `gorm:"size:100;not null"`  // Judul foto
+	Caption       string         `gorm:"size:200"`           // Keterangan foto (opsional)
+	PhotoURL      string         `gorm:"type:text;not null"` // URL gambar foto
+	UserID        int64          `gorm:"not null"`           // ID pengguna yang mengunggah foto
+	User          User           `gorm:"foreignKey:UserID"`  // Pengguna yang mengunggah foto
+	LikeCount     int64          `gorm:"not null;default:0"` // Jumlah like pada foto
+	HiddenAt      *time.Time     // Waktu foto disembunyikan oleh moderator
+	CommentPolicy string         `gorm:"size:20;not null;default:everyone"` // Siapa saja yang boleh mengomentari foto
+	AgeRestricted bool           `gorm:"not null;default:false"`            // Foto hanya ditampilkan kepada pengguna yang cukup umur
+	Version       int64          `gorm:"not null;default:1"`                // Versi foto untuk ETag, naik setiap kali diubah pemiliknya
+	CreatedAt     time.Time      // Waktu pembuatan foto
+	UpdatedAt     time.Time      // Waktu pembaruan terakhir foto
+	DeletedAt     gorm.DeletedAt `gorm:"index"` // Waktu foto dipindahkan ke tempat sampah
+	DeletedByID   *int64         // ID pengguna yang menghapus foto
+	Comments      []Comment      // Komentar yang terkait dengan foto
 }
 
 // CreatePhotoRequest adalah struktur data yang digunakan untuk membuat foto baru.
 type CreatePhotoRequest struct {
-	Title    string `json:"title" binding:"required"`     // Judul foto (wajib diisi)
-	Caption  string `json:"caption,omitempty"`            // Keterangan foto (opsional)
-	PhotoURL string `json:"photo_url" binding:"required"` // URL gambar foto (wajib diisi)
+	Title         string `json:"title" binding:"required"`     // Judul foto (wajib diisi)
+	Caption       string `json:"caption,omitempty"`            // Keterangan foto (opsional)
+	PhotoURL      string `json:"photo_url" binding:"required"` // URL gambar foto (wajib diisi)
+	CommentPolicy string `json:"comment_policy,omitempty"`     // Siapa saja yang boleh berkomentar: everyone (default), followers, atau nobody
+	AgeRestricted bool   `json:"age_restricted,omitempty"`     // Tandai foto sebagai berbatasan usia (opsional)
 }
 
 // UpdatePhoto adalah struktur data yang digunakan untuk memperbarui informasi foto yang sudah ada.
 type UpdatePhoto struct {
-	Title    string `json:"title" validate:"required"`     // Judul foto yang diperbarui (wajib diisi)
-	Caption  string `json:"caption,omitempty"`             // Keterangan foto yang diperbarui (opsional)
-	PhotoURL string `json:"photo_url" validate:"required"` // URL gambar foto yang diperbarui (wajib diisi)
+	Title         string `json:"title" validate:"required"`     // Judul foto yang diperbarui (wajib diisi)
+	Caption       string `json:"caption,omitempty"`             // Keterangan foto yang diperbarui (opsional)
+	PhotoURL      string `json:"photo_url" validate:"required"` // URL gambar foto yang diperbarui (wajib diisi)
+	CommentPolicy string `json:"comment_policy,omitempty"`      // Siapa saja yang boleh berkomentar, kosong berarti tidak diubah
+	AgeRestricted *bool  `json:"age_restricted,omitempty"`      // Tandai foto sebagai berbatasan usia, kosong berarti tidak diubah
 }
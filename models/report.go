@@ -0,0 +1,113 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kategori alasan laporan.
+const (
+	ReportReasonSpam           = "spam"
+	ReportReasonHarassment     = "harassment"
+	ReportReasonHateSpeech     = "hate_speech"
+	ReportReasonNudity         = "nudity"
+	ReportReasonViolence       = "violence"
+	ReportReasonMisinformation = "misinformation"
+	ReportReasonOther          = "other"
+)
+
+// ReportReasons berisi semua kategori alasan laporan yang dapat dipilih pelapor.
+var ReportReasons = []string{
+	ReportReasonSpam,
+	ReportReasonHarassment,
+	ReportReasonHateSpeech,
+	ReportReasonNudity,
+	ReportReasonViolence,
+	ReportReasonMisinformation,
+	ReportReasonOther,
+}
+
+// Status laporan pada antrean moderasi.
+const (
+	ReportOpen      = "open"      // Menunggu ditangani moderator
+	ReportClaimed   = "claimed"   // Sedang ditangani oleh seorang moderator
+	ReportResolved  = "resolved"  // Selesai dengan tindakan moderasi
+	ReportDismissed = "dismissed" // Ditolak karena tidak melanggar aturan
+)
+
+// Jenis tindakan moderasi.
+const (
+	ModerationHideContent    = "hide_content"    // Menyembunyikan foto atau komentar dari pengguna lain
+	ModerationUnhideContent  = "unhide_content"  // Menampilkan kembali konten yang disembunyikan
+	ModerationRemoveContent  = "remove_content"  // Menghapus foto atau komentar
+	ModerationSuspendUser    = "suspend_user"    // Menangguhkan akun pengguna
+	ModerationUnsuspendUser  = "unsuspend_user"  // Mencabut penangguhan akun pengguna
+	ModerationDismiss        = "dismiss"         // Menolak laporan tanpa tindakan
+	ModerationApproveComment = "approve_comment" // Mempublikasikan komentar yang ditahan filter konten
+	ModerationRejectComment  = "reject_comment"  // Menghapus komentar yang ditahan filter konten
+)
+
+// Report merupakan model untuk laporan pengguna atas foto, komentar, atau pengguna lain.
+type Report struct {
+	ID           int64      `gorm:"primaryKey"`
+	ReporterID   int64      `gorm:"not null;uniqueIndex:idx_reports_reporter_target"`                                  // ID pengguna yang melapor
+	Reporter     User       `gorm:"foreignKey:ReporterID;constraint:OnDelete:CASCADE"`                                 // Pengguna yang melapor
+	TargetType   string     `gorm:"size:20;not null;uniqueIndex:idx_reports_reporter_target;index:idx_reports_target"` // Jenis objek yang dilaporkan: photo, comment, atau user
+	TargetID     int64      `gorm:"not null;uniqueIndex:idx_reports_reporter_target;index:idx_reports_target"`         // ID objek yang dilaporkan
+	TargetUserID int64      `gorm:"not null;index"`                                                                    // ID pemilik konten atau pengguna yang dilaporkan
+	Reason       string     `gorm:"size:30;not null"`                                                                  // Kategori alasan laporan
+	Details      string     `gorm:"type:text"`                                                                         // Penjelasan tambahan dari pelapor
+	Status       string     `gorm:"size:20;not null;index"`                                                            // Status laporan
+	ClaimedByID  *int64     // ID moderator yang menangani laporan
+	ClaimedAt    *time.Time // Waktu laporan mulai ditangani
+	ResolvedAt   *time.Time // Waktu laporan selesai ditangani
+	CreatedAt    time.Time  // Waktu laporan dibuat
+	UpdatedAt    time.Time  // Waktu pembaruan terakhir laporan
+}
+
+// ErrModerationActionImmutable dikembalikan saat ada upaya mengubah atau menghapus catatan tindakan moderasi.
+var ErrModerationActionImmutable = errors.New("catatan tindakan moderasi tidak dapat diubah atau dihapus")
+
+// ModerationAction merupakan catatan permanen atas setiap keputusan moderator. Catatan ini tidak dapat diubah atau dihapus.
+type ModerationAction struct {
+	ID             int64      `gorm:"primaryKey"`
+	ReportID       *int64     `gorm:"index"`          // ID laporan yang menjadi dasar tindakan (opsional)
+	ModeratorID    int64      `gorm:"not null;index"` // ID moderator yang mengambil keputusan
+	Moderator      User       `gorm:"foreignKey:ModeratorID"`
+	Action         string     `gorm:"size:30;not null"`                                     // Jenis tindakan
+	TargetType     string     `gorm:"size:20;not null;index:idx_moderation_actions_target"` // Jenis objek yang dikenai tindakan
+	TargetID       int64      `gorm:"not null;index:idx_moderation_actions_target"`         // ID objek yang dikenai tindakan
+	TargetUserID   int64      `gorm:"not null;index"`                                       // ID pemilik konten atau pengguna yang dikenai tindakan
+	Note           string     `gorm:"type:text"`                                            // Catatan moderator
+	SuspendedUntil *time.Time // Akhir masa penangguhan untuk tindakan suspend_user
+	CreatedAt      time.Time  // Waktu tindakan diambil
+}
+
+// BeforeUpdate mencegah perubahan catatan tindakan moderasi.
+func (ModerationAction) BeforeUpdate(tx *gorm.DB) error {
+	return ErrModerationActionImmutable
+}
+
+// BeforeDelete mencegah penghapusan catatan tindakan moderasi.
+func (ModerationAction) BeforeDelete(tx *gorm.DB) error {
+	return ErrModerationActionImmutable
+}
+
+// CreateReportRequest adalah struktur data yang digunakan untuk membuat laporan.
+type CreateReportRequest struct {
+	TargetType string `json:"target_type" binding:"required"` // Jenis objek yang dilaporkan: photo, comment, atau user
+	TargetID   int64  `json:"target_id" binding:"required"`   // ID objek yang dilaporkan
+	Reason     string `json:"reason" binding:"required"`      // Kategori alasan laporan
+	Details    string `json:"details,omitempty"`              // Penjelasan tambahan (opsional)
+}
+
+// ModerationActionRequest adalah struktur data yang digunakan moderator untuk mengambil tindakan.
+type ModerationActionRequest struct {
+	Action      string `json:"action"`                 // Jenis tindakan
+	TargetType  string `json:"target_type,omitempty"`  // Jenis objek, hanya untuk tindakan tanpa laporan
+	TargetID    int64  `json:"target_id,omitempty"`    // ID objek, hanya untuk tindakan tanpa laporan
+	Note        string `json:"note,omitempty"`         // Catatan moderator
+	SuspendDays int    `json:"suspend_days,omitempty"` // Lama penangguhan dalam hari, 0 berarti tanpa batas waktu
+}
@@ -2,17 +2,21 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // SocialMedia adalah model untuk media sosial yang terkait dengan pengguna.
 type SocialMedia struct {
-	ID             int64     `gorm:"primaryKey"`         // ID media sosial
-	Name           string    `gorm:"size:50;not null"`   // Nama media sosial
-	SocialMediaURL string    `gorm:"type:text;not null"` // URL media sosial
-	UserID         int64     `gorm:"not null"`           // ID pengguna yang terkait dengan media sosial
-	User           User      `gorm:"foreignKey:UserID"`  // Pengguna yang terkait dengan media sosial
-	CreatedAt      time.Time // Waktu pembuatan entri media sosial
-	UpdatedAt      time.Time // Waktu pembaruan terakhir entri media sosial
+	ID             int64          `gorm:"primaryKey"`         // ID media sosial
+	Name           string         `gorm:"size:50;not null"`   // Nama media sosial
+	SocialMediaURL string         `gorm:"type:text;not null"` // URL media sosial
+	UserID         int64          `gorm:"not null"`           // ID pengguna yang terkait dengan media sosial
+	User           User           `gorm:"foreignKey:UserID"`  // Pengguna yang terkait dengan media sosial
+	Version        int64          `gorm:"not null;default:1"` // Versi entri untuk ETag
+	CreatedAt      time.Time      // Waktu pembuatan entri media sosial
+	UpdatedAt      time.Time      // Waktu pembaruan terakhir entri media sosial
+	DeletedAt      gorm.DeletedAt `gorm:"index"` // Waktu entri dipindahkan ke tempat sampah
 }
 
 // CreateSocialMediaRequest adalah struktur data yang digunakan untuk membuat entri media sosial baru.
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// Jenis media story.
+const (
+	StoryMediaImage = "image"
+	StoryMediaVideo = "video"
+)
+
+// Story merupakan model untuk story, yaitu postingan singkat yang otomatis hilang setelah masa berlakunya habis.
+type Story struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"not null;index"`                                // ID pengguna pemilik story
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Pengguna pemilik story
+	MediaURL  string    `gorm:"type:text;not null"`                            // URL media story
+	MediaType string    `gorm:"size:10;not null"`                              // Jenis media: image atau video
+	Caption   string    `gorm:"size:200"`                                      // Keterangan story (opsional)
+	ViewCount int64     `gorm:"not null;default:0"`                            // Jumlah pengguna yang sudah melihat story
+	ExpiresAt time.Time `gorm:"not null;index"`                                // Waktu story kedaluwarsa
+	CreatedAt time.Time // Waktu pembuatan story
+}
+
+// StoryView merupakan model untuk catatan pengguna yang sudah melihat sebuah story.
+type StoryView struct {
+	StoryID  int64     `gorm:"primaryKey"`
+	Story    Story     `gorm:"foreignKey:StoryID;constraint:OnDelete:CASCADE"`
+	ViewerID int64     `gorm:"primaryKey;index"`
+	Viewer   User      `gorm:"foreignKey:ViewerID;constraint:OnDelete:CASCADE"`
+	ViewedAt time.Time // Waktu pertama kali story dilihat
+}
+
+// CreateStoryRequest adalah struktur data yang digunakan untuk membuat story baru.
+type CreateStoryRequest struct {
+	MediaURL  string `json:"media_url" binding:"required"`        // URL media story (wajib diisi)
+	MediaType string `json:"media_type,omitempty"`                // Jenis media: image (default) atau video
+	Caption   string `json:"caption,omitempty" binding:"max=200"` // Keterangan story (opsional, maksimal 200 karakter)
+}
@@ -0,0 +1,7 @@
+package models
+
+import "time"
+
+// DefaultTrashRetention adalah lama konten yang dihapus disimpan di tempat sampah sebelum dihapus permanen,
+// jika tidak diatur melalui konfigurasi.
+const DefaultTrashRetention = 30 * 24 * time.Hour
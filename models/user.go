@@ -2,29 +2,115 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // User adalah model untuk pengguna dalam sistem.
 type User struct {
-	ID              int64         `gorm:"primaryKey"`         // ID pengguna
-	Username        string        `gorm:"size:50;not null"`   // Nama pengguna
-	Email           string        `gorm:"size:150;not null"`  // Email pengguna
-	Password        string        `gorm:"type:text;not null"` // Kata sandi pengguna
-	Age             int           `gorm:"not null"`           // Usia pengguna
-	ProfileImageURL string        `gorm:"type:text"`          // URL gambar profil pengguna
-	CreatedAt       time.Time     // Waktu pembuatan akun pengguna
-	UpdatedAt       time.Time     // Waktu pembaruan terakhir akun pengguna
-	Photos          []Photo       `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Foto-foto yang dimiliki oleh pengguna
-	Comments        []Comment     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Komentar yang dibuat oleh pengguna
-	SocialMedias    []SocialMedia `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Media sosial yang terkait dengan pengguna
+	ID                    int64          `gorm:"primaryKey"`                    // ID pengguna
+	Username              string         `gorm:"size:50;not null"`              // Nama pengguna
+	Email                 string         `gorm:"size:150;not null"`             // Email pengguna
+	Password              string         `gorm:"type:text;not null"`            // Kata sandi pengguna
+	Birthdate             *time.Time     `gorm:"type:date"`                     // Tanggal lahir pengguna, usia dihitung dari tanggal ini
+	BirthdateEstimated    bool           `gorm:"not null;default:false"`        // Tanggal lahir diperkirakan dari usia yang diisi saat mendaftar
+	ProfileImageURL       string         `gorm:"type:text"`                     // URL gambar profil pengguna
+	AvatarKey             string         `gorm:"size:100"`                      // Key dasar avatar yang diunggah di penyimpanan, kosong jika tidak ada
+	DisplayName           string         `gorm:"size:50"`                       // Nama tampilan
+	Bio                   string         `gorm:"type:text"`                     // Deskripsi singkat pengguna
+	Website               string         `gorm:"type:text"`                     // URL situs web pribadi
+	Pronouns              string         `gorm:"size:30"`                       // Kata ganti, misalnya "dia/nya"
+	Location              string         `gorm:"size:100"`                      // Lokasi
+	Role                  string         `gorm:"size:20;not null;default:user"` // Peran pengguna (user atau admin)
+	AllowMentions         bool           `gorm:"not null;default:true"`         // Pengguna lain boleh menyebut pengguna ini dengan @username
+	IsPrivate             bool           `gorm:"not null;default:false"`        // Foto dan media sosial hanya terlihat oleh pengikut
+	SuspendedAt           *time.Time     // Waktu akun ditangguhkan oleh moderator
+	SuspendedUntil        *time.Time     // Akhir masa penangguhan, kosong berarti tanpa batas waktu
+	DeletionScheduledFor  *time.Time     // Waktu akun dijadwalkan untuk dihapus permanen, dibatalkan saat pengguna login kembali
+	PasswordResetRequired bool           `gorm:"not null;default:false"` // Admin mewajibkan pengguna mengganti password sebelum dapat login
+	SessionsRevokedAt     *time.Time     // Token yang diterbitkan sebelum waktu ini tidak berlaku lagi
+	Version               int64          `gorm:"not null;default:1"` // Versi profil untuk ETag, naik setiap kali pengguna mengubah profilnya
+	CreatedAt             time.Time      // Waktu pembuatan akun pengguna
+	UpdatedAt             time.Time      // Waktu pembaruan terakhir akun pengguna
+	DeletedAt             gorm.DeletedAt `gorm:"index"`                                         // Waktu akun dihapus, akun dihapus permanen setelah masa retensi
+	Photos                []Photo        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Foto-foto yang dimiliki oleh pengguna
+	Comments              []Comment      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Komentar yang dibuat oleh pengguna
+	SocialMedias          []SocialMedia  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Media sosial yang terkait dengan pengguna
+}
+
+// Daftar peran pengguna yang dikenali sistem.
+const (
+	RoleUser      = "user"      // Pengguna biasa
+	RoleModerator = "moderator" // Moderator yang menangani laporan konten
+	RoleAdmin     = "admin"     // Administrator dengan akses penuh
+)
+
+// IsSuspended menentukan apakah akun pengguna sedang ditangguhkan pada waktu now.
+func (u User) IsSuspended(now time.Time) bool {
+	return u.SuspendedAt != nil && (u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil))
+}
+
+// IsDeletionScheduled menentukan apakah akun pengguna sedang menunggu penghapusan.
+func (u User) IsDeletionScheduled() bool {
+	return u.DeletionScheduledFor != nil
+}
+
+// AgeAt mengembalikan usia pengguna dalam tahun pada waktu now, atau false jika tanggal lahirnya tidak diketahui.
+func (u User) AgeAt(now time.Time) (int, bool) {
+	if u.Birthdate == nil {
+		return 0, false
+	}
+	birthdate := *u.Birthdate
+	age := now.Year() - birthdate.Year()
+	if now.Month() < birthdate.Month() || (now.Month() == birthdate.Month() && now.Day() < birthdate.Day()) {
+		age--
+	}
+	return age, true
+}
+
+// BirthdateText mengembalikan tanggal lahir pengguna dalam format BirthdateLayout, atau nil jika tidak diketahui.
+func (u User) BirthdateText() *string {
+	if u.Birthdate == nil {
+		return nil
+	}
+	text := u.Birthdate.Format(BirthdateLayout)
+	return &text
+}
+
+// IsModerator menentukan apakah pengguna dapat menangani laporan dan melihat konten yang disembunyikan.
+func (u User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
+// AgePolicy adalah aturan batas usia pengguna.
+type AgePolicy struct {
+	MinimumAge           int // Usia minimal untuk mendaftar
+	RestrictedContentAge int // Usia minimal untuk melihat foto yang ditandai berbatasan usia
 }
 
+// Batas usia default jika tidak diatur dari konfigurasi.
+const (
+	DefaultMinimumAge           = 13
+	DefaultRestrictedContentAge = 18
+)
+
+// BirthdateLayout adalah format tanggal lahir pada permintaan dan respons
+const BirthdateLayout = "2006-01-02"
+
+// Panjang maksimal field profil dalam karakter.
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 160
+	MaxPronounsLength    = 30
+	MaxLocationLength    = 100
+)
+
 // SignUpInput adalah struktur data yang digunakan saat mendaftar sebagai pengguna baru.
 type SignUpInput struct {
 	Username        string `json:"username" binding:"required"`            // Nama pengguna (wajib diisi)
 	Email           string `json:"email" binding:"required"`               // Email pengguna (wajib diisi)
 	Password        string `json:"password" binding:"required"`            // Kata sandi pengguna (wajib diisi)
-	Age             int    `json:"age" binding:"required"`                 // Usia pengguna (wajib diisi)
+	Birthdate       string `json:"birthdate" binding:"required"`           // Tanggal lahir pengguna dalam format YYYY-MM-DD (wajib diisi)
 	ProfileImageURL string `json:"profile_image_url" validate:"omitempty"` // URL gambar profil pengguna (opsional)
 }
 
@@ -38,6 +124,8 @@ type SignInInput struct {
 type UpdateCurrentUserRequest struct {
 	Username        string `json:"username" binding:"required"`                      // Nama pengguna yang diperbarui (wajib diisi)
 	Email           string `json:"email" binding:"required"`                         // Email pengguna yang diperbarui (wajib diisi)
-	Age             int    `json:"age" binding:"required"`                           // Usia pengguna yang diperbarui (wajib diisi)
+	Birthdate       string `json:"birthdate" binding:"required"`                     // Tanggal lahir pengguna dalam format YYYY-MM-DD (wajib diisi)
 	ProfileImageURL string `json:"profile_image_url,omitempty" validate:"omitempty"` // URL gambar profil pengguna yang diperbarui (opsional)
+	AllowMentions   *bool  `json:"allow_mentions,omitempty" validate:"omitempty"`    // Izinkan pengguna lain menyebut dengan @username (opsional)
+	IsPrivate       *bool  `json:"is_private,omitempty" validate:"omitempty"`        // Jadikan akun privat (opsional)
 }
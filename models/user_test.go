@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserAgeAt(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	now := date(2024, time.March, 15)
+
+	tests := []struct {
+		name      string
+		birthdate *time.Time
+		now       time.Time
+		want      int
+		wantKnown bool
+	}{
+		{"tanggal lahir tidak diketahui", nil, now, 0, false},
+		{"sudah berulang tahun", ptr(date(2000, time.January, 1)), now, 24, true},
+		{"tepat hari ulang tahun", ptr(date(2000, time.March, 15)), now, 24, true},
+		{"sehari sebelum ulang tahun", ptr(date(2000, time.March, 16)), now, 23, true},
+		{"bulan berikutnya", ptr(date(2000, time.April, 1)), now, 23, true},
+		{"lahir hari ini", ptr(now), now, 0, true},
+		{"29 Februari pada tahun bukan kabisat", ptr(date(2004, time.February, 29)), date(2023, time.February, 28), 18, true},
+		{"29 Februari setelah 28 Februari", ptr(date(2004, time.February, 29)), date(2023, time.March, 1), 19, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			age, known := User{Birthdate: tt.birthdate}.AgeAt(tt.now)
+			if age != tt.want || known != tt.wantKnown {
+				t.Errorf("AgeAt = %d, %v, ingin %d, %v", age, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
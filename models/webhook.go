@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+)
+
+// Jenis event yang dapat dikirim melalui webhook.
+const (
+	WebhookEventPhotoCreated   = "photo.created"   // Foto baru diunggah
+	WebhookEventCommentCreated = "comment.created" // Komentar baru dibuat
+	WebhookEventUserDeleted    = "user.deleted"    // Akun pengguna dihapus
+	WebhookEventPing           = "webhook.ping"    // Event uji coba dari endpoint ping
+)
+
+// WebhookEvents berisi semua event yang dapat dilanggan.
+var WebhookEvents = []string{
+	WebhookEventPhotoCreated,
+	WebhookEventCommentCreated,
+	WebhookEventUserDeleted,
+}
+
+// Cakupan langganan webhook.
+const (
+	WebhookScopeUser = "user" // Hanya menerima event yang terkait dengan pemilik langganan
+	WebhookScopeApp  = "app"  // Menerima semua event (hanya dapat dibuat oleh admin)
+)
+
+// Status pengiriman webhook.
+const (
+	WebhookDeliveryPending   = "pending"   // Menunggu dikirim atau dikirim ulang
+	WebhookDeliverySucceeded = "succeeded" // Berhasil diterima oleh penerima
+	WebhookDeliveryFailed    = "failed"    // Gagal setelah batas percobaan habis
+)
+
+// WebhookSubscription merupakan model untuk langganan webhook yang didaftarkan pengguna atau aplikasi.
+type WebhookSubscription struct {
+	ID        int64     `gorm:"primaryKey"`
+	UserID    int64     `gorm:"not null;index"`                                // ID pengguna yang mendaftarkan webhook
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"` // Pengguna yang mendaftarkan webhook
+	URL       string    `gorm:"type:text;not null"`                            // URL penerima webhook
+	Secret    string    `gorm:"size:64;not null"`                              // Kunci untuk tanda tangan HMAC payload
+	Events    string    `gorm:"type:text;not null"`                            // Daftar event yang dilanggan, dipisah koma
+	Scope     string    `gorm:"size:10;not null;default:user"`                 // Cakupan langganan (user atau app)
+	Active    bool      `gorm:"not null;default:true"`                         // Langganan aktif atau tidak
+	CreatedAt time.Time // Waktu pembuatan langganan
+	UpdatedAt time.Time // Waktu pembaruan terakhir langganan
+}
+
+// WebhookDelivery merupakan model untuk satu event yang harus dikirim ke sebuah langganan webhook.
+type WebhookDelivery struct {
+	ID             int64               `gorm:"primaryKey"`
+	SubscriptionID int64               `gorm:"not null;index;uniqueIndex:idx_webhook_deliveries_source"`
+	Subscription   WebhookSubscription `gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE"`
+	OutboxEventID  *int64              `gorm:"uniqueIndex:idx_webhook_deliveries_source"`         // ID domain event asal, mencegah pengiriman ganda saat event diproses ulang
+	Event          string              `gorm:"size:50;not null"`                                  // Nama event
+	Payload        string              `gorm:"type:text;not null"`                                // Isi payload JSON yang dikirim
+	Status         string              `gorm:"size:20;not null;index:idx_webhook_deliveries_due"` // Status pengiriman
+	Attempts       int                 `gorm:"not null;default:0"`                                // Jumlah percobaan pengiriman
+	NextAttemptAt  time.Time           `gorm:"not null;index:idx_webhook_deliveries_due"`         // Waktu percobaan berikutnya
+	LastError      string              `gorm:"type:text"`                                         // Pesan error percobaan terakhir
+	DeliveredAt    *time.Time          // Waktu berhasil dikirim
+	CreatedAt      time.Time           // Waktu event dibuat
+	UpdatedAt      time.Time           // Waktu pembaruan terakhir status
+}
+
+// WebhookDeliveryAttempt merupakan catatan satu kali percobaan pengiriman webhook.
+type WebhookDeliveryAttempt struct {
+	ID             int64           `gorm:"primaryKey"`
+	DeliveryID     int64           `gorm:"not null;index"`
+	Delivery       WebhookDelivery `gorm:"foreignKey:DeliveryID;constraint:OnDelete:CASCADE"`
+	ResponseStatus int             // Kode status HTTP dari penerima, 0 jika tidak ada respons
+	Error          string          `gorm:"type:text"` // Pesan error jika percobaan gagal
+	DurationMs     int64           // Lama percobaan dalam milidetik
+	CreatedAt      time.Time       // Waktu percobaan
+}
+
+// CreateWebhookRequest adalah struktur data yang digunakan untuk mendaftarkan webhook baru.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`    // URL penerima webhook (wajib diisi)
+	Events []string `json:"events" binding:"required"` // Daftar event yang dilanggan (wajib diisi)
+	Scope  string   `json:"scope,omitempty"`           // Cakupan langganan, default user (opsional)
+}
+
+// UpdateWebhookRequest adalah struktur data yang digunakan untuk memperbarui webhook.
+type UpdateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`    // URL penerima webhook (wajib diisi)
+	Events []string `json:"events" binding:"required"` // Daftar event yang dilanggan (wajib diisi)
+	Active *bool    `json:"active,omitempty"`          // Status aktif langganan (opsional)
+}
@@ -0,0 +1,71 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/events"
+	"mygram-final-project/models"
+)
+
+// eventData adalah field domain event yang dibutuhkan untuk membuat notifikasi
+type eventData struct {
+	PhotoID       int64  `json:"photo_id"`
+	PhotoUserID   int64  `json:"photo_user_id"`
+	ParentID      *int64 `json:"parent_id"`
+	ParentUserID  int64  `json:"parent_user_id"`
+	CommentID     int64  `json:"comment_id"`
+	CommentUserID int64  `json:"comment_user_id"`
+	SourceType    string `json:"source_type"`
+	SourceID      int64  `json:"source_id"`
+}
+
+// Register mendaftarkan handler yang mengubah domain event menjadi notifikasi.
+func Register(dispatcher *events.Dispatcher, db *gorm.DB) {
+	dispatcher.Register("notifications", func(ctx context.Context, event models.OutboxEvent) error {
+		return handle(db.WithContext(ctx), event)
+	}, events.CommentCreated, events.PhotoLiked, events.CommentLiked, events.UserFollowed,
+		events.FollowRequested, events.FollowApproved, events.UserMentioned)
+}
+
+// handle membuat notifikasi untuk satu domain event
+func handle(db *gorm.DB, event models.OutboxEvent) error {
+	var actor models.User
+	if err := db.First(&actor, event.ActorID).Error; err != nil {
+		// Aktor yang sudah dihapus tidak lagi memicu notifikasi
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var data eventData
+	if err := events.Decode(event, &data); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		switch event.Type {
+		case events.CommentCreated:
+			if data.ParentID != nil {
+				return Notify(tx, data.ParentUserID, actor, models.NotificationTypeReply, models.TargetComment, *data.ParentID)
+			}
+			return Notify(tx, data.PhotoUserID, actor, models.NotificationTypeComment, models.TargetPhoto, data.PhotoID)
+		case events.PhotoLiked:
+			return Notify(tx, data.PhotoUserID, actor, models.NotificationTypeLikePhoto, models.TargetPhoto, data.PhotoID)
+		case events.CommentLiked:
+			return Notify(tx, data.CommentUserID, actor, models.NotificationTypeLikeComment, models.TargetComment, data.CommentID)
+		case events.UserFollowed:
+			return Notify(tx, event.AggregateID, actor, models.NotificationTypeFollow, models.TargetUser, event.AggregateID)
+		case events.FollowRequested:
+			return Notify(tx, event.AggregateID, actor, models.NotificationTypeFollowRequest, models.TargetUser, event.AggregateID)
+		case events.FollowApproved:
+			return Notify(tx, event.AggregateID, actor, models.NotificationTypeFollowAccepted, models.TargetUser, actor.ID)
+		case events.UserMentioned:
+			return Notify(tx, event.AggregateID, actor, models.NotificationTypeMention, data.SourceType, data.SourceID)
+		}
+		return nil
+	})
+}
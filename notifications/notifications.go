@@ -0,0 +1,152 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/models"
+	"mygram-final-project/realtime"
+)
+
+// Notify membuat notifikasi untuk recipientID atas tindakan actor, atau menggabungkannya ke notifikasi
+// sejenis yang belum dibaca. Notifikasi tidak dibuat untuk tindakan pada diri sendiri, jenis yang dinonaktifkan penerima,
+// atau actor yang diblokir maupun dibisukan oleh penerima.
+func Notify(tx *gorm.DB, recipientID int64, actor models.User, notificationType string, targetType string, targetID int64) error {
+	if recipientID == actor.ID {
+		return nil
+	}
+
+	// Penerima yang sudah dihapus tidak lagi menerima notifikasi
+	var recipients int64
+	if err := tx.Model(&models.User{}).Where("id = ?", recipientID).Count(&recipients).Error; err != nil {
+		return err
+	}
+	if recipients == 0 {
+		return nil
+	}
+
+	var disabled int64
+	tx.Model(&models.NotificationPreference{}).
+		Where("user_id = ? AND type = ? AND enabled = ?", recipientID, notificationType, false).
+		Count(&disabled)
+	if disabled > 0 {
+		return nil
+	}
+
+	var hidden int64
+	tx.Raw(`
+		SELECT (SELECT COUNT(*) FROM blocks WHERE (blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?))
+		     + (SELECT COUNT(*) FROM mutes WHERE muter_id = ? AND muted_id = ?)`,
+		recipientID, actor.ID, actor.ID, recipientID, recipientID, actor.ID).Scan(&hidden)
+	if hidden > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	groupKey := fmt.Sprintf("%s:%s:%d", notificationType, targetType, targetID)
+
+	// Notifikasi dibuat atau digabung dengan satu upsert pada index unik notifikasi belum dibaca, sehingga kejadian
+	// bersamaan tidak pernah membuat dua notifikasi untuk GroupKey yang sama. Notifikasi baru dimulai dengan actor_count 0
+	// lalu dihitung bersama actor lain di bawah.
+	var notificationID int64
+	err := tx.Raw(`
+		INSERT INTO notifications (user_id, actor_id, actor_count, type, target_type, target_id, group_key, created_at, updated_at)
+		VALUES (?, ?, 0, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, group_key) WHERE read_at IS NULL
+		DO UPDATE SET actor_id = EXCLUDED.actor_id, updated_at = EXCLUDED.updated_at
+		RETURNING id`, recipientID, actor.ID, notificationType, targetType, targetID, groupKey, now, now).Scan(&notificationID).Error
+	if err != nil {
+		return err
+	}
+
+	// Pengguna yang sama hanya dihitung sekali dalam satu notifikasi gabungan
+	actorRow := models.NotificationActor{NotificationID: notificationID, ActorID: actor.ID, CreatedAt: now}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&actorRow)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		if err := tx.Model(&models.Notification{}).Where("id = ?", notificationID).
+			UpdateColumn("actor_count", gorm.Expr("actor_count + 1")).Error; err != nil {
+			return err
+		}
+	}
+	return publishNotification(tx, notificationID, recipientID, actor, notificationType, targetType, targetID)
+}
+
+// NotifySelf membuat notifikasi sistem untuk user atas kejadian pada akunnya sendiri, misalnya arsip ekspor data yang sudah siap.
+// Notifikasi ini tidak digabung, tidak dapat dinonaktifkan, dan mencatat user sebagai actor-nya.
+func NotifySelf(tx *gorm.DB, user models.User, notificationType string, targetType string, targetID int64) error {
+	now := time.Now()
+	notification := models.Notification{
+		UserID:     user.ID,
+		ActorID:    user.ID,
+		ActorCount: 1,
+		Type:       notificationType,
+		TargetType: targetType,
+		TargetID:   targetID,
+		GroupKey:   fmt.Sprintf("%s:%s:%d", notificationType, targetType, targetID),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	// Notifikasi yang sama yang belum dibaca tidak dibuat dua kali
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&notification)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return publishNotification(tx, notification.ID, user.ID, user, notificationType, targetType, targetID)
+}
+
+// publishNotification mengirim notifikasi ke stream real-time penerima setelah transaksi di-commit
+func publishNotification(tx *gorm.DB, notificationID int64, recipientID int64, actor models.User, notificationType string, targetType string, targetID int64) error {
+	return realtime.Publish(tx, realtime.Event{
+		Type:   realtime.EventNotification,
+		UserID: recipientID,
+		Data: map[string]interface{}{
+			"id":          notificationID,
+			"type":        notificationType,
+			"target_type": targetType,
+			"target_id":   targetID,
+			"actor": map[string]interface{}{
+				"id":       actor.ID,
+				"username": actor.Username,
+			},
+		},
+	})
+}
+
+// Text membuat kalimat notifikasi yang siap ditampilkan, misalnya "budi dan 4 orang lainnya menyukai foto kamu"
+func Text(notification models.Notification) string {
+	actor := notification.Actor.Username
+	if notification.ActorCount > 1 {
+		actor = fmt.Sprintf("%s dan %d orang lainnya", actor, notification.ActorCount-1)
+	}
+
+	switch notification.Type {
+	case models.NotificationTypeMention:
+		if notification.TargetType == models.MentionSourcePhoto {
+			return actor + " menyebut kamu di sebuah foto"
+		}
+		return actor + " menyebut kamu di sebuah komentar"
+	case models.NotificationTypeComment:
+		return actor + " mengomentari foto kamu"
+	case models.NotificationTypeReply:
+		return actor + " membalas komentar kamu"
+	case models.NotificationTypeLikePhoto:
+		return actor + " menyukai foto kamu"
+	case models.NotificationTypeLikeComment:
+		return actor + " menyukai komentar kamu"
+	case models.NotificationTypeFollow:
+		return actor + " mulai mengikuti kamu"
+	case models.NotificationTypeFollowRequest:
+		return actor + " ingin mengikuti kamu"
+	case models.NotificationTypeFollowAccepted:
+		return actor + " menerima permintaan mengikuti kamu"
+	case models.NotificationTypeDataExport:
+		return "Arsip data kamu siap diunduh"
+	}
+	return actor
+}
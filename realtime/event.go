@@ -0,0 +1,41 @@
+package realtime
+
+import (
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// Channel adalah nama channel PostgreSQL LISTEN/NOTIFY yang dipakai untuk menyebarkan event antar instance server.
+const Channel = "mygram_events"
+
+// Jenis event yang dikirim melalui stream.
+const (
+	EventCommentCreated = "comment.created" // Komentar baru pada foto yang sedang dilihat
+	EventPhotoLiked     = "photo.liked"     // Foto yang sedang dilihat di-like
+	EventPhotoUnliked   = "photo.unliked"   // Like pada foto yang sedang dilihat dihapus
+	EventCommentLiked   = "comment.liked"   // Komentar pada foto yang sedang dilihat di-like
+	EventNotification   = "notification"    // Notifikasi baru untuk pengguna
+	EventMessageCreated = "message.created" // Pesan baru pada percakapan pengguna
+	EventMessageRead    = "message.read"    // Anggota percakapan membaca pesan
+)
+
+// Event adalah satu kejadian yang dikirim ke klien melalui stream.
+// Event dengan UserID hanya dikirim ke pengguna tersebut, sedangkan event dengan PhotoID
+// dikirim ke semua pengguna yang sedang melihat foto tersebut.
+type Event struct {
+	Type    string      `json:"type"`
+	UserID  int64       `json:"user_id,omitempty"`
+	PhotoID int64       `json:"photo_id,omitempty"`
+	Data    interface{} `json:"data"`
+}
+
+// Publish mengirim event ke semua instance server melalui pg_notify.
+// Jika dipanggil di dalam transaksi, event baru terkirim setelah transaksi berhasil di-commit.
+func Publish(tx *gorm.DB, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return tx.Exec("SELECT pg_notify(?, ?)", Channel, string(payload)).Error
+}
@@ -0,0 +1,81 @@
+package realtime
+
+import (
+	"sync"
+)
+
+// subscriberBuffer adalah jumlah event yang dapat mengantre untuk satu subscriber sebelum event baru dibuang
+const subscriberBuffer = 32
+
+// Subscriber adalah satu koneksi stream milik pengguna yang sedang terhubung.
+type Subscriber struct {
+	UserID int64          // ID pengguna pemilik koneksi
+	Events chan Event     // Antrean event yang akan dikirim ke klien
+	photos map[int64]bool // Foto yang sedang dilihat oleh klien
+}
+
+// Hub menyimpan semua subscriber pada instance server ini dan meneruskan event ke subscriber yang berhak.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+}
+
+// NewHub membuat instance baru dari Hub.
+func NewHub() *Hub {
+	return &Hub{subscribers: map[*Subscriber]struct{}{}}
+}
+
+// Subscribe mendaftarkan koneksi baru untuk userID yang tertarik pada event foto-foto photoIDs.
+func (h *Hub) Subscribe(userID int64, photoIDs []int64) *Subscriber {
+	subscriber := &Subscriber{
+		UserID: userID,
+		Events: make(chan Event, subscriberBuffer),
+		photos: map[int64]bool{},
+	}
+	for _, id := range photoIDs {
+		subscriber.photos[id] = true
+	}
+
+	h.mu.Lock()
+	h.subscribers[subscriber] = struct{}{}
+	h.mu.Unlock()
+	return subscriber
+}
+
+// Unsubscribe menghapus koneksi dari Hub dan menutup antrean event-nya.
+func (h *Hub) Unsubscribe(subscriber *Subscriber) {
+	h.mu.Lock()
+	if _, ok := h.subscribers[subscriber]; ok {
+		delete(h.subscribers, subscriber)
+		close(subscriber.Events)
+	}
+	h.mu.Unlock()
+}
+
+// Dispatch meneruskan event ke semua subscriber lokal yang berhak menerimanya.
+// Event untuk subscriber yang antreannya penuh dibuang agar klien lambat tidak menahan klien lain.
+func (h *Hub) Dispatch(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for subscriber := range h.subscribers {
+		if !subscriber.wants(event) {
+			continue
+		}
+		select {
+		case subscriber.Events <- event:
+		default:
+		}
+	}
+}
+
+// wants menentukan apakah event ditujukan untuk subscriber ini
+func (s *Subscriber) wants(event Event) bool {
+	if event.UserID != 0 {
+		return event.UserID == s.UserID
+	}
+	if event.PhotoID != 0 {
+		return s.photos[event.PhotoID]
+	}
+	return false
+}
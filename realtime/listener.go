@@ -0,0 +1,59 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Listen menerima event dari channel PostgreSQL dan meneruskannya ke hub sampai ctx dibatalkan.
+// Koneksi yang terputus akan disambung ulang secara otomatis.
+func Listen(ctx context.Context, dsn string, hub *Hub) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := listenOnce(ctx, dsn, hub)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("realtime: listener terputus: %v, mencoba lagi dalam %s", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// listenOnce membuka satu koneksi LISTEN dan memproses notifikasi sampai terjadi error
+func listenOnce(ctx context.Context, dsn string, hub *Hub) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("realtime: payload tidak valid: %v", err)
+			continue
+		}
+		hub.Dispatch(event)
+	}
+}
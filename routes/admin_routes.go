@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+	"mygram-final-project/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRouteController mengelola rute yang terkait dengan pengelolaan pengguna dan konten oleh admin.
+type AdminRouteController struct {
+	adminController controllers.AdminController // Kontroler untuk admin
+}
+
+// NewRouteAdminController membuat instance baru dari AdminRouteController.
+func NewRouteAdminController(adminController controllers.AdminController) AdminRouteController {
+	return AdminRouteController{adminController}
+}
+
+// AdminRoute menentukan rute yang terkait dengan pengelolaan pengguna dan konten. Semua rute hanya dapat diakses oleh admin.
+func (ac *AdminRouteController) AdminRoute(rg *gin.RouterGroup) {
+	router := rg.Group("admin")
+	router.Use(middleware.UserExtractor(), middleware.RequireRole(models.RoleAdmin))
+
+	router.GET("/users", ac.adminController.SearchUsers)                                        // Rute untuk mencari pengguna
+	router.POST("/users/:username/suspend", ac.adminController.SuspendUser)                     // Rute untuk menangguhkan akun pengguna
+	router.POST("/users/:username/unsuspend", ac.adminController.UnsuspendUser)                 // Rute untuk memulihkan akun pengguna
+	router.POST("/users/:username/force-password-reset", ac.adminController.ForcePasswordReset) // Rute untuk mewajibkan reset password
+	router.POST("/users/:username/impersonate", ac.adminController.Impersonate)                 // Rute untuk memulai sesi sebagai pengguna lain
+	router.POST("/content/remove", ac.adminController.BulkRemoveContent)                        // Rute untuk menghapus banyak konten sekaligus
+	router.GET("/stats", ac.adminController.GetStats)                                           // Rute untuk mendapatkan statistik situs
+}
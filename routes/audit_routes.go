@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+	"mygram-final-project/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditRouteController mengelola rute yang terkait dengan audit log.
+type AuditRouteController struct {
+	auditController controllers.AuditController // Kontroler untuk audit log
+}
+
+// NewRouteAuditController membuat instance baru dari AuditRouteController.
+func NewRouteAuditController(auditController controllers.AuditController) AuditRouteController {
+	return AuditRouteController{auditController}
+}
+
+// AuditRoute menentukan rute yang terkait dengan audit log. Audit log hanya dapat dibaca oleh admin.
+func (ac *AuditRouteController) AuditRoute(rg *gin.RouterGroup) {
+	router := rg.Group("admin")
+	router.Use(middleware.UserExtractor(), middleware.RequireRole(models.RoleAdmin))
+
+	router.GET("/audit-logs", ac.auditController.GetAuditLogs) // Rute untuk mencari catatan audit log
+}
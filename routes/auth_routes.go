@@ -23,4 +23,5 @@ func (rc *AuthRouteController) AuthRoute(rg *gin.RouterGroup) {
 
 	router.POST("/register", rc.authController.SignUpUser)
 	router.POST("/login", rc.authController.SignInUser)
+	router.POST("/password/reset", rc.authController.ResetPassword)
 }
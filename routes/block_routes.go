@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BlockRouteController mengelola rute yang terkait dengan memblokir dan membisukan pengguna.
+type BlockRouteController struct {
+	blockController controllers.BlockController // Kontroler untuk blokir dan bisukan
+}
+
+// NewRouteBlockController membuat instance baru dari BlockRouteController.
+func NewRouteBlockController(blockController controllers.BlockController) BlockRouteController {
+	return BlockRouteController{blockController}
+}
+
+// BlockRoute menentukan rute yang terkait dengan memblokir dan membisukan pengguna.
+func (bc *BlockRouteController) BlockRoute(rg *gin.RouterGroup) {
+	router := rg.Group("users")
+	router.Use(middleware.UserExtractor())
+
+	router.GET("/me/blocked", bc.blockController.GetBlocked)      // Rute untuk mendapatkan daftar pengguna yang diblokir
+	router.GET("/me/muted", bc.blockController.GetMuted)          // Rute untuk mendapatkan daftar pengguna yang dibisukan
+	router.POST("/:username/block", bc.blockController.Block)     // Rute untuk memblokir pengguna
+	router.DELETE("/:username/block", bc.blockController.Unblock) // Rute untuk membuka blokir pengguna
+	router.POST("/:username/mute", bc.blockController.Mute)       // Rute untuk membisukan pengguna
+	router.DELETE("/:username/mute", bc.blockController.Unmute)   // Rute untuk berhenti membisukan pengguna
+}
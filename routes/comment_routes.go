@@ -3,6 +3,7 @@ package routes
 import (
 	"mygram-final-project/controllers"
 	"mygram-final-project/middleware"
+	"mygram-final-project/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,9 +23,20 @@ func (cc *CommentRouteController) CommentRoute(rg *gin.RouterGroup) {
 	router := rg.Group("comments")
 	router.Use(middleware.UserExtractor())
 
-	router.POST("", cc.commentController.CreateComment)              // Rute untuk membuat komentar
-	router.GET("", cc.commentController.GetComment)                  // Rute untuk mendapatkan semua komentar
-	router.PUT("/:commentId", cc.commentController.UpdateComment)    // Rute untuk memperbarui komentar berdasarkan ID
-	router.GET("/:commentId", cc.commentController.GetCommentByID)   // Rute untuk mendapatkan komentar berdasarkan ID
-	router.DELETE("/:commentId", cc.commentController.DeleteComment) // Rute untuk menghapus komentar berdasarkan ID
+	router.POST("", cc.commentController.CreateComment)                                       // Rute untuk membuat komentar
+	router.GET("", middleware.RequireRole(models.RoleAdmin), cc.commentController.GetComment) // Rute untuk mendapatkan semua komentar (khusus admin)
+	router.PUT("/:commentId", cc.commentController.UpdateComment)                             // Rute untuk memperbarui komentar berdasarkan ID
+	router.PATCH("/:commentId", cc.commentController.PatchComment)                            // Rute untuk memperbarui komentar dengan merge patch berdasarkan ID
+	router.GET("/:commentId", cc.commentController.GetCommentByID)                            // Rute untuk mendapatkan komentar berdasarkan ID
+	router.DELETE("/:commentId", cc.commentController.DeleteComment)                          // Rute untuk menghapus komentar berdasarkan ID
+	router.GET("/:commentId/replies", cc.commentController.GetReplies)                        // Rute untuk mendapatkan balasan dari komentar
+	router.POST("/:commentId/hide", cc.commentController.HideComment)                         // Rute untuk menyembunyikan komentar pada foto milik sendiri
+	router.DELETE("/:commentId/hide", cc.commentController.UnhideComment)                     // Rute untuk menampilkan kembali komentar yang disembunyikan
+	router.POST("/:commentId/pin", cc.commentController.PinComment)                           // Rute untuk menyematkan komentar pada foto milik sendiri
+	router.DELETE("/:commentId/pin", cc.commentController.UnpinComment)                       // Rute untuk melepas komentar yang disematkan
+
+	photos := rg.Group("photos")
+	photos.Use(middleware.UserExtractor())
+
+	photos.GET("/:photoId/comments", cc.commentController.GetPhotoComments) // Rute untuk mendapatkan komentar pada foto
 }
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConversationRouteController mengelola rute yang terkait dengan pesan pribadi.
+type ConversationRouteController struct {
+	conversationController controllers.ConversationController // Kontroler untuk percakapan
+}
+
+// NewRouteConversationController membuat instance baru dari ConversationRouteController.
+func NewRouteConversationController(conversationController controllers.ConversationController) ConversationRouteController {
+	return ConversationRouteController{conversationController}
+}
+
+// ConversationRoute menentukan rute yang terkait dengan pesan pribadi.
+func (cc *ConversationRouteController) ConversationRoute(rg *gin.RouterGroup) {
+	router := rg.Group("conversations")
+	router.Use(middleware.UserExtractor())
+
+	router.POST("", middleware.DenyImpersonation(), cc.conversationController.CreateConversation)                   // Rute untuk membuat percakapan baru
+	router.GET("", cc.conversationController.GetConversations)                                                      // Rute untuk mendapatkan daftar percakapan
+	router.GET("/:conversationId", cc.conversationController.GetConversationByID)                                   // Rute untuk mendapatkan detail percakapan
+	router.GET("/:conversationId/messages", cc.conversationController.GetMessages)                                  // Rute untuk mendapatkan riwayat pesan
+	router.POST("/:conversationId/messages", middleware.DenyImpersonation(), cc.conversationController.SendMessage) // Rute untuk mengirim pesan
+	router.POST("/:conversationId/read", cc.conversationController.MarkAsRead)                                      // Rute untuk menandai percakapan sudah dibaca
+}
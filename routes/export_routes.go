@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportRouteController mengelola rute yang terkait dengan ekspor data pribadi pengguna.
+type ExportRouteController struct {
+	exportController controllers.ExportController // Kontroler untuk ekspor data
+}
+
+// NewRouteExportController membuat instance baru dari ExportRouteController.
+func NewRouteExportController(exportController controllers.ExportController) ExportRouteController {
+	return ExportRouteController{exportController}
+}
+
+// ExportRoute menentukan rute yang terkait dengan ekspor data pribadi pengguna.
+func (ec *ExportRouteController) ExportRoute(rg *gin.RouterGroup) {
+	users := rg.Group("users")
+	users.Use(middleware.UserExtractor(), middleware.DenyImpersonation())
+
+	users.POST("/me/exports", ec.exportController.RequestExport) // Rute untuk meminta arsip data pribadi
+	users.GET("/me/exports", ec.exportController.GetExports)     // Rute untuk mendapatkan daftar permintaan ekspor
+
+	// Tautan unduhan diamankan dengan tanda tangan sehingga tidak memerlukan login
+	exports := rg.Group("exports")
+	exports.GET("/:exportId/download", ec.exportController.DownloadExport) // Rute untuk mengunduh arsip data pribadi
+}
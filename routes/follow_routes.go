@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FollowRouteController mengelola rute yang terkait dengan mengikuti pengguna.
+type FollowRouteController struct {
+	followController controllers.FollowController // Kontroler untuk follow
+}
+
+// NewRouteFollowController membuat instance baru dari FollowRouteController.
+func NewRouteFollowController(followController controllers.FollowController) FollowRouteController {
+	return FollowRouteController{followController}
+}
+
+// FollowRoute menentukan rute yang terkait dengan mengikuti pengguna.
+func (fc *FollowRouteController) FollowRoute(rg *gin.RouterGroup) {
+	router := rg.Group("users")
+	router.Use(middleware.UserExtractor())
+
+	router.POST("/:username/follow", fc.followController.Follow)         // Rute untuk mengikuti pengguna
+	router.DELETE("/:username/follow", fc.followController.Unfollow)     // Rute untuk berhenti mengikuti pengguna
+	router.GET("/:username/followers", fc.followController.GetFollowers) // Rute untuk mendapatkan daftar pengikut pengguna
+	router.GET("/:username/following", fc.followController.GetFollowing) // Rute untuk mendapatkan daftar pengguna yang diikuti
+
+	// Rute untuk mengelola permintaan mengikuti akun privat milik pengguna saat ini
+	router.GET("/me/follow-requests", fc.followController.GetFollowRequests)
+	router.POST("/me/follow-requests/:username/approve", fc.followController.ApproveFollowRequest)
+	router.DELETE("/me/follow-requests/:username", fc.followController.DeclineFollowRequest)
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LikeRouteController mengelola rute yang terkait dengan like.
+type LikeRouteController struct {
+	likeController controllers.LikeController // Kontroler untuk like
+}
+
+// NewRouteLikeController membuat instance baru dari LikeRouteController.
+func NewRouteLikeController(likeController controllers.LikeController) LikeRouteController {
+	return LikeRouteController{likeController}
+}
+
+// LikeRoute menentukan rute yang terkait dengan like pada foto dan komentar.
+func (lc *LikeRouteController) LikeRoute(rg *gin.RouterGroup) {
+	photos := rg.Group("photos")
+	photos.Use(middleware.UserExtractor())
+
+	photos.POST("/:photoId/likes", lc.likeController.LikePhoto)     // Rute untuk memberi like pada foto
+	photos.DELETE("/:photoId/likes", lc.likeController.UnlikePhoto) // Rute untuk menghapus like pada foto
+	photos.GET("/:photoId/likes", lc.likeController.GetPhotoLikers) // Rute untuk mendapatkan daftar pengguna yang memberi like pada foto
+
+	comments := rg.Group("comments")
+	comments.Use(middleware.UserExtractor())
+
+	comments.POST("/:commentId/likes", lc.likeController.LikeComment)     // Rute untuk memberi like pada komentar
+	comments.DELETE("/:commentId/likes", lc.likeController.UnlikeComment) // Rute untuk menghapus like pada komentar
+	comments.GET("/:commentId/likes", lc.likeController.GetCommentLikers) // Rute untuk mendapatkan daftar pengguna yang memberi like pada komentar
+}
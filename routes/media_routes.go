@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MediaRouteController mengelola rute untuk berkas media publik.
+type MediaRouteController struct {
+	mediaController controllers.MediaController // Kontroler untuk berkas media
+}
+
+// NewRouteMediaController membuat instance baru dari MediaRouteController.
+func NewRouteMediaController(mediaController controllers.MediaController) MediaRouteController {
+	return MediaRouteController{mediaController}
+}
+
+// MediaRoute menentukan rute untuk berkas media publik. Rute ini tidak memerlukan login agar avatar dapat ditampilkan langsung.
+func (mc *MediaRouteController) MediaRoute(rg *gin.RouterGroup) {
+	router := rg.Group("media")
+
+	router.GET("/*key", mc.mediaController.GetMedia) // Rute untuk mendapatkan berkas media, misalnya avatar
+}
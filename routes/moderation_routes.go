@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+	"mygram-final-project/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModerationRouteController mengelola rute yang terkait dengan laporan dan moderasi konten.
+type ModerationRouteController struct {
+	moderationController controllers.ModerationController // Kontroler untuk moderasi
+}
+
+// NewRouteModerationController membuat instance baru dari ModerationRouteController.
+func NewRouteModerationController(moderationController controllers.ModerationController) ModerationRouteController {
+	return ModerationRouteController{moderationController}
+}
+
+// ModerationRoute menentukan rute yang terkait dengan laporan dan moderasi konten.
+func (mc *ModerationRouteController) ModerationRoute(rg *gin.RouterGroup) {
+	reports := rg.Group("reports")
+	reports.Use(middleware.UserExtractor())
+
+	reports.POST("", mc.moderationController.CreateReport) // Rute untuk melaporkan foto, komentar, atau pengguna
+
+	router := rg.Group("moderation")
+	router.Use(middleware.UserExtractor(), middleware.RequireRole(models.RoleModerator, models.RoleAdmin))
+
+	router.GET("/reports", mc.moderationController.GetReports)                       // Rute untuk mendapatkan antrean laporan
+	router.GET("/reports/:reportId", mc.moderationController.GetReportByID)          // Rute untuk mendapatkan detail laporan
+	router.POST("/reports/:reportId/claim", mc.moderationController.ClaimReport)     // Rute untuk mengklaim laporan
+	router.POST("/reports/:reportId/resolve", mc.moderationController.ResolveReport) // Rute untuk menyelesaikan laporan dengan tindakan
+	router.POST("/reports/:reportId/dismiss", mc.moderationController.DismissReport) // Rute untuk menolak laporan
+	router.GET("/comments/pending", mc.moderationController.GetPendingComments)      // Rute untuk mendapatkan komentar yang ditahan filter konten
+	router.GET("/actions", mc.moderationController.GetActions)                       // Rute untuk mendapatkan catatan tindakan moderasi
+	router.POST("/actions", mc.moderationController.CreateAction)                    // Rute untuk mengambil tindakan tanpa laporan
+}
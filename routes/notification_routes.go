@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationRouteController mengelola rute yang terkait dengan notifikasi.
+type NotificationRouteController struct {
+	notificationController controllers.NotificationController // Kontroler untuk notifikasi
+}
+
+// NewRouteNotificationController membuat instance baru dari NotificationRouteController.
+func NewRouteNotificationController(notificationController controllers.NotificationController) NotificationRouteController {
+	return NotificationRouteController{notificationController}
+}
+
+// NotificationRoute menentukan rute yang terkait dengan notifikasi.
+func (nc *NotificationRouteController) NotificationRoute(rg *gin.RouterGroup) {
+	router := rg.Group("notifications")
+	router.Use(middleware.UserExtractor())
+
+	router.GET("", nc.notificationController.GetNotifications)                 // Rute untuk mendapatkan daftar notifikasi
+	router.GET("/unread-count", nc.notificationController.GetUnreadCount)      // Rute untuk mendapatkan jumlah notifikasi yang belum dibaca
+	router.POST("/read-all", nc.notificationController.MarkAllAsRead)          // Rute untuk menandai semua notifikasi sebagai sudah dibaca
+	router.POST("/:notificationId/read", nc.notificationController.MarkAsRead) // Rute untuk menandai notifikasi sebagai sudah dibaca
+	router.GET("/preferences", nc.notificationController.GetPreferences)       // Rute untuk mendapatkan preferensi notifikasi
+	router.PUT("/preferences", nc.notificationController.UpdatePreferences)    // Rute untuk memperbarui preferensi notifikasi
+}
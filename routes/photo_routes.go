@@ -25,6 +25,7 @@ func (pc *PhotoRouteController) PhotoRoute(rg *gin.RouterGroup) {
 	router.POST("", pc.photoController.CreatePhoto)            // Rute untuk membuat foto baru
 	router.GET("", pc.photoController.FindPhotos)              // Rute untuk menemukan semua foto
 	router.PUT("/:photoId", pc.photoController.UpdatePhoto)    // Rute untuk memperbarui foto berdasarkan ID
+	router.PATCH("/:photoId", pc.photoController.PatchPhoto)   // Rute untuk memperbarui sebagian field foto berdasarkan ID
 	router.GET("/:photoId", pc.photoController.FindPhotoByID)  // Rute untuk menemukan foto berdasarkan ID
 	router.DELETE("/:photoId", pc.photoController.DeletePhoto) // Rute untuk menghapus foto berdasarkan ID
 }
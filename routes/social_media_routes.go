@@ -26,5 +26,6 @@ func (smc *SocialMediaRouteController) SocialMediaRoute(rg *gin.RouterGroup) {
 	router.GET("", smc.socialMediaController.GetSocialMedias)                     // Rute untuk mendapatkan semua media sosial
 	router.GET("/:socialMediaId", smc.socialMediaController.GetSocialMediaByID)   // Rute untuk mendapatkan media sosial berdasarkan ID
 	router.PUT("/:socialMediaId", smc.socialMediaController.UpdateSocialMedia)    // Rute untuk memperbarui media sosial berdasarkan ID
+	router.PATCH("/:socialMediaId", smc.socialMediaController.PatchSocialMedia)   // Rute untuk memperbarui sebagian field media sosial berdasarkan ID
 	router.DELETE("/:socialMediaId", smc.socialMediaController.DeleteSocialMedia) // Rute untuk menghapus media sosial berdasarkan ID
 }
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoryRouteController mengelola rute yang terkait dengan story.
+type StoryRouteController struct {
+	storyController controllers.StoryController // Kontroler untuk story
+}
+
+// NewRouteStoryController membuat instance baru dari StoryRouteController.
+func NewRouteStoryController(storyController controllers.StoryController) StoryRouteController {
+	return StoryRouteController{storyController}
+}
+
+// StoryRoute menentukan rute yang terkait dengan story.
+func (sc *StoryRouteController) StoryRoute(rg *gin.RouterGroup) {
+	router := rg.Group("stories")
+	router.Use(middleware.UserExtractor())
+
+	router.POST("", sc.storyController.CreateStory)                     // Rute untuk membuat story baru
+	router.GET("/tray", sc.storyController.GetStoryTray)                // Rute untuk mendapatkan story dari pengguna yang diikuti
+	router.GET("/:storyId", sc.storyController.GetStoryByID)            // Rute untuk melihat story berdasarkan ID
+	router.GET("/:storyId/viewers", sc.storyController.GetStoryViewers) // Rute untuk mendapatkan daftar penonton story
+	router.DELETE("/:storyId", sc.storyController.DeleteStory)          // Rute untuk menghapus story berdasarkan ID
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamRouteController mengelola rute yang terkait dengan stream event real-time.
+type StreamRouteController struct {
+	streamController controllers.StreamController // Kontroler untuk stream event
+}
+
+// NewRouteStreamController membuat instance baru dari StreamRouteController.
+func NewRouteStreamController(streamController controllers.StreamController) StreamRouteController {
+	return StreamRouteController{streamController}
+}
+
+// StreamRoute menentukan rute yang terkait dengan stream event real-time.
+func (sc *StreamRouteController) StreamRoute(rg *gin.RouterGroup) {
+	router := rg.Group("stream")
+	router.Use(middleware.UserExtractor())
+
+	router.GET("", sc.streamController.Stream) // Rute untuk membuka stream Server-Sent Events
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashRouteController mengelola rute yang terkait dengan tempat sampah dan pemulihan konten.
+type TrashRouteController struct {
+	trashController controllers.TrashController // Kontroler untuk tempat sampah
+}
+
+// NewRouteTrashController membuat instance baru dari TrashRouteController.
+func NewRouteTrashController(trashController controllers.TrashController) TrashRouteController {
+	return TrashRouteController{trashController}
+}
+
+// TrashRoute menentukan rute yang terkait dengan tempat sampah dan pemulihan konten.
+func (tc *TrashRouteController) TrashRoute(rg *gin.RouterGroup) {
+	users := rg.Group("users")
+	users.Use(middleware.UserExtractor())
+
+	users.GET("/me/trash", tc.trashController.GetTrash) // Rute untuk mendapatkan konten di tempat sampah
+
+	photos := rg.Group("photos")
+	photos.Use(middleware.UserExtractor())
+
+	photos.POST("/:photoId/restore", tc.trashController.RestorePhoto) // Rute untuk memulihkan foto dari tempat sampah
+
+	comments := rg.Group("comments")
+	comments.Use(middleware.UserExtractor())
+
+	comments.POST("/:commentId/restore", tc.trashController.RestoreComment) // Rute untuk memulihkan komentar dari tempat sampah
+
+	socialMedias := rg.Group("socialmedias")
+	socialMedias.Use(middleware.UserExtractor())
+
+	socialMedias.POST("/:socialMediaId/restore", tc.trashController.RestoreSocialMedia) // Rute untuk memulihkan media sosial dari tempat sampah
+}
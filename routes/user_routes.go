@@ -21,8 +21,15 @@ func NewRouteUserController(userController controllers.UserController) UserRoute
 func (uc *UserRouteController) UserRoute(rg *gin.RouterGroup) {
 	router := rg.Group("users")
 
+	// Mengatur rute untuk mendapatkan profil pengguna saat ini dan profil pengguna lain
+	router.GET("/me", middleware.UserExtractor(), uc.userController.GetMe)
+	router.GET("/:username", middleware.UserExtractor(), uc.userController.GetUserByUsername)
+	// Mengatur rute untuk memperbarui sebagian profil pengguna saat ini serta mengunggah dan menghapus avatar
+	router.PATCH("/me", middleware.UserExtractor(), middleware.DenyImpersonation(), uc.userController.PatchMe)
+	router.POST("/me/avatar", middleware.UserExtractor(), uc.userController.UploadAvatar)
+	router.DELETE("/me/avatar", middleware.UserExtractor(), uc.userController.DeleteAvatar)
 	// Mengatur rute untuk memperbarui informasi pengguna
-	router.PUT("", middleware.UserExtractor(), uc.userController.UpdateMe)
+	router.PUT("", middleware.UserExtractor(), middleware.DenyImpersonation(), uc.userController.UpdateMe)
 	// Mengatur rute untuk menghapus pengguna
-	router.DELETE("", middleware.UserExtractor(), uc.userController.DeleteMe)
+	router.DELETE("", middleware.UserExtractor(), middleware.DenyImpersonation(), uc.userController.DeleteMe)
 }
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"mygram-final-project/controllers"
+	"mygram-final-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRouteController mengelola rute yang terkait dengan webhook.
+type WebhookRouteController struct {
+	webhookController controllers.WebhookController // Kontroler untuk webhook
+}
+
+// NewRouteWebhookController membuat instance baru dari WebhookRouteController.
+func NewRouteWebhookController(webhookController controllers.WebhookController) WebhookRouteController {
+	return WebhookRouteController{webhookController}
+}
+
+// WebhookRoute menentukan rute yang terkait dengan webhook.
+func (wc *WebhookRouteController) WebhookRoute(rg *gin.RouterGroup) {
+	router := rg.Group("webhooks")
+	router.Use(middleware.UserExtractor(), middleware.DenyImpersonation())
+
+	router.POST("", wc.webhookController.CreateWebhook)                      // Rute untuk mendaftarkan webhook baru
+	router.GET("", wc.webhookController.GetWebhooks)                         // Rute untuk mendapatkan daftar webhook
+	router.GET("/:webhookId", wc.webhookController.GetWebhookByID)           // Rute untuk mendapatkan webhook berdasarkan ID
+	router.PUT("/:webhookId", wc.webhookController.UpdateWebhook)            // Rute untuk memperbarui webhook berdasarkan ID
+	router.DELETE("/:webhookId", wc.webhookController.DeleteWebhook)         // Rute untuk menghapus webhook berdasarkan ID
+	router.GET("/:webhookId/deliveries", wc.webhookController.GetDeliveries) // Rute untuk mendapatkan log pengiriman webhook
+	router.POST("/:webhookId/ping", wc.webhookController.PingWebhook)        // Rute untuk mengirim event uji coba
+}
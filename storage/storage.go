@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound dikembalikan saat berkas dengan key yang diminta tidak ada.
+var ErrNotFound = errors.New("berkas tidak ditemukan")
+
+// ErrInvalidKey dikembalikan saat key berkas kosong atau keluar dari direktori penyimpanan.
+var ErrInvalidKey = errors.New("key berkas tidak valid")
+
+// Store menyimpan berkas biner berdasarkan key berbentuk path relatif, misalnya "exports/12/3.zip".
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Local menyimpan berkas di direktori lokal. Cocok untuk satu server atau beberapa server dengan volume bersama.
+type Local struct {
+	Dir string // Direktori akar penyimpanan
+}
+
+// NewLocal membuat penyimpanan lokal di dir dan membuat direktorinya jika belum ada.
+func NewLocal(dir string) (*Local, error) {
+	if dir == "" {
+		dir = "uploads"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Local{Dir: dir}, nil
+}
+
+// Put menyimpan isi r ke key. Berkas ditulis ke berkas sementara lalu dipindahkan, sehingga pembaca tidak pernah melihat berkas setengah jadi.
+func (l *Local) Put(_ context.Context, key string, r io.Reader) (int64, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return size, os.Rename(tmp.Name(), path)
+}
+
+// Open membuka berkas key untuk dibaca.
+func (l *Local) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Delete menghapus berkas key. Berkas yang sudah tidak ada tidak dianggap error.
+func (l *Local) Delete(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path mengubah key menjadi path di dalam Dir dan menolak key yang keluar dari Dir
+func (l *Local) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(l.Dir, cleaned), nil
+}
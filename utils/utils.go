@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"regexp"
 	"time"
+	"unicode/utf8"
 
 	"github.com/golang-jwt/jwt"
 	"golang.org/x/crypto/bcrypt"
@@ -36,6 +37,11 @@ func VerifyPassword(hashedPassword string, candidatePassword string) error {
 
 // CreateToken membuat token JWT dengan menggunakan private key yang diberikan.
 func CreateToken(ttl time.Duration, payload interface{}, privateKey string) (string, error) {
+	return CreateTokenWithClaims(ttl, payload, nil, privateKey)
+}
+
+// CreateTokenWithClaims membuat token JWT seperti CreateToken dengan klaim tambahan, misalnya penanda sesi impersonasi.
+func CreateTokenWithClaims(ttl time.Duration, payload interface{}, extra map[string]interface{}, privateKey string) (string, error) {
 	// Mendecode private key dari base64
 	decodedPrivateKey, err := base64.StdEncoding.DecodeString(privateKey)
 	if err != nil {
@@ -51,6 +57,9 @@ func CreateToken(ttl time.Duration, payload interface{}, privateKey string) (str
 
 	// Menyiapkan klaim token JWT
 	claims := make(jwt.MapClaims)
+	for name, value := range extra {
+		claims[name] = value
+	}
 	claims["sub"] = payload
 	claims["exp"] = now.Add(ttl).Unix()
 	claims["iat"] = now.Unix()
@@ -67,6 +76,15 @@ func CreateToken(ttl time.Duration, payload interface{}, privateKey string) (str
 
 // ValidateToken memvalidasi token JWT menggunakan public key yang diberikan.
 func ValidateToken(token string, publicKey string) (interface{}, error) {
+	claims, err := ParseToken(token, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return claims["sub"], nil
+}
+
+// ParseToken memvalidasi token JWT seperti ValidateToken lalu mengembalikan seluruh klaimnya.
+func ParseToken(token string, publicKey string) (map[string]interface{}, error) {
 	// Mendecode public key dari base64
 	decodedPublicKey, err := base64.StdEncoding.DecodeString(publicKey)
 	if err != nil {
@@ -76,7 +94,7 @@ func ValidateToken(token string, publicKey string) (interface{}, error) {
 	// Parse public key RSA dari PEM
 	key, err := jwt.ParseRSAPublicKeyFromPEM(decodedPublicKey)
 	if err != nil {
-		return "", fmt.Errorf("validate: parse key: %w", err)
+		return nil, fmt.Errorf("validate: parse key: %w", err)
 	}
 
 	// Memvalidasi token JWT dengan menggunakan public key yang sudah diparse
@@ -97,7 +115,7 @@ func ValidateToken(token string, publicKey string) (interface{}, error) {
 		return nil, fmt.Errorf("validate: invalid token")
 	}
 
-	return claims["sub"], nil
+	return claims, nil
 }
 
 // IsValidURL memeriksa apakah string URL yang diberikan valid atau tidak.
@@ -111,3 +129,31 @@ func IsValidURL(urlString string) bool {
 	// Jika tidak ada kesalahan, URL dianggap valid
 	return true
 }
+
+// MentionToken adalah hasil ekstraksi satu @mention dari sebuah teks.
+type MentionToken struct {
+	Username string // Username tanpa awalan @
+	Offset   int    // Posisi karakter (rune) @ di dalam teks
+	Length   int    // Panjang mention dalam karakter, termasuk @
+}
+
+// mentionRegex mencocokkan @username yang diawali awal teks atau karakter selain huruf/angka
+var mentionRegex = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@])(@([A-Za-z0-9_][A-Za-z0-9_.]{0,49}))`)
+
+// ExtractMentions mengambil semua @username dari teks beserta posisinya.
+func ExtractMentions(text string) []MentionToken {
+	var mentions []MentionToken
+	for _, match := range mentionRegex.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2], match[3]
+		// Titik di akhir dianggap tanda baca, bukan bagian dari username
+		for end > start && text[end-1] == '.' {
+			end--
+		}
+		mentions = append(mentions, MentionToken{
+			Username: text[start+1 : end],
+			Offset:   utf8.RuneCountInString(text[:start]),
+			Length:   utf8.RuneCountInString(text[start:end]),
+		})
+	}
+	return mentions
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []MentionToken
+	}{
+		{"tanpa mention", "foto pantai", nil},
+		{"di awal teks", "@budi lihat ini", []MentionToken{{"budi", 0, 5}}},
+		{"di tengah teks", "halo @siti_1!", []MentionToken{{"siti_1", 5, 7}}},
+		{"beberapa mention", "@a dan @b.c", []MentionToken{{"a", 0, 2}, {"b.c", 7, 4}}},
+		{"titik di akhir adalah tanda baca", "terima kasih @budi.", []MentionToken{{"budi", 13, 5}}},
+		{"setelah tanda kurung", "(@budi)", []MentionToken{{"budi", 1, 5}}},
+		{"alamat email", "kirim ke budi@example.com", nil},
+		{"@ ganda", "@@budi", nil},
+		{"@ saja", "halo @ semua", nil},
+		{"offset dalam karakter", "café @budi", []MentionToken{{"budi", 5, 5}}},
+		{"emoji sebelum mention", "🎉@budi", []MentionToken{{"budi", 1, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractMentions(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractMentions(%q) = %+v, ingin %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
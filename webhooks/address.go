@@ -0,0 +1,216 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidURL dikembalikan saat URL webhook tidak dapat diurai atau bukan http/https.
+var ErrInvalidURL = errors.New("URL webhook tidak valid")
+
+// ErrPrivateAddress dikembalikan saat host webhook mengarah ke alamat yang bukan alamat publik, misalnya loopback,
+// jaringan privat, atau link-local. Webhook tidak boleh dipakai untuk mengakses jaringan internal server.
+var ErrPrivateAddress = errors.New("URL webhook harus mengarah ke alamat publik")
+
+// blockedNetworks adalah rentang alamat khusus yang tidak tercakup oleh pemeriksaan bawaan net.IP
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",       // Jaringan "ini"
+	"100.64.0.0/10",   // Carrier-grade NAT
+	"192.0.0.0/24",    // Penugasan protokol IETF
+	"192.0.2.0/24",    // Dokumentasi
+	"198.18.0.0/15",   // Pengujian benchmark
+	"198.51.100.0/24", // Dokumentasi
+	"203.0.113.0/24",  // Dokumentasi
+	"240.0.0.0/4",     // Dicadangkan, termasuk broadcast
+	"64:ff9b::/96",    // Translasi NAT64 ke alamat IPv4
+	"64:ff9b:1::/48",  // Translasi NAT64 lokal
+	"2001:db8::/32",   // Dokumentasi
+)
+
+// mustParseCIDRs mengurai daftar CIDR dan panic jika ada yang tidak valid
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// IsPublicIP menentukan apakah ip adalah alamat publik yang boleh dihubungi webhook
+func IsPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddressPolicy menentukan alamat yang boleh dihubungi webhook. Policy kosong, termasuk nil, hanya mengizinkan alamat
+// publik. Host pada daftar izin boleh mengarah ke alamat non-publik, misalnya penerima lokal saat pengembangan.
+type AddressPolicy struct {
+	hosts    map[string]bool // Nama host yang diizinkan, huruf kecil
+	networks []*net.IPNet    // Rentang alamat yang diizinkan
+}
+
+// NewAddressPolicy membuat AddressPolicy dari daftar izin allowed berisi nama host, alamat IP, atau rentang CIDR,
+// misalnya "localhost", "127.0.0.1", atau "10.0.0.0/8".
+func NewAddressPolicy(allowed []string) (*AddressPolicy, error) {
+	policy := &AddressPolicy{hosts: map[string]bool{}}
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case strings.Contains(entry, "/"):
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("webhooks: rentang alamat %q tidak valid: %w", entry, err)
+			}
+			policy.networks = append(policy.networks, network)
+		case net.ParseIP(entry) != nil:
+			ip := net.ParseIP(entry)
+			bits := 128
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+			policy.networks = append(policy.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		default:
+			policy.hosts[entry] = true
+		}
+	}
+	return policy, nil
+}
+
+// IsEmpty menentukan apakah policy hanya mengizinkan alamat publik
+func (p *AddressPolicy) IsEmpty() bool {
+	return p == nil || (len(p.hosts) == 0 && len(p.networks) == 0)
+}
+
+// allowsHost menentukan apakah host ada pada daftar izin
+func (p *AddressPolicy) allowsHost(host string) bool {
+	return p != nil && p.hosts[strings.ToLower(host)]
+}
+
+// allowsIP menentukan apakah ip boleh dihubungi, yaitu alamat publik atau alamat pada daftar izin
+func (p *AddressPolicy) allowsIP(ip net.IP) bool {
+	if IsPublicIP(ip) {
+		return true
+	}
+	if p == nil {
+		return false
+	}
+	for _, network := range p.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve mencari alamat IP host dan memastikan seluruhnya boleh dihubungi
+func (p *AddressPolicy) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		if !p.allowsIP(ip) {
+			return nil, ErrPrivateAddress
+		}
+		return []net.IP{ip}, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		// Host yang salah satu alamatnya privat ditolak agar tidak bergantung pada alamat mana yang dipilih saat dial
+		if !p.allowsHost(host) && !p.allowsIP(addr.IP) {
+			return nil, ErrPrivateAddress
+		}
+		ips = append(ips, addr.IP)
+	}
+	if len(ips) == 0 {
+		return nil, ErrPrivateAddress
+	}
+	return ips, nil
+}
+
+// ValidateURL memastikan rawURL adalah URL http/https yang host-nya hanya mengarah ke alamat yang diizinkan policy.
+// Dipakai saat webhook didaftarkan. Alamat diperiksa ulang setiap kali webhook dikirim karena DNS dapat berubah.
+func (p *AddressPolicy) ValidateURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" || parsed.User != nil {
+		return ErrInvalidURL
+	}
+	_, err = p.resolve(ctx, parsed.Hostname())
+	return err
+}
+
+// ValidateURL memastikan rawURL adalah URL http/https yang host-nya hanya mengarah ke alamat publik.
+func ValidateURL(ctx context.Context, rawURL string) error {
+	return (*AddressPolicy)(nil).ValidateURL(ctx, rawURL)
+}
+
+// dialAllowed membuka koneksi hanya ke alamat yang diizinkan policy. Host di-resolve sekali lalu koneksi dibuat langsung
+// ke IP yang sudah diperiksa, sehingga DNS yang berubah di antara pemeriksaan dan koneksi tidak dapat mengarahkan webhook
+// ke jaringan internal.
+func dialAllowed(dialer *net.Dialer, policy *AddressPolicy) func(ctx context.Context, network string, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := policy.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, ip := range ips {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// newClient membuat klien HTTP untuk pengiriman webhook yang hanya terhubung ke alamat yang diizinkan policy, tidak memakai
+// proxy dari environment, dan tidak mengikuti redirect
+func newClient(timeout time.Duration, policy *AddressPolicy) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		DialContext:           dialAllowed(dialer, policy),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   timeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,215 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// Nilai default untuk Dispatcher.
+const (
+	DefaultMaxAttempts  = 8                // Jumlah percobaan sebelum pengiriman dianggap gagal
+	DefaultBaseBackoff  = 30 * time.Second // Jeda percobaan ulang pertama, berlipat dua setiap percobaan
+	DefaultMaxBackoff   = 6 * time.Hour    // Jeda percobaan ulang maksimal
+	DefaultPollInterval = 5 * time.Second  // Jeda pemeriksaan pengiriman yang jatuh tempo
+	DefaultBatchSize    = 20               // Jumlah pengiriman yang diproses per pemeriksaan
+	claimLease          = 2 * time.Minute  // Lama pengiriman dikunci oleh satu dispatcher
+)
+
+// Dispatcher mengirim pengiriman webhook yang tersimpan di database dengan percobaan ulang berjeda eksponensial.
+// Beberapa dispatcher dapat berjalan bersamaan di instance berbeda karena pengiriman diklaim dengan FOR UPDATE SKIP LOCKED.
+type Dispatcher struct {
+	DB           *gorm.DB
+	Addresses    *AddressPolicy   // Alamat yang boleh dihubungi, secara default hanya alamat publik
+	Client       *http.Client     // Klien HTTP untuk mengirim webhook, hanya terhubung ke alamat yang diizinkan Addresses
+	Now          func() time.Time // Sumber waktu, dapat diganti saat pengujian
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// NewDispatcher membuat instance baru dari Dispatcher dengan nilai default.
+func NewDispatcher(DB *gorm.DB) *Dispatcher {
+	addresses := &AddressPolicy{}
+	return &Dispatcher{
+		DB:           DB,
+		Addresses:    addresses,
+		Client:       newClient(10*time.Second, addresses),
+		Now:          time.Now,
+		MaxAttempts:  DefaultMaxAttempts,
+		BaseBackoff:  DefaultBaseBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		PollInterval: DefaultPollInterval,
+		BatchSize:    DefaultBatchSize,
+	}
+}
+
+// AllowHosts mengizinkan webhook dikirim ke host, alamat IP, atau rentang CIDR pada allowed walaupun bukan alamat publik,
+// misalnya penerima lokal saat pengembangan. Dipanggil sebelum dispatcher berjalan, dan berlaku juga untuk ValidateURL
+// pada Addresses.
+func (d *Dispatcher) AllowHosts(allowed []string) error {
+	policy, err := NewAddressPolicy(allowed)
+	if err != nil {
+		return err
+	}
+	*d.Addresses = *policy
+	if !policy.IsEmpty() {
+		log.Printf("webhooks: alamat non-publik diizinkan untuk %v, jangan dipakai di produksi", allowed)
+	}
+	return nil
+}
+
+// Run memproses pengiriman yang jatuh tempo secara berkala sampai ctx dibatalkan.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.ProcessDue(ctx); err != nil {
+			log.Printf("webhooks: gagal memproses pengiriman: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue mengklaim dan mengirim satu batch pengiriman yang jatuh tempo, lalu mengembalikan jumlah yang diproses.
+func (d *Dispatcher) ProcessDue(ctx context.Context) (int, error) {
+	now := d.Now()
+
+	var deliveries []models.WebhookDelivery
+	err := d.DB.Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(claimLease), models.WebhookDeliveryPending, now, d.BatchSize).Scan(&deliveries).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := d.Deliver(ctx, delivery); err != nil {
+			log.Printf("webhooks: pengiriman %d gagal: %v", delivery.ID, err)
+		}
+	}
+	return len(deliveries), nil
+}
+
+// Deliver mengirim satu pengiriman webhook, mencatat percobaannya, lalu memperbarui status dan jadwal percobaan berikutnya.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery models.WebhookDelivery) (models.WebhookDeliveryAttempt, error) {
+	var subscription models.WebhookSubscription
+	if err := d.DB.First(&subscription, delivery.SubscriptionID).Error; err != nil {
+		return models.WebhookDeliveryAttempt{}, err
+	}
+
+	start := d.Now()
+	status, sendErr := d.send(ctx, subscription, delivery)
+	attempt := models.WebhookDeliveryAttempt{
+		DeliveryID:     delivery.ID,
+		ResponseStatus: status,
+		DurationMs:     d.Now().Sub(start).Milliseconds(),
+		CreatedAt:      start,
+	}
+	if sendErr == nil && (status < 200 || status >= 300) {
+		sendErr = fmt.Errorf("penerima membalas dengan status %d", status)
+	}
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+	}
+
+	updates := d.deliveryUpdates(delivery, attempt, sendErr)
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).UpdateColumns(updates).Error
+	})
+	if err != nil {
+		return attempt, err
+	}
+	return attempt, sendErr
+}
+
+// deliveryUpdates menentukan perubahan pengiriman setelah satu percobaan: berhasil, gagal permanen setelah MaxAttempts
+// percobaan, atau dijadwalkan ulang dengan backoff
+func (d *Dispatcher) deliveryUpdates(delivery models.WebhookDelivery, attempt models.WebhookDeliveryAttempt, sendErr error) map[string]interface{} {
+	attempts := delivery.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts, "updated_at": d.Now(), "last_error": attempt.Error}
+	switch {
+	case sendErr == nil:
+		updates["status"] = models.WebhookDeliverySucceeded
+		updates["delivered_at"] = d.Now()
+	case attempts >= d.MaxAttempts:
+		updates["status"] = models.WebhookDeliveryFailed
+	default:
+		updates["status"] = models.WebhookDeliveryPending
+		updates["next_attempt_at"] = d.Now().Add(d.backoff(attempts))
+	}
+	return updates
+}
+
+// send melakukan request HTTP ke penerima webhook dan mengembalikan kode status respons. Isi respons tidak disimpan
+// agar webhook tidak dapat dipakai untuk membaca respons dari server lain.
+func (d *Dispatcher) send(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := d.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MyGram-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		// Error dari klien dapat memuat detail jaringan, sehingga hanya penyebabnya yang disimpan
+		if errors.Is(err, ErrPrivateAddress) {
+			return 0, ErrPrivateAddress
+		}
+		return 0, errors.New("gagal menghubungi penerima webhook")
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	return resp.StatusCode, nil
+}
+
+// backoff menghitung jeda sebelum percobaan ke-(attempts+1)
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= d.MaxBackoff {
+			return d.MaxBackoff
+		}
+	}
+	return wait
+}
@@ -0,0 +1,253 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"mygram-final-project/models"
+)
+
+// testNow adalah waktu tetap yang dipakai dispatcher saat pengujian
+var testNow = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// newTestDispatcher membuat Dispatcher dengan waktu tetap yang boleh mengirim ke penerima lokal
+func newTestDispatcher(t *testing.T, DB *gorm.DB) *Dispatcher {
+	t.Helper()
+	d := NewDispatcher(DB)
+	d.Now = func() time.Time { return testNow }
+	if err := d.AllowHosts([]string{"127.0.0.1", "::1"}); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// receiver adalah penerima webhook lokal yang mencatat request terakhir
+type receiver struct {
+	server   *httptest.Server
+	requests atomic.Int32
+	header   http.Header
+	body     []byte
+}
+
+// newReceiver menjalankan penerima webhook yang membalas dengan status dari statuses secara berurutan.
+// Status terakhir dipakai untuk request berikutnya.
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+	r := &receiver{}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := int(r.requests.Add(1))
+		r.header = req.Header.Clone()
+		r.body, _ = io.ReadAll(req.Body)
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+		io.WriteString(w, "rahasia internal penerima")
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func TestSendSignsRequest(t *testing.T) {
+	r := newReceiver(t, http.StatusNoContent)
+	d := newTestDispatcher(t, nil)
+
+	subscription := models.WebhookSubscription{URL: r.server.URL, Secret: "rahasia"}
+	delivery := models.WebhookDelivery{ID: 42, Event: "photo.created", Payload: `{"id":42,"event":"photo.created"}`}
+	status, err := d.send(context.Background(), subscription, delivery)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("send = %d, %v, ingin 204 tanpa error", status, err)
+	}
+
+	if got := string(r.body); got != delivery.Payload {
+		t.Errorf("isi request = %s, ingin %s", got, delivery.Payload)
+	}
+	if got := r.header.Get(HeaderEvent); got != "photo.created" {
+		t.Errorf("%s = %q", HeaderEvent, got)
+	}
+	if got := r.header.Get(HeaderDelivery); got != "42" {
+		t.Errorf("%s = %q", HeaderDelivery, got)
+	}
+	timestamp, err := strconv.ParseInt(r.header.Get(HeaderTimestamp), 10, 64)
+	if err != nil || timestamp != testNow.Unix() {
+		t.Fatalf("%s = %q, ingin %d", HeaderTimestamp, r.header.Get(HeaderTimestamp), testNow.Unix())
+	}
+	if !Verify("rahasia", timestamp, r.body, r.header.Get(HeaderSignature)) {
+		t.Errorf("tanda tangan %q tidak valid", r.header.Get(HeaderSignature))
+	}
+}
+
+func TestSendRefusesPrivateAddressByDefault(t *testing.T) {
+	r := newReceiver(t, http.StatusOK)
+	d := NewDispatcher(nil)
+
+	_, err := d.send(context.Background(), models.WebhookSubscription{URL: r.server.URL, Secret: "rahasia"}, models.WebhookDelivery{Payload: "{}"})
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("send = %v, ingin %v", err, ErrPrivateAddress)
+	}
+	if r.requests.Load() != 0 {
+		t.Errorf("penerima lokal menerima %d request", r.requests.Load())
+	}
+}
+
+func TestSendDoesNotFollowRedirects(t *testing.T) {
+	target := newReceiver(t, http.StatusOK)
+	redirect := httptest.NewServer(http.RedirectHandler(target.server.URL, http.StatusFound))
+	t.Cleanup(redirect.Close)
+	d := newTestDispatcher(t, nil)
+
+	status, err := d.send(context.Background(), models.WebhookSubscription{URL: redirect.URL, Secret: "rahasia"}, models.WebhookDelivery{Payload: "{}"})
+	if err != nil || status != http.StatusFound {
+		t.Fatalf("send = %d, %v, ingin 302 tanpa error", status, err)
+	}
+	if target.requests.Load() != 0 {
+		t.Errorf("redirect diikuti ke %s", target.server.URL)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(nil)
+	d.BaseBackoff = 30 * time.Second
+	d.MaxBackoff = 5 * time.Minute
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{20, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, ingin %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDeliveryUpdates(t *testing.T) {
+	d := newTestDispatcher(t, nil)
+	d.MaxAttempts = 3
+	sendErr := errors.New("penerima membalas dengan status 500")
+
+	tests := []struct {
+		name        string
+		attempts    int
+		sendErr     error
+		wantStatus  string
+		wantNext    time.Time
+		wantDeliver bool
+	}{
+		{"berhasil", 0, nil, models.WebhookDeliverySucceeded, time.Time{}, true},
+		{"gagal pertama dijadwalkan ulang", 0, sendErr, models.WebhookDeliveryPending, testNow.Add(d.BaseBackoff), false},
+		{"gagal kedua dengan jeda dua kali lipat", 1, sendErr, models.WebhookDeliveryPending, testNow.Add(2 * d.BaseBackoff), false},
+		{"gagal pada percobaan terakhir", 2, sendErr, models.WebhookDeliveryFailed, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates := d.deliveryUpdates(models.WebhookDelivery{Attempts: tt.attempts}, models.WebhookDeliveryAttempt{}, tt.sendErr)
+			if updates["attempts"] != tt.attempts+1 {
+				t.Errorf("attempts = %v, ingin %d", updates["attempts"], tt.attempts+1)
+			}
+			if updates["status"] != tt.wantStatus {
+				t.Errorf("status = %v, ingin %s", updates["status"], tt.wantStatus)
+			}
+			next, scheduled := updates["next_attempt_at"]
+			if scheduled != !tt.wantNext.IsZero() || (scheduled && next != tt.wantNext) {
+				t.Errorf("next_attempt_at = %v, ingin %v", next, tt.wantNext)
+			}
+			if _, delivered := updates["delivered_at"]; delivered != tt.wantDeliver {
+				t.Errorf("delivered_at diisi = %v, ingin %v", delivered, tt.wantDeliver)
+			}
+		})
+	}
+}
+
+// testDB membuka basis data PostgreSQL dari TEST_DATABASE_URL di dalam transaksi yang dibatalkan setelah pengujian.
+// Pengujian dilewati jika TEST_DATABASE_URL tidak diatur.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL tidak diatur")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.WebhookDeliveryAttempt{}); err != nil {
+		t.Fatal(err)
+	}
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+func TestDeliverRecordsAttemptsAndRetries(t *testing.T) {
+	tx := testDB(t)
+	r := newReceiver(t, http.StatusInternalServerError, http.StatusOK)
+	d := newTestDispatcher(t, tx)
+
+	user := models.User{Username: "penerima", Email: "penerima@example.com", Password: "-"}
+	if err := tx.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	subscription := models.WebhookSubscription{UserID: user.ID, URL: r.server.URL, Secret: "rahasia", Events: models.WebhookEventPing, Active: true}
+	if err := tx.Create(&subscription).Error; err != nil {
+		t.Fatal(err)
+	}
+	delivery, err := createDelivery(tx, subscription.ID, nil, models.WebhookEventPing, map[string]string{"pesan": "halo"}, testNow, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Percobaan pertama gagal dan dijadwalkan ulang dengan backoff
+	if _, err := d.Deliver(context.Background(), delivery); err == nil {
+		t.Fatal("percobaan pertama seharusnya gagal")
+	}
+	if err := tx.First(&delivery, delivery.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != models.WebhookDeliveryPending || delivery.Attempts != 1 || !delivery.NextAttemptAt.Equal(testNow.Add(d.BaseBackoff)) {
+		t.Fatalf("setelah gagal: status %s, attempts %d, next %v", delivery.Status, delivery.Attempts, delivery.NextAttemptAt)
+	}
+
+	// Percobaan kedua berhasil
+	if _, err := d.Deliver(context.Background(), delivery); err != nil {
+		t.Fatalf("percobaan kedua: %v", err)
+	}
+	if err := tx.First(&delivery, delivery.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != models.WebhookDeliverySucceeded || delivery.Attempts != 2 || delivery.DeliveredAt == nil {
+		t.Fatalf("setelah berhasil: status %s, attempts %d, delivered %v", delivery.Status, delivery.Attempts, delivery.DeliveredAt)
+	}
+
+	var attempts []models.WebhookDeliveryAttempt
+	if err := tx.Where("delivery_id = ?", delivery.ID).Order("id").Find(&attempts).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("jumlah percobaan tercatat = %d, ingin 2", len(attempts))
+	}
+	if attempts[0].ResponseStatus != http.StatusInternalServerError || attempts[0].Error == "" {
+		t.Errorf("percobaan pertama = %d %q", attempts[0].ResponseStatus, attempts[0].Error)
+	}
+	if attempts[1].ResponseStatus != http.StatusOK || attempts[1].Error != "" {
+		t.Errorf("percobaan kedua = %d %q", attempts[1].ResponseStatus, attempts[1].Error)
+	}
+	if r.requests.Load() != 2 {
+		t.Errorf("penerima menerima %d request, ingin 2", r.requests.Load())
+	}
+}
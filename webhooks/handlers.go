@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"mygram-final-project/events"
+	"mygram-final-project/models"
+)
+
+// Register mendaftarkan handler yang mengubah domain event menjadi pengiriman webhook.
+func Register(dispatcher *events.Dispatcher, db *gorm.DB) {
+	dispatcher.Register("webhooks", func(ctx context.Context, event models.OutboxEvent) error {
+		var data map[string]interface{}
+		if err := events.Decode(event, &data); err != nil {
+			return err
+		}
+
+		// Pemilik event menentukan langganan ber-cakupan user yang menerima webhook
+		ownerIDs := []int64{event.ActorID}
+		if photoUserID, ok := data["photo_user_id"].(float64); ok {
+			ownerIDs = append(ownerIDs, int64(photoUserID))
+		}
+		// Field internal tidak dikirim ke penerima webhook
+		delete(data, "photo_user_id")
+		delete(data, "parent_user_id")
+
+		return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return Enqueue(tx, event.ID, event.Type, ownerIDs, data)
+		})
+	}, events.PhotoCreated, events.CommentCreated, events.UserDeleted)
+}
@@ -0,0 +1,131 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"mygram-final-project/models"
+)
+
+// Header yang dikirim bersama setiap webhook.
+const (
+	HeaderEvent     = "X-MyGram-Event"     // Nama event
+	HeaderDelivery  = "X-MyGram-Delivery"  // ID pengiriman, sama untuk setiap percobaan ulang
+	HeaderTimestamp = "X-MyGram-Timestamp" // Waktu pengiriman dalam detik Unix
+	HeaderSignature = "X-MyGram-Signature" // Tanda tangan HMAC-SHA256 dengan format sha256=<hex>
+)
+
+// Envelope adalah isi payload JSON yang dikirim ke penerima webhook.
+type Envelope struct {
+	ID        int64       `json:"id"`         // ID pengiriman
+	Event     string      `json:"event"`      // Nama event
+	CreatedAt time.Time   `json:"created_at"` // Waktu event terjadi
+	Data      interface{} `json:"data"`       // Data event
+}
+
+// GenerateSecret membuat kunci acak untuk tanda tangan payload webhook.
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Sign menghitung tanda tangan HMAC-SHA256 atas "<timestamp>.<body>" dengan secret.
+// Penerima memverifikasi webhook dengan menghitung ulang nilai ini dari header X-MyGram-Timestamp dan isi request.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify memeriksa apakah signature cocok dengan payload. Disediakan untuk penerima webhook yang ditulis dalam Go.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Subscribes memeriksa apakah langganan mendaftarkan event tertentu.
+func Subscribes(subscription models.WebhookSubscription, event string) bool {
+	for _, name := range strings.Split(subscription.Events, ",") {
+		if name == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Enqueue menyimpan pengiriman webhook untuk semua langganan aktif yang mendaftarkan event.
+// Langganan ber-cakupan user hanya menerima event milik ownerIDs, sedangkan langganan ber-cakupan app menerima semua event.
+// Pemanggilan ulang dengan outboxEventID yang sama tidak membuat pengiriman ganda.
+func Enqueue(tx *gorm.DB, outboxEventID int64, event string, ownerIDs []int64, data interface{}) error {
+	var subscriptions []models.WebhookSubscription
+	query := tx.Where("active = ?", true)
+	if len(ownerIDs) > 0 {
+		query = query.Where("scope = ? OR user_id IN ?", models.WebhookScopeApp, ownerIDs)
+	} else {
+		query = query.Where("scope = ?", models.WebhookScopeApp)
+	}
+	if err := query.Find(&subscriptions).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		if !Subscribes(subscription, event) {
+			continue
+		}
+		if _, err := createDelivery(tx, subscription.ID, &outboxEventID, event, data, now, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createDelivery menyimpan satu pengiriman webhook berstatus pending beserta payload-nya.
+// Pengiriman baru diambil dispatcher mulai nextAttemptAt.
+func createDelivery(tx *gorm.DB, subscriptionID int64, outboxEventID *int64, event string, data interface{}, now time.Time, nextAttemptAt time.Time) (models.WebhookDelivery, error) {
+	delivery := models.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		OutboxEventID:  outboxEventID,
+		Event:          event,
+		Payload:        "{}",
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  nextAttemptAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return delivery, result.Error
+	}
+
+	// Payload memuat ID pengiriman sehingga baru dapat disusun setelah baris dibuat
+	payload, err := json.Marshal(Envelope{ID: delivery.ID, Event: event, CreatedAt: now, Data: data})
+	if err != nil {
+		return delivery, err
+	}
+	delivery.Payload = string(payload)
+	return delivery, tx.Model(&delivery).UpdateColumn("payload", delivery.Payload).Error
+}
+
+// CreatePing menyimpan pengiriman event webhook.ping untuk sebuah langganan. Pengiriman dibuat dalam keadaan sudah diklaim
+// selama claimLease sehingga dispatcher tidak ikut mengirimnya saat pemanggil mengirim ping secara langsung.
+func CreatePing(tx *gorm.DB, subscription models.WebhookSubscription) (models.WebhookDelivery, error) {
+	now := time.Now()
+	return createDelivery(tx, subscription.ID, nil, models.WebhookEventPing, map[string]interface{}{
+		"subscription_id": subscription.ID,
+		"message":         "Ping dari MyGram",
+	}, now, now.Add(claimLease))
+}
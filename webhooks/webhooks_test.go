@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":1,"event":"photo.created"}`)
+	signature := Sign("rahasia", 1700000000, body)
+	if !strings.HasPrefix(signature, "sha256=") || len(signature) != len("sha256=")+64 {
+		t.Fatalf("format tanda tangan tidak sesuai: %q", signature)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"cocok", "rahasia", 1700000000, body, signature, true},
+		{"secret berbeda", "rahasia-lain", 1700000000, body, signature, false},
+		{"timestamp berbeda", "rahasia", 1700000001, body, signature, false},
+		{"isi berubah", "rahasia", 1700000000, []byte(`{"id":2,"event":"photo.created"}`), signature, false},
+		{"tanpa awalan sha256=", "rahasia", 1700000000, body, strings.TrimPrefix(signature, "sha256="), false},
+		{"tanda tangan kosong", "rahasia", 1700000000, body, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.secret, tt.timestamp, tt.body, tt.signature); got != tt.want {
+				t.Errorf("Verify = %v, ingin %v", got, tt.want)
+			}
+		})
+	}
+}
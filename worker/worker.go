@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"mygram-final-project/background"
+	"mygram-final-project/initializers"
+	"mygram-final-project/mail"
+	"mygram-final-project/storage"
+)
+
+func init() {
+	config, err := initializers.LoadConfig(".")
+	if err != nil {
+		log.Fatal("Could not load environment variables", err)
+	}
+
+	initializers.ConnectDB(&config)
+}
+
+func main() {
+	config, err := initializers.LoadConfig(".")
+	if err != nil {
+		log.Fatal("Could not load environment variables", err)
+	}
+
+	// Hentikan worker dengan rapi saat menerima sinyal, setelah job yang sedang berjalan selesai
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := storage.NewLocal(config.StorageDir)
+	if err != nil {
+		log.Fatal("Could not initialize storage", err)
+	}
+
+	processes := background.New(initializers.DB, background.Options{
+		Concurrency:    config.WorkerConcurrency,
+		TrashRetention: config.TrashRetention,
+		Storage:        store,
+		ExportTTL:      config.ExportTTL,
+		Mailer:         mail.New(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.MailFrom),
+	})
+	if err := processes.Webhooks.AllowHosts(config.WebhookAllowedHosts); err != nil {
+		log.Fatal("Invalid WEBHOOK_ALLOWED_HOSTS", err)
+	}
+	processes.Run(ctx)
+	log.Println("Worker stopped")
+}